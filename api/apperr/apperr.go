@@ -0,0 +1,168 @@
+// Package apperr описывает ошибки предметной области. Хранилище возвращает их
+// вместо строк и ошибок драйвера базы данных, а HTTP-слой по виду ошибки
+// выбирает код ответа и отдает клиенту стабильный машиночитаемый код с
+// параметрами и сообщением на языке клиента.
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind - вид ошибки, по которому выбирается код ответа HTTP
+type Kind int
+
+const (
+	// KindInternal - непредвиденная ошибка, подробности которой клиенту не показываются
+	KindInternal Kind = iota
+	// KindValidation - запрос не прошел проверку
+	KindValidation
+	// KindUnauthorized - клиент не аутентифицирован
+	KindUnauthorized
+	// KindForbidden - у клиента нет прав на действие
+	KindForbidden
+	// KindNotFound - запись не существует или недоступна клиенту
+	KindNotFound
+	// KindConflict - действие противоречит текущему состоянию данных
+	KindConflict
+	// KindUnavailable - не отвечает внешний сервис, от которого зависит запрос
+	KindUnavailable
+)
+
+// Error - ошибка предметной области. Code не меняется между версиями API, по
+// нему клиент выбирает перевод, подставляя в него Params; Message - описание
+// на английском для разработчиков. Fields перечисляет ошибки в отдельных полях
+// запроса. Причина в Err попадает только в журнал.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Params  map[string]interface{}
+	Fields  []FieldError
+	Err     error
+}
+
+// FieldError - ошибка в одном поле запроса. Field - путь к полю в JSON,
+// например "attributes.area"; Message заполняется при формировании ответа.
+type FieldError struct {
+	Field   string                 `json:"field"`
+	Code    string                 `json:"code"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Message string                 `json:"message,omitempty"`
+}
+
+// ErrInternal - ответ на непредвиденную ошибку
+var ErrInternal = New(KindInternal, "internal_error", "Internal server error")
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is сравнивает ошибки по виду и коду, поэтому errors.Is находит ошибку-образец
+// и в ее копиях, созданных Wrap.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// Wrap возвращает копию ошибки с причиной cause
+func (e *Error) Wrap(cause error) *Error {
+	wrapped := *e
+	wrapped.Err = cause
+	return &wrapped
+}
+
+// With возвращает копию ошибки с параметром key для подстановки в сообщение
+func (e *Error) With(key string, value interface{}) *Error {
+	copied := *e
+	copied.Params = make(map[string]interface{}, len(e.Params)+1)
+	for k, v := range e.Params {
+		copied.Params[k] = v
+	}
+	copied.Params[key] = value
+	return &copied
+}
+
+// WithFields возвращает копию ошибки с ошибками в полях запроса
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	copied := *e
+	copied.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return &copied
+}
+
+// statuses сопоставляет виду ошибки код ответа HTTP
+var statuses = map[Kind]int{
+	KindValidation:   http.StatusBadRequest,
+	KindUnauthorized: http.StatusUnauthorized,
+	KindForbidden:    http.StatusForbidden,
+	KindNotFound:     http.StatusNotFound,
+	KindConflict:     http.StatusConflict,
+	KindUnavailable:  http.StatusBadGateway,
+}
+
+// Status возвращает код ответа HTTP для вида ошибки
+func (e *Error) Status() int {
+	if status, ok := statuses[e.Kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// New создает ошибку вида kind
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// NotFound создает ошибку об отсутствующей записи
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+// Forbidden создает ошибку о недостаточных правах
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+// Conflict создает ошибку о конфликте с текущим состоянием
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+// Validation создает ошибку о неверных входных данных
+func Validation(code, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+// Unauthorized создает ошибку об отсутствующей или неверной аутентификации
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+// Unavailable создает ошибку о недоступном внешнем сервисе
+func Unavailable(code, message string) *Error {
+	return New(KindUnavailable, code, message)
+}
+
+// As возвращает ошибку предметной области из цепочки err
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
+
+// Public возвращает ошибку, которую можно показать клиенту: ошибку
+// предметной области из цепочки err или ErrInternal, если ее нет или она
+// внутренняя.
+func Public(err error) *Error {
+	if appErr, ok := As(err); ok && appErr.Kind != KindInternal {
+		return appErr
+	}
+	return ErrInternal
+}
@@ -0,0 +1,114 @@
+package apperr
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"masterdom/api/utils"
+)
+
+// Сообщения лежат в messages/<язык>.json: в errors - по коду ошибки, в
+// fields - по коду ошибки в поле. Это шаблоны text/template, в которые
+// подставляются параметры ошибки, например {{.max}}.
+//
+//go:embed messages/*.json
+var messageFS embed.FS
+
+// catalog - разобранные шаблоны сообщений по языкам
+var catalog = loadCatalog()
+
+type messageFile struct {
+	Errors map[string]string `json:"errors"`
+	Fields map[string]string `json:"fields"`
+}
+
+type messages struct {
+	errors map[string]*template.Template
+	fields map[string]*template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"join": func(list interface{}, sep string) string {
+		switch v := list.(type) {
+		case []string:
+			return strings.Join(v, sep)
+		default:
+			return fmt.Sprint(v)
+		}
+	},
+}
+
+func loadCatalog() map[string]messages {
+	result := make(map[string]messages, len(utils.SupportedLocales))
+	for _, locale := range utils.SupportedLocales {
+		data, err := messageFS.ReadFile("messages/" + locale + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("apperr: no messages for %s: %v", locale, err))
+		}
+		var file messageFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			panic(fmt.Sprintf("apperr: failed to parse %s messages: %v", locale, err))
+		}
+		result[locale] = messages{
+			errors: parseTemplates(locale, file.Errors),
+			fields: parseTemplates(locale, file.Fields),
+		}
+	}
+	return result
+}
+
+func parseTemplates(locale string, texts map[string]string) map[string]*template.Template {
+	templates := make(map[string]*template.Template, len(texts))
+	for code, text := range texts {
+		templates[code] = template.Must(template.New(locale + ":" + code).Funcs(templateFuncs).Parse(text))
+	}
+	return templates
+}
+
+// Response - тело ответа с ошибкой. Error - сообщение на английском,
+// оставленное для совместимости со старыми клиентами; Message - сообщение на
+// языке клиента.
+type Response struct {
+	Code    string                 `json:"code"`
+	Error   string                 `json:"error"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Fields  []FieldError           `json:"fields,omitempty"`
+}
+
+// Response формирует тело ответа с сообщениями на языке locale. Если перевода
+// нет, используется английское описание ошибки.
+func (e *Error) Response(locale string) Response {
+	msgs, ok := catalog[locale]
+	if !ok {
+		msgs = catalog[utils.DefaultLocale]
+	}
+
+	resp := Response{
+		Code:    e.Code,
+		Error:   e.Message,
+		Message: render(msgs.errors[e.Code], e.Params, e.Message),
+		Params:  e.Params,
+	}
+	for _, field := range e.Fields {
+		field.Message = render(msgs.fields[field.Code], field.Params, field.Message)
+		resp.Fields = append(resp.Fields, field)
+	}
+	return resp
+}
+
+// render подставляет params в шаблон или возвращает fallback, если шаблона нет
+func render(tmpl *template.Template, params map[string]interface{}, fallback string) string {
+	if tmpl == nil {
+		return fallback
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return fallback
+	}
+	return buf.String()
+}
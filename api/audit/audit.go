@@ -0,0 +1,26 @@
+// Package audit передает сведения об инициаторе привилегированного действия
+// от HTTP-слоя к хранилищу, которое записывает их в журнал аудита
+// в той же транзакции, что и само изменение.
+package audit
+
+import "context"
+
+// Actor описывает, кто и откуда выполнил действие
+type Actor struct {
+	UserID    string
+	IP        string
+	UserAgent string
+}
+
+type actorKey struct{}
+
+// WithActor возвращает контекст, несущий инициатора действия.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает инициатора действия, если он был установлен.
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
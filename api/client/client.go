@@ -0,0 +1,146 @@
+// Package client - клиент API MasterDom для Go. Типы и методы операций
+// генерируются по документу OpenAPI (client_gen.go), здесь - транспорт,
+// аутентификация и разбор ошибок.
+package client
+
+//go:generate go run ../cmd/clientgen -o client_gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client выполняет запросы к API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	apiKey     string
+	language   string
+}
+
+// Option настраивает клиент
+type Option func(*Client)
+
+// WithHTTPClient задает HTTP-клиент для запросов
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithToken задает токен сессии (заголовок Authorization: Bearer)
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithAPIKey задает API-ключ (заголовок X-API-Key)
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithLanguage задает язык сообщений об ошибках (заголовок Accept-Language)
+func WithLanguage(language string) Option {
+	return func(c *Client) { c.language = language }
+}
+
+// New создает клиент для сервера с адресом baseURL, например
+// "https://masterdom.example.com"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error - ответ сервера с кодом ошибки
+type Error struct {
+	StatusCode int
+	Response   ErrorResponse
+}
+
+func (e *Error) Error() string {
+	message := e.Response.Message
+	if message == "" {
+		message = e.Response.Error
+	}
+	return fmt.Sprintf("masterdom: %d %s: %s", e.StatusCode, e.Response.Code, message)
+}
+
+// do выполняет запрос и декодирует ответ в out. Если out имеет тип *[]byte,
+// тело ответа сохраняется без разбора.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.language != "" {
+		req.Header.Set("Accept-Language", c.language)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(data, &apiErr.Response); err != nil {
+			apiErr.Response.Error = strings.TrimSpace(string(data))
+		}
+		return apiErr
+	}
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*out = data
+		return nil
+	default:
+		if len(data) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
+	}
+}
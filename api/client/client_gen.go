@@ -0,0 +1,1482 @@
+// Code generated by clientgen from the OpenAPI document. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// APIKey is the APIKey schema.
+type APIKey struct {
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	ID         string     `json:"id"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	LastUsedIP *string    `json:"lastUsedIp"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	Scopes     []string   `json:"scopes"`
+	UserID     string     `json:"userId"`
+}
+
+// APIKeyCreated is the APIKeyCreated schema.
+type APIKeyCreated struct {
+	APIKey APIKey `json:"apiKey"`
+	Key    string `json:"key"`
+}
+
+// ActionResult is the ActionResult schema.
+type ActionResult struct {
+	Message string `json:"message"`
+}
+
+// AdminOfferResponse is the AdminOfferResponse schema.
+type AdminOfferResponse struct {
+	AuthorEmail     string     `json:"authorEmail"`
+	AuthorFirstName string     `json:"authorFirstName"`
+	AuthorID        string     `json:"authorId"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	Description     string     `json:"description"`
+	ExpiresAt       *time.Time `json:"expiresAt"`
+	ID              string     `json:"id"`
+	IsActive        bool       `json:"isActive"`
+	OfferType       string     `json:"offerType"`
+	Title           string     `json:"title"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// AdminStats is the AdminStats schema.
+type AdminStats struct {
+	TotalJobs            int64 `json:"totalJobs"`
+	TotalOffers          int64 `json:"totalOffers"`
+	TotalServiceOffers   int64 `json:"totalServiceOffers"`
+	TotalServiceRequests int64 `json:"totalServiceRequests"`
+	TotalUsers           int64 `json:"totalUsers"`
+}
+
+// ApplicationCreated is the ApplicationCreated schema.
+type ApplicationCreated struct {
+	ResponseID string `json:"responseId"`
+}
+
+// AttributeCreated is the AttributeCreated schema.
+type AttributeCreated struct {
+	AttributeID int64  `json:"attributeId"`
+	Message     string `json:"message"`
+}
+
+// AuditEntry is the AuditEntry schema.
+type AuditEntry struct {
+	Action     string      `json:"action"`
+	ActorEmail *string     `json:"actorEmail"`
+	ActorID    *string     `json:"actorId"`
+	After      interface{} `json:"after"`
+	Before     interface{} `json:"before"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	ID         int64       `json:"id"`
+	IP         *string     `json:"ip"`
+	TargetID   string      `json:"targetId"`
+	TargetType string      `json:"targetType"`
+	UserAgent  *string     `json:"userAgent"`
+}
+
+// BanUserPayload is the BanUserPayload schema.
+type BanUserPayload struct {
+	Reason string `json:"reason"`
+}
+
+// CategoryAttribute is the CategoryAttribute schema.
+type CategoryAttribute struct {
+	CategoryID int64    `json:"categoryId"`
+	ID         int64    `json:"id"`
+	Key        string   `json:"key"`
+	Label      string   `json:"label"`
+	Options    []string `json:"options,omitempty"`
+	Required   bool     `json:"required"`
+	SortOrder  int64    `json:"sortOrder"`
+	Type       string   `json:"type"`
+}
+
+// CategoryAttributePayload is the CategoryAttributePayload schema.
+type CategoryAttributePayload struct {
+	Key       string   `json:"key"`
+	Label     string   `json:"label"`
+	Options   []string `json:"options,omitempty"`
+	Required  bool     `json:"required,omitempty"`
+	SortOrder int64    `json:"sortOrder,omitempty"`
+	Type      string   `json:"type"`
+}
+
+// CategoryCreated is the CategoryCreated schema.
+type CategoryCreated struct {
+	CategoryID int64  `json:"categoryId"`
+	Message    string `json:"message"`
+}
+
+// CategoryNode is the CategoryNode schema.
+type CategoryNode struct {
+	Children     []CategoryNode                 `json:"children"`
+	Description  string                         `json:"description"`
+	Icon         *string                        `json:"icon"`
+	ID           int64                          `json:"id"`
+	Name         string                         `json:"name"`
+	ParentID     *int64                         `json:"parentId"`
+	Slug         string                         `json:"slug"`
+	SortOrder    int64                          `json:"sortOrder"`
+	Translations map[string]CategoryTranslation `json:"translations,omitempty"`
+}
+
+// CategoryPayload is the CategoryPayload schema.
+type CategoryPayload struct {
+	Description  *string                        `json:"description,omitempty"`
+	Icon         *string                        `json:"icon,omitempty"`
+	Name         string                         `json:"name"`
+	ParentID     *int64                         `json:"parentId,omitempty"`
+	Slug         *string                        `json:"slug,omitempty"`
+	SortOrder    *int64                         `json:"sortOrder,omitempty"`
+	Translations map[string]CategoryTranslation `json:"translations,omitempty"`
+}
+
+// CategoryTranslation is the CategoryTranslation schema.
+type CategoryTranslation struct {
+	Description *string `json:"description,omitempty"`
+	Name        string  `json:"name"`
+}
+
+// ChatDetailsResponse is the ChatDetailsResponse schema.
+type ChatDetailsResponse struct {
+	ConversationID string       `json:"conversationId"`
+	OfferID        string       `json:"offerId"`
+	OfferTitle     string       `json:"offerTitle"`
+	Participants   []UserDetail `json:"participants"`
+}
+
+// ChatInitiated is the ChatInitiated schema.
+type ChatInitiated struct {
+	ConversationID string `json:"conversationId"`
+}
+
+// ConversationPreview is the ConversationPreview schema.
+type ConversationPreview struct {
+	ConversationID       string    `json:"conversationId"`
+	LastMessageAt        time.Time `json:"lastMessageAt"`
+	LastMessageContent   string    `json:"lastMessageContent"`
+	OfferTitle           string    `json:"offerTitle"`
+	OtherParticipantID   string    `json:"otherParticipantId"`
+	OtherParticipantName string    `json:"otherParticipantName"`
+}
+
+// CreateAPIKeyPayload is the CreateAPIKeyPayload schema.
+type CreateAPIKeyPayload struct {
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+}
+
+// CreateOfferPayload is the CreateOfferPayload schema.
+type CreateOfferPayload struct {
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	CategoryID  *int64                 `json:"categoryId,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	ExpiresAt   *time.Time             `json:"expiresAt,omitempty"`
+	OfferType   string                 `json:"offerType"`
+	Title       string                 `json:"title"`
+}
+
+// CreateReviewPayload is the CreateReviewPayload schema.
+type CreateReviewPayload struct {
+	Comment *string `json:"comment,omitempty"`
+	Rating  int64   `json:"rating"`
+}
+
+// DeletedEntity is the DeletedEntity schema.
+type DeletedEntity struct {
+	DeletedAt time.Time `json:"deletedAt"`
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+}
+
+// ErrorResponse is the ErrorResponse schema.
+type ErrorResponse struct {
+	Code    string                 `json:"code"`
+	Error   string                 `json:"error"`
+	Fields  []FieldError           `json:"fields,omitempty"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+// FieldError is the FieldError schema.
+type FieldError struct {
+	Code    string                 `json:"code"`
+	Field   string                 `json:"field"`
+	Message string                 `json:"message,omitempty"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+// HealthStatus is the HealthStatus schema.
+type HealthStatus struct {
+	Status string `json:"status"`
+}
+
+// InitiateChatPayload is the InitiateChatPayload schema.
+type InitiateChatPayload struct {
+	OfferID     string `json:"offerId"`
+	RecipientID string `json:"recipientId"`
+}
+
+// JWK is the JWK schema.
+type JWK struct {
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	E   string `json:"e,omitempty"`
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n,omitempty"`
+	Use string `json:"use"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet is the JWKSet schema.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Job is the Job schema.
+type Job struct {
+	ClientID     string     `json:"clientId"`
+	CompletedAt  *time.Time `json:"completedAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	ID           string     `json:"id"`
+	MasterID     string     `json:"masterId"`
+	OfferID      string     `json:"offerId"`
+	OfferTitle   string     `json:"offerTitle"`
+	ScheduledFor *time.Time `json:"scheduledFor"`
+	StartedAt    *time.Time `json:"startedAt"`
+	Status       string     `json:"status"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+// LiftSanctionPayload is the LiftSanctionPayload schema.
+type LiftSanctionPayload struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// LoginPayload is the LoginPayload schema.
+type LoginPayload struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginResult is the LoginResult schema.
+type LoginResult struct {
+	IsAdmin bool   `json:"isAdmin"`
+	Token   string `json:"token"`
+}
+
+// MessageResponse is the MessageResponse schema.
+type MessageResponse struct {
+	Content         string    `json:"content"`
+	ConversationID  string    `json:"conversationId"`
+	CreatedAt       time.Time `json:"createdAt"`
+	ID              string    `json:"id"`
+	IsRead          bool      `json:"isRead"`
+	SenderFirstName string    `json:"senderFirstName"`
+	SenderID        string    `json:"senderId"`
+}
+
+// Notification is the Notification schema.
+type Notification struct {
+	CreatedAt time.Time   `json:"createdAt"`
+	ID        string      `json:"id"`
+	Payload   interface{} `json:"payload"`
+	ReadAt    *time.Time  `json:"readAt"`
+	Type      string      `json:"type"`
+}
+
+// NotificationList is the NotificationList schema.
+type NotificationList struct {
+	Items       []Notification `json:"items"`
+	UnreadCount int64          `json:"unreadCount"`
+}
+
+// NotificationsMarked is the NotificationsMarked schema.
+type NotificationsMarked struct {
+	Message string `json:"message"`
+	Updated int64  `json:"updated"`
+}
+
+// OIDCProvider is the OIDCProvider schema.
+type OIDCProvider struct {
+	DisplayName string `json:"displayName"`
+	Name        string `json:"name"`
+}
+
+// OfferApplication is the OfferApplication schema.
+type OfferApplication struct {
+	ApplicantFirstName string    `json:"applicantFirstName,omitempty"`
+	ApplicantID        string    `json:"applicantId"`
+	ApplicantRating    float32   `json:"applicantRating,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+	ID                 string    `json:"id"`
+	Message            string    `json:"message"`
+	OfferID            string    `json:"offerId"`
+	Status             string    `json:"status"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+// OfferCreated is the OfferCreated schema.
+type OfferCreated struct {
+	OfferID string `json:"offerId"`
+}
+
+// OfferRenewed is the OfferRenewed schema.
+type OfferRenewed struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+	Message   string    `json:"message"`
+}
+
+// OfferResponse is the OfferResponse schema.
+type OfferResponse struct {
+	Attributes      map[string]interface{} `json:"attributes"`
+	AuthorFirstName string                 `json:"authorFirstName"`
+	AuthorID        string                 `json:"authorId"`
+	CategoryID      *int64                 `json:"categoryId"`
+	CreatedAt       time.Time              `json:"createdAt"`
+	Description     string                 `json:"description"`
+	ExpiresAt       *time.Time             `json:"expiresAt"`
+	HasResponded    bool                   `json:"hasResponded"`
+	ID              string                 `json:"id"`
+	OfferType       string                 `json:"offerType"`
+	Title           string                 `json:"title"`
+}
+
+// Readiness is the Readiness schema.
+type Readiness struct {
+	Database string        `json:"database,omitempty"`
+	Schema   *SchemaStatus `json:"schema,omitempty"`
+	Status   string        `json:"status"`
+}
+
+// RegisterPayload is the RegisterPayload schema.
+type RegisterPayload struct {
+	Email       string  `json:"email"`
+	FirstName   string  `json:"firstName"`
+	LastName    *string `json:"lastName,omitempty"`
+	Locale      string  `json:"locale,omitempty"`
+	Password    string  `json:"password"`
+	PhoneNumber *string `json:"phoneNumber,omitempty"`
+}
+
+// Registered is the Registered schema.
+type Registered struct {
+	Message string `json:"message"`
+	UserID  string `json:"userId"`
+}
+
+// RespondToOfferPayload is the RespondToOfferPayload schema.
+type RespondToOfferPayload struct {
+	Message string `json:"message,omitempty"`
+}
+
+// ReviewCreated is the ReviewCreated schema.
+type ReviewCreated struct {
+	ReviewID string `json:"reviewId"`
+}
+
+// SchemaStatus is the SchemaStatus schema.
+type SchemaStatus struct {
+	Dirty    bool  `json:"dirty"`
+	Expected int64 `json:"expected"`
+	Version  int64 `json:"version"`
+}
+
+// SecretRotated is the SecretRotated schema.
+type SecretRotated struct {
+	Message string `json:"message"`
+	Secret  string `json:"secret"`
+}
+
+// SendMessagePayload is the SendMessagePayload schema.
+type SendMessagePayload struct {
+	Content string `json:"content"`
+}
+
+// ServiceCategory is the ServiceCategory schema.
+type ServiceCategory struct {
+	Description  string                         `json:"description"`
+	Icon         *string                        `json:"icon"`
+	ID           int64                          `json:"id"`
+	Name         string                         `json:"name"`
+	ParentID     *int64                         `json:"parentId"`
+	Slug         string                         `json:"slug"`
+	SortOrder    int64                          `json:"sortOrder"`
+	Translations map[string]CategoryTranslation `json:"translations,omitempty"`
+}
+
+// SuspendUserPayload is the SuspendUserPayload schema.
+type SuspendUserPayload struct {
+	Reason string    `json:"reason"`
+	Until  time.Time `json:"until"`
+}
+
+// Task is the Task schema.
+type Task struct {
+	Attempts    int64       `json:"attempts"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	FinishedAt  *time.Time  `json:"finishedAt"`
+	ID          int64       `json:"id"`
+	Kind        string      `json:"kind"`
+	LastError   *string     `json:"lastError"`
+	LockedAt    *time.Time  `json:"lockedAt"`
+	LockedBy    *string     `json:"lockedBy"`
+	MaxAttempts int64       `json:"maxAttempts"`
+	Payload     interface{} `json:"payload"`
+	RunAt       time.Time   `json:"runAt"`
+	Status      string      `json:"status"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
+}
+
+// TrashResponse is the TrashResponse schema.
+type TrashResponse struct {
+	Categories []DeletedEntity `json:"categories"`
+	Offers     []DeletedEntity `json:"offers"`
+	Users      []DeletedEntity `json:"users"`
+}
+
+// UpdateApplicationStatusPayload is the UpdateApplicationStatusPayload schema.
+type UpdateApplicationStatusPayload struct {
+	Status string `json:"status"`
+}
+
+// UpdateCategoryPayload is the UpdateCategoryPayload schema.
+type UpdateCategoryPayload struct {
+	Clear        []string                       `json:"clear,omitempty"`
+	Description  *string                        `json:"description,omitempty"`
+	Icon         *string                        `json:"icon,omitempty"`
+	Name         *string                        `json:"name,omitempty"`
+	ParentID     *int64                         `json:"parentId,omitempty"`
+	Slug         *string                        `json:"slug,omitempty"`
+	SortOrder    *int64                         `json:"sortOrder,omitempty"`
+	Translations map[string]CategoryTranslation `json:"translations,omitempty"`
+}
+
+// UpdateJobStatusPayload is the UpdateJobStatusPayload schema.
+type UpdateJobStatusPayload struct {
+	Status string `json:"status"`
+}
+
+// UpdateOfferPayload is the UpdateOfferPayload schema.
+type UpdateOfferPayload struct {
+	IsActive *bool `json:"isActive,omitempty"`
+}
+
+// UpdateUserPayload is the UpdateUserPayload schema.
+type UpdateUserPayload struct {
+	Bio               *string `json:"bio,omitempty"`
+	FirstName         *string `json:"firstName,omitempty"`
+	IsAdmin           *bool   `json:"isAdmin,omitempty"`
+	LastName          *string `json:"lastName,omitempty"`
+	Locale            *string `json:"locale,omitempty"`
+	PhoneNumber       *string `json:"phoneNumber,omitempty"`
+	YearsOfExperience *int64  `json:"yearsOfExperience,omitempty"`
+}
+
+// UploadAvatarPayload is the UploadAvatarPayload schema.
+type UploadAvatarPayload struct {
+	Image []byte `json:"image"`
+}
+
+// UserDetail is the UserDetail schema.
+type UserDetail struct {
+	AverageRating     *float64   `json:"averageRating"`
+	Bio               *string    `json:"bio"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	Email             string     `json:"email"`
+	FirstName         *string    `json:"firstName"`
+	ID                string     `json:"id"`
+	LastName          *string    `json:"lastName"`
+	Locale            string     `json:"locale"`
+	PhoneNumber       *string    `json:"phoneNumber"`
+	Role              string     `json:"role"`
+	SanctionReason    *string    `json:"sanctionReason,omitempty"`
+	Status            string     `json:"status"`
+	SuspendedUntil    *time.Time `json:"suspendedUntil,omitempty"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+	YearsOfExperience *int64     `json:"yearsOfExperience"`
+}
+
+// UserSanction is the UserSanction schema.
+type UserSanction struct {
+	Action    string     `json:"action"`
+	ActorID   *string    `json:"actorId"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	ID        string     `json:"id"`
+	Reason    *string    `json:"reason"`
+	UserID    string     `json:"userId"`
+}
+
+// Webhook is the Webhook schema.
+type Webhook struct {
+	CreatedAt   time.Time `json:"createdAt"`
+	CreatedBy   *string   `json:"createdBy"`
+	Description *string   `json:"description"`
+	Events      []string  `json:"events"`
+	ID          int64     `json:"id"`
+	IsActive    bool      `json:"isActive"`
+	Secret      string    `json:"secret,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	URL         string    `json:"url"`
+}
+
+// WebhookCreated is the WebhookCreated schema.
+type WebhookCreated struct {
+	Message   string `json:"message"`
+	Secret    string `json:"secret"`
+	WebhookID int64  `json:"webhookId"`
+}
+
+// WebhookDelivery is the WebhookDelivery schema.
+type WebhookDelivery struct {
+	Attempt      int64     `json:"attempt"`
+	CreatedAt    time.Time `json:"createdAt"`
+	DurationMs   int64     `json:"durationMs"`
+	Error        *string   `json:"error"`
+	EventID      string    `json:"eventId"`
+	EventType    string    `json:"eventType"`
+	ID           int64     `json:"id"`
+	ResponseBody *string   `json:"responseBody"`
+	StatusCode   *int64    `json:"statusCode"`
+	Success      bool      `json:"success"`
+	WebhookID    int64     `json:"webhookId"`
+}
+
+// WebhookPayload is the WebhookPayload schema.
+type WebhookPayload struct {
+	Description *string  `json:"description,omitempty"`
+	Events      []string `json:"events"`
+	IsActive    *bool    `json:"isActive,omitempty"`
+	URL         string   `json:"url"`
+}
+
+// WebhookTestQueued is the WebhookTestQueued schema.
+type WebhookTestQueued struct {
+	EventID string `json:"eventId"`
+	Message string `json:"message"`
+}
+
+// GetJWKS calls GET /.well-known/jwks.json: Public keys for verifying session tokens.
+func (c *Client) GetJWKS(ctx context.Context) (*JWKSet, error) {
+	var out JWKSet
+	if err := c.do(ctx, "GET", "/.well-known/jwks.json", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAllAPIKeys calls GET /api/admin/api-keys: List API keys of all users.
+func (c *Client) GetAllAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var out []APIKey
+	err := c.do(ctx, "GET", "/api/admin/api-keys", nil, nil, &out)
+	return out, err
+}
+
+// RevokeAPIKey calls DELETE /api/admin/api-keys/{id}: Revoke any API key.
+func (c *Client) RevokeAPIKey(ctx context.Context, id string) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "DELETE", "/api/admin/api-keys/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuditLogParams holds the query parameters of GetAuditLog.
+type GetAuditLogParams struct {
+	// Only actions of this user
+	ActorID string
+	// Only this action, e.g. user.ban
+	Action string
+	// Only this target type, e.g. user
+	TargetType string
+	// Only this target
+	TargetID string
+	// Only entries created at or after this time
+	From *time.Time
+	// Only entries created before this time
+	To *time.Time
+	// Page size
+	Limit int64
+	// Number of items to skip
+	Offset int64
+}
+
+func (p *GetAuditLogParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.ActorID != "" {
+		q.Set("actorId", p.ActorID)
+	}
+	if p.Action != "" {
+		q.Set("action", p.Action)
+	}
+	if p.TargetType != "" {
+		q.Set("targetType", p.TargetType)
+	}
+	if p.TargetID != "" {
+		q.Set("targetId", p.TargetID)
+	}
+	if p.From != nil {
+		q.Set("from", p.From.Format(time.RFC3339))
+	}
+	if p.To != nil {
+		q.Set("to", p.To.Format(time.RFC3339))
+	}
+	if p.Limit != 0 {
+		q.Set("limit", strconv.FormatInt(p.Limit, 10))
+	}
+	if p.Offset != 0 {
+		q.Set("offset", strconv.FormatInt(p.Offset, 10))
+	}
+	return q
+}
+
+// GetAuditLog calls GET /api/admin/audit: Search the audit log.
+func (c *Client) GetAuditLog(ctx context.Context, params *GetAuditLogParams) ([]AuditEntry, error) {
+	var out []AuditEntry
+	err := c.do(ctx, "GET", "/api/admin/audit", params.values(), nil, &out)
+	return out, err
+}
+
+// ExportAuditLogParams holds the query parameters of ExportAuditLog.
+type ExportAuditLogParams struct {
+	// Only actions of this user
+	ActorID string
+	// Only this action, e.g. user.ban
+	Action string
+	// Only this target type, e.g. user
+	TargetType string
+	// Only this target
+	TargetID string
+	// Only entries created at or after this time
+	From *time.Time
+	// Only entries created before this time
+	To *time.Time
+}
+
+func (p *ExportAuditLogParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.ActorID != "" {
+		q.Set("actorId", p.ActorID)
+	}
+	if p.Action != "" {
+		q.Set("action", p.Action)
+	}
+	if p.TargetType != "" {
+		q.Set("targetType", p.TargetType)
+	}
+	if p.TargetID != "" {
+		q.Set("targetId", p.TargetID)
+	}
+	if p.From != nil {
+		q.Set("from", p.From.Format(time.RFC3339))
+	}
+	if p.To != nil {
+		q.Set("to", p.To.Format(time.RFC3339))
+	}
+	return q
+}
+
+// ExportAuditLog calls GET /api/admin/audit/export: Export the audit log as CSV.
+func (c *Client) ExportAuditLog(ctx context.Context, params *ExportAuditLogParams) ([]byte, error) {
+	var out []byte
+	err := c.do(ctx, "GET", "/api/admin/audit/export", params.values(), nil, &out)
+	return out, err
+}
+
+// GetAdminCategories calls GET /api/admin/categories: List categories with all translations.
+func (c *Client) GetAdminCategories(ctx context.Context) ([]ServiceCategory, error) {
+	var out []ServiceCategory
+	err := c.do(ctx, "GET", "/api/admin/categories", nil, nil, &out)
+	return out, err
+}
+
+// CreateCategory calls POST /api/admin/categories: Create a category.
+func (c *Client) CreateCategory(ctx context.Context, body CategoryPayload) (*CategoryCreated, error) {
+	var out CategoryCreated
+	if err := c.do(ctx, "POST", "/api/admin/categories", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteCategoryParams holds the query parameters of DeleteCategory.
+type DeleteCategoryParams struct {
+	// Category that receives the offers
+	ReassignTo int64
+}
+
+func (p *DeleteCategoryParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.ReassignTo != 0 {
+		q.Set("reassignTo", strconv.FormatInt(p.ReassignTo, 10))
+	}
+	return q
+}
+
+// DeleteCategory calls DELETE /api/admin/categories/{id}: Delete a category.
+func (c *Client) DeleteCategory(ctx context.Context, id int64, params *DeleteCategoryParams) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "DELETE", "/api/admin/categories/"+strconv.FormatInt(id, 10), params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateCategory calls PATCH /api/admin/categories/{id}: Update a category.
+func (c *Client) UpdateCategory(ctx context.Context, id int64, body UpdateCategoryPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "PATCH", "/api/admin/categories/"+strconv.FormatInt(id, 10), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateCategoryAttribute calls POST /api/admin/categories/{id}/attributes: Add an attribute to a category.
+func (c *Client) CreateCategoryAttribute(ctx context.Context, id int64, body CategoryAttributePayload) (*AttributeCreated, error) {
+	var out AttributeCreated
+	if err := c.do(ctx, "POST", "/api/admin/categories/"+strconv.FormatInt(id, 10)+"/attributes", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteCategoryAttribute calls DELETE /api/admin/categories/{id}/attributes/{attributeId}: Delete a category attribute.
+func (c *Client) DeleteCategoryAttribute(ctx context.Context, id int64, attributeID int64) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "DELETE", "/api/admin/categories/"+strconv.FormatInt(id, 10)+"/attributes/"+strconv.FormatInt(attributeID, 10), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateCategoryAttribute calls PATCH /api/admin/categories/{id}/attributes/{attributeId}: Update a category attribute.
+func (c *Client) UpdateCategoryAttribute(ctx context.Context, id int64, attributeID int64, body CategoryAttributePayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "PATCH", "/api/admin/categories/"+strconv.FormatInt(id, 10)+"/attributes/"+strconv.FormatInt(attributeID, 10), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RestoreCategory calls POST /api/admin/categories/{id}/restore: Restore a deleted category.
+func (c *Client) RestoreCategory(ctx context.Context, id int64) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "POST", "/api/admin/categories/"+strconv.FormatInt(id, 10)+"/restore", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAdminAllOffers calls GET /api/admin/offers: List all offers.
+func (c *Client) GetAdminAllOffers(ctx context.Context) ([]AdminOfferResponse, error) {
+	var out []AdminOfferResponse
+	err := c.do(ctx, "GET", "/api/admin/offers", nil, nil, &out)
+	return out, err
+}
+
+// DeleteOffer calls DELETE /api/admin/offers/{id}: Delete an offer.
+func (c *Client) DeleteOffer(ctx context.Context, id string) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "DELETE", "/api/admin/offers/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateOfferStatus calls PATCH /api/admin/offers/{id}: Publish or unpublish an offer.
+func (c *Client) UpdateOfferStatus(ctx context.Context, id string, body UpdateOfferPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "PATCH", "/api/admin/offers/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RestoreOffer calls POST /api/admin/offers/{id}/restore: Restore a deleted offer.
+func (c *Client) RestoreOffer(ctx context.Context, id string) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "POST", "/api/admin/offers/"+url.PathEscape(id)+"/restore", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAdminStats calls GET /api/admin/stats: Platform totals.
+func (c *Client) GetAdminStats(ctx context.Context) (*AdminStats, error) {
+	var out AdminStats
+	if err := c.do(ctx, "GET", "/api/admin/stats", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTasksParams holds the query parameters of GetTasks.
+type GetTasksParams struct {
+	// Task status
+	Status string
+	// Task kind
+	Kind string
+	// Page size
+	Limit int64
+	// Number of items to skip
+	Offset int64
+}
+
+func (p *GetTasksParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	if p.Kind != "" {
+		q.Set("kind", p.Kind)
+	}
+	if p.Limit != 0 {
+		q.Set("limit", strconv.FormatInt(p.Limit, 10))
+	}
+	if p.Offset != 0 {
+		q.Set("offset", strconv.FormatInt(p.Offset, 10))
+	}
+	return q
+}
+
+// GetTasks calls GET /api/admin/tasks: List background tasks.
+func (c *Client) GetTasks(ctx context.Context, params *GetTasksParams) ([]Task, error) {
+	var out []Task
+	err := c.do(ctx, "GET", "/api/admin/tasks", params.values(), nil, &out)
+	return out, err
+}
+
+// GetTask calls GET /api/admin/tasks/{id}: Get a background task.
+func (c *Client) GetTask(ctx context.Context, id int64) (*Task, error) {
+	var out Task
+	if err := c.do(ctx, "GET", "/api/admin/tasks/"+strconv.FormatInt(id, 10), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RetryTask calls POST /api/admin/tasks/{id}/retry: Requeue a dead task.
+func (c *Client) RetryTask(ctx context.Context, id int64) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "POST", "/api/admin/tasks/"+strconv.FormatInt(id, 10)+"/retry", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTrash calls GET /api/admin/trash: List deleted records that can be restored.
+func (c *Client) GetTrash(ctx context.Context) (*TrashResponse, error) {
+	var out TrashResponse
+	if err := c.do(ctx, "GET", "/api/admin/trash", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUsers calls GET /api/admin/users: List users.
+func (c *Client) GetUsers(ctx context.Context) ([]UserDetail, error) {
+	var out []UserDetail
+	err := c.do(ctx, "GET", "/api/admin/users", nil, nil, &out)
+	return out, err
+}
+
+// DeleteUser calls DELETE /api/admin/users/{id}: Delete a user.
+func (c *Client) DeleteUser(ctx context.Context, id string) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "DELETE", "/api/admin/users/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUserByID calls GET /api/admin/users/{id}: Get a user.
+func (c *Client) GetUserByID(ctx context.Context, id string) (*UserDetail, error) {
+	var out UserDetail
+	if err := c.do(ctx, "GET", "/api/admin/users/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateUser calls PATCH /api/admin/users/{id}: Update a user.
+func (c *Client) UpdateUser(ctx context.Context, id string, body UpdateUserPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "PATCH", "/api/admin/users/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BanUser calls POST /api/admin/users/{id}/ban: Ban a user.
+func (c *Client) BanUser(ctx context.Context, id string, body BanUserPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "POST", "/api/admin/users/"+url.PathEscape(id)+"/ban", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LiftUserSanction calls POST /api/admin/users/{id}/lift-sanction: Lift a suspension or ban.
+func (c *Client) LiftUserSanction(ctx context.Context, id string, body *LiftSanctionPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "POST", "/api/admin/users/"+url.PathEscape(id)+"/lift-sanction", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RestoreUser calls POST /api/admin/users/{id}/restore: Restore a deleted user.
+func (c *Client) RestoreUser(ctx context.Context, id string) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "POST", "/api/admin/users/"+url.PathEscape(id)+"/restore", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUserSanctions calls GET /api/admin/users/{id}/sanctions: List sanctions of a user.
+func (c *Client) GetUserSanctions(ctx context.Context, id string) ([]UserSanction, error) {
+	var out []UserSanction
+	err := c.do(ctx, "GET", "/api/admin/users/"+url.PathEscape(id)+"/sanctions", nil, nil, &out)
+	return out, err
+}
+
+// SuspendUser calls POST /api/admin/users/{id}/suspend: Suspend a user until a date.
+func (c *Client) SuspendUser(ctx context.Context, id string, body SuspendUserPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "POST", "/api/admin/users/"+url.PathEscape(id)+"/suspend", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhooks calls GET /api/admin/webhooks: List webhooks.
+func (c *Client) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	var out []Webhook
+	err := c.do(ctx, "GET", "/api/admin/webhooks", nil, nil, &out)
+	return out, err
+}
+
+// CreateWebhook calls POST /api/admin/webhooks: Create a webhook.
+func (c *Client) CreateWebhook(ctx context.Context, body WebhookPayload) (*WebhookCreated, error) {
+	var out WebhookCreated
+	if err := c.do(ctx, "POST", "/api/admin/webhooks", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWebhook calls DELETE /api/admin/webhooks/{id}: Delete a webhook.
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "DELETE", "/api/admin/webhooks/"+strconv.FormatInt(id, 10), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhook calls GET /api/admin/webhooks/{id}: Get a webhook.
+func (c *Client) GetWebhook(ctx context.Context, id int64) (*Webhook, error) {
+	var out Webhook
+	if err := c.do(ctx, "GET", "/api/admin/webhooks/"+strconv.FormatInt(id, 10), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateWebhook calls PATCH /api/admin/webhooks/{id}: Update a webhook.
+func (c *Client) UpdateWebhook(ctx context.Context, id int64, body WebhookPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "PATCH", "/api/admin/webhooks/"+strconv.FormatInt(id, 10), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhookDeliveriesParams holds the query parameters of GetWebhookDeliveries.
+type GetWebhookDeliveriesParams struct {
+	// Page size
+	Limit int64
+	// Number of items to skip
+	Offset int64
+}
+
+func (p *GetWebhookDeliveriesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Limit != 0 {
+		q.Set("limit", strconv.FormatInt(p.Limit, 10))
+	}
+	if p.Offset != 0 {
+		q.Set("offset", strconv.FormatInt(p.Offset, 10))
+	}
+	return q
+}
+
+// GetWebhookDeliveries calls GET /api/admin/webhooks/{id}/deliveries: List delivery attempts.
+func (c *Client) GetWebhookDeliveries(ctx context.Context, id int64, params *GetWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	var out []WebhookDelivery
+	err := c.do(ctx, "GET", "/api/admin/webhooks/"+strconv.FormatInt(id, 10)+"/deliveries", params.values(), nil, &out)
+	return out, err
+}
+
+// RotateWebhookSecret calls POST /api/admin/webhooks/{id}/rotate-secret: Generate a new signing secret.
+func (c *Client) RotateWebhookSecret(ctx context.Context, id int64) (*SecretRotated, error) {
+	var out SecretRotated
+	if err := c.do(ctx, "POST", "/api/admin/webhooks/"+strconv.FormatInt(id, 10)+"/rotate-secret", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TestWebhook calls POST /api/admin/webhooks/{id}/test: Queue a test event.
+func (c *Client) TestWebhook(ctx context.Context, id int64) (*WebhookTestQueued, error) {
+	var out WebhookTestQueued
+	if err := c.do(ctx, "POST", "/api/admin/webhooks/"+strconv.FormatInt(id, 10)+"/test", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMyAPIKeys calls GET /api/api-keys: List own API keys.
+func (c *Client) GetMyAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var out []APIKey
+	err := c.do(ctx, "GET", "/api/api-keys", nil, nil, &out)
+	return out, err
+}
+
+// CreateAPIKey calls POST /api/api-keys: Create an API key.
+func (c *Client) CreateAPIKey(ctx context.Context, body CreateAPIKeyPayload) (*APIKeyCreated, error) {
+	var out APIKeyCreated
+	if err := c.do(ctx, "POST", "/api/api-keys", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeMyAPIKey calls DELETE /api/api-keys/{id}: Revoke an own API key.
+func (c *Client) RevokeMyAPIKey(ctx context.Context, id string) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "DELETE", "/api/api-keys/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Login calls POST /api/auth/login: Sign in with email and password.
+func (c *Client) Login(ctx context.Context, body LoginPayload) (*LoginResult, error) {
+	var out LoginResult
+	if err := c.do(ctx, "POST", "/api/auth/login", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOIDCProviders calls GET /api/auth/oidc/providers: List OpenID Connect providers.
+func (c *Client) GetOIDCProviders(ctx context.Context) ([]OIDCProvider, error) {
+	var out []OIDCProvider
+	err := c.do(ctx, "GET", "/api/auth/oidc/providers", nil, nil, &out)
+	return out, err
+}
+
+// Register calls POST /api/auth/register: Register a user.
+func (c *Client) Register(ctx context.Context, body RegisterPayload) (*Registered, error) {
+	var out Registered
+	if err := c.do(ctx, "POST", "/api/auth/register", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAllCategoriesParams holds the query parameters of GetAllCategories.
+type GetAllCategoriesParams struct {
+	// Response language; defaults to Accept-Language
+	Lang string
+}
+
+func (p *GetAllCategoriesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Lang != "" {
+		q.Set("lang", p.Lang)
+	}
+	return q
+}
+
+// GetAllCategories calls GET /api/categories: List categories.
+func (c *Client) GetAllCategories(ctx context.Context, params *GetAllCategoriesParams) ([]ServiceCategory, error) {
+	var out []ServiceCategory
+	err := c.do(ctx, "GET", "/api/categories", params.values(), nil, &out)
+	return out, err
+}
+
+// GetCategoryTreeParams holds the query parameters of GetCategoryTree.
+type GetCategoryTreeParams struct {
+	// Response language; defaults to Accept-Language
+	Lang string
+}
+
+func (p *GetCategoryTreeParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Lang != "" {
+		q.Set("lang", p.Lang)
+	}
+	return q
+}
+
+// GetCategoryTree calls GET /api/categories/tree: Category tree.
+func (c *Client) GetCategoryTree(ctx context.Context, params *GetCategoryTreeParams) ([]CategoryNode, error) {
+	var out []CategoryNode
+	err := c.do(ctx, "GET", "/api/categories/tree", params.values(), nil, &out)
+	return out, err
+}
+
+// GetCategoryAttributes calls GET /api/categories/{id}/attributes: List attributes of a category.
+func (c *Client) GetCategoryAttributes(ctx context.Context, id int64) ([]CategoryAttribute, error) {
+	var out []CategoryAttribute
+	err := c.do(ctx, "GET", "/api/categories/"+strconv.FormatInt(id, 10)+"/attributes", nil, nil, &out)
+	return out, err
+}
+
+// GetConversations calls GET /api/chats: List conversations.
+func (c *Client) GetConversations(ctx context.Context) ([]ConversationPreview, error) {
+	var out []ConversationPreview
+	err := c.do(ctx, "GET", "/api/chats", nil, nil, &out)
+	return out, err
+}
+
+// InitiateChat calls POST /api/chats/initiate: Start or reopen a conversation about an offer.
+func (c *Client) InitiateChat(ctx context.Context, body InitiateChatPayload) (*ChatInitiated, error) {
+	var out ChatInitiated
+	if err := c.do(ctx, "POST", "/api/chats/initiate", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetChatDetails calls GET /api/chats/{id}: Get a conversation.
+func (c *Client) GetChatDetails(ctx context.Context, id string) (*ChatDetailsResponse, error) {
+	var out ChatDetailsResponse
+	if err := c.do(ctx, "GET", "/api/chats/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMessages calls GET /api/chats/{id}/messages: List messages of a conversation.
+func (c *Client) GetMessages(ctx context.Context, id string) ([]MessageResponse, error) {
+	var out []MessageResponse
+	err := c.do(ctx, "GET", "/api/chats/"+url.PathEscape(id)+"/messages", nil, nil, &out)
+	return out, err
+}
+
+// PostMessage calls POST /api/chats/{id}/messages: Send a message.
+func (c *Client) PostMessage(ctx context.Context, id string, body SendMessagePayload) (*MessageResponse, error) {
+	var out MessageResponse
+	if err := c.do(ctx, "POST", "/api/chats/"+url.PathEscape(id)+"/messages", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Health calls GET /api/health: Readiness probe under the API prefix.
+func (c *Client) Health(ctx context.Context) (*Readiness, error) {
+	var out Readiness
+	if err := c.do(ctx, "GET", "/api/health", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMyJobs calls GET /api/jobs: List jobs of the current user.
+func (c *Client) GetMyJobs(ctx context.Context) ([]Job, error) {
+	var out []Job
+	err := c.do(ctx, "GET", "/api/jobs", nil, nil, &out)
+	return out, err
+}
+
+// UpdateJobStatus calls PATCH /api/jobs/{id}: Change the status of a job.
+func (c *Client) UpdateJobStatus(ctx context.Context, id string, body UpdateJobStatusPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "PATCH", "/api/jobs/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateJobReview calls POST /api/jobs/{id}/review: Review the other participant of a completed job.
+func (c *Client) CreateJobReview(ctx context.Context, id string, body CreateReviewPayload) (*ReviewCreated, error) {
+	var out ReviewCreated
+	if err := c.do(ctx, "POST", "/api/jobs/"+url.PathEscape(id)+"/review", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNotificationsParams holds the query parameters of GetNotifications.
+type GetNotificationsParams struct {
+	// Only unread notifications
+	Unread bool
+	// Page size
+	Limit int64
+	// Number of items to skip
+	Offset int64
+}
+
+func (p *GetNotificationsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Unread {
+		q.Set("unread", "true")
+	}
+	if p.Limit != 0 {
+		q.Set("limit", strconv.FormatInt(p.Limit, 10))
+	}
+	if p.Offset != 0 {
+		q.Set("offset", strconv.FormatInt(p.Offset, 10))
+	}
+	return q
+}
+
+// GetNotifications calls GET /api/notifications: List notifications.
+func (c *Client) GetNotifications(ctx context.Context, params *GetNotificationsParams) (*NotificationList, error) {
+	var out NotificationList
+	if err := c.do(ctx, "GET", "/api/notifications", params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNotificationPreferences calls GET /api/notifications/preferences: Get notification settings.
+func (c *Client) GetNotificationPreferences(ctx context.Context) (map[string]bool, error) {
+	var out map[string]bool
+	err := c.do(ctx, "GET", "/api/notifications/preferences", nil, nil, &out)
+	return out, err
+}
+
+// UpdateNotificationPreferences calls PUT /api/notifications/preferences: Update notification settings.
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, body map[string]bool) (map[string]bool, error) {
+	var out map[string]bool
+	err := c.do(ctx, "PUT", "/api/notifications/preferences", nil, body, &out)
+	return out, err
+}
+
+// MarkAllNotificationsRead calls POST /api/notifications/read-all: Mark all notifications as read.
+func (c *Client) MarkAllNotificationsRead(ctx context.Context) (*NotificationsMarked, error) {
+	var out NotificationsMarked
+	if err := c.do(ctx, "POST", "/api/notifications/read-all", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MarkNotificationRead calls POST /api/notifications/{id}/read: Mark a notification as read.
+func (c *Client) MarkNotificationRead(ctx context.Context, id string) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "POST", "/api/notifications/"+url.PathEscape(id)+"/read", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOffersParams holds the query parameters of GetOffers.
+type GetOffersParams struct {
+	// Offer type
+	Type string
+	// Text to search in titles and descriptions
+	Search string
+	// Category ID or slug; subcategories are included
+	Category string
+	// Attribute equals the value, e.g. attr[material]=brick
+	Attr map[string]string
+	// Numeric attribute is at least the value
+	AttrMin map[string]string
+	// Numeric attribute is at most the value
+	AttrMax map[string]string
+}
+
+func (p *GetOffersParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Type != "" {
+		q.Set("type", p.Type)
+	}
+	if p.Search != "" {
+		q.Set("search", p.Search)
+	}
+	if p.Category != "" {
+		q.Set("category", p.Category)
+	}
+	for key, value := range p.Attr {
+		q.Set("attr["+key+"]", value)
+	}
+	for key, value := range p.AttrMin {
+		q.Set("attrMin["+key+"]", value)
+	}
+	for key, value := range p.AttrMax {
+		q.Set("attrMax["+key+"]", value)
+	}
+	return q
+}
+
+// GetOffers calls GET /api/offers: List active offers.
+func (c *Client) GetOffers(ctx context.Context, params *GetOffersParams) ([]OfferResponse, error) {
+	var out []OfferResponse
+	err := c.do(ctx, "GET", "/api/offers", params.values(), nil, &out)
+	return out, err
+}
+
+// CreateOffer calls POST /api/offers: Create an offer.
+func (c *Client) CreateOffer(ctx context.Context, body CreateOfferPayload) (*OfferCreated, error) {
+	var out OfferCreated
+	if err := c.do(ctx, "POST", "/api/offers", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOfferApplications calls GET /api/offers/{id}/applications: List applications to an own offer.
+func (c *Client) GetOfferApplications(ctx context.Context, id string) ([]OfferApplication, error) {
+	var out []OfferApplication
+	err := c.do(ctx, "GET", "/api/offers/"+url.PathEscape(id)+"/applications", nil, nil, &out)
+	return out, err
+}
+
+// UpdateApplicationStatus calls PATCH /api/offers/{id}/applications/{applicationId}: Accept or reject an application.
+func (c *Client) UpdateApplicationStatus(ctx context.Context, id string, applicationID string, body UpdateApplicationStatusPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "PATCH", "/api/offers/"+url.PathEscape(id)+"/applications/"+url.PathEscape(applicationID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RenewOffer calls POST /api/offers/{id}/renew: Extend the publication of an own offer.
+func (c *Client) RenewOffer(ctx context.Context, id string) (*OfferRenewed, error) {
+	var out OfferRenewed
+	if err := c.do(ctx, "POST", "/api/offers/"+url.PathEscape(id)+"/renew", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RespondToOffer calls POST /api/offers/{id}/respond: Respond to an offer.
+func (c *Client) RespondToOffer(ctx context.Context, id string, body RespondToOfferPayload) (*ApplicationCreated, error) {
+	var out ApplicationCreated
+	if err := c.do(ctx, "POST", "/api/offers/"+url.PathEscape(id)+"/respond", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOpenAPI calls GET /api/openapi.json: This OpenAPI document.
+func (c *Client) GetOpenAPI(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	err := c.do(ctx, "GET", "/api/openapi.json", nil, nil, &out)
+	return out, err
+}
+
+// GetMyProfile calls GET /api/profile: Get the current user profile.
+func (c *Client) GetMyProfile(ctx context.Context) (*UserDetail, error) {
+	var out UserDetail
+	if err := c.do(ctx, "GET", "/api/profile", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateMyProfile calls PATCH /api/profile: Update the current user profile.
+func (c *Client) UpdateMyProfile(ctx context.Context, body UpdateUserPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "PATCH", "/api/profile", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UploadMyAvatar calls PUT /api/profile/avatar: Upload the current user avatar.
+func (c *Client) UploadMyAvatar(ctx context.Context, body UploadAvatarPayload) (*ActionResult, error) {
+	var out ActionResult
+	if err := c.do(ctx, "PUT", "/api/profile/avatar", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUserAvatarParams holds the query parameters of GetUserAvatar.
+type GetUserAvatarParams struct {
+	// Image size
+	Size string
+}
+
+func (p *GetUserAvatarParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Size != "" {
+		q.Set("size", p.Size)
+	}
+	return q
+}
+
+// GetUserAvatar calls GET /api/users/{id}/avatar: Get a user avatar.
+func (c *Client) GetUserAvatar(ctx context.Context, id string, params *GetUserAvatarParams) ([]byte, error) {
+	var out []byte
+	err := c.do(ctx, "GET", "/api/users/"+url.PathEscape(id)+"/avatar", params.values(), nil, &out)
+	return out, err
+}
+
+// Livez calls GET /livez: Liveness probe.
+func (c *Client) Livez(ctx context.Context) (*HealthStatus, error) {
+	var out HealthStatus
+	if err := c.do(ctx, "GET", "/livez", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Readyz calls GET /readyz: Readiness probe.
+func (c *Client) Readyz(ctx context.Context) (*Readiness, error) {
+	var out Readiness
+	if err := c.do(ctx, "GET", "/readyz", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,350 @@
+// Команда clientgen генерирует типизированный клиент API (пакет client) по
+// документу OpenAPI из пакета openapi:
+//
+//	go run ./cmd/clientgen -o client/client_gen.go
+//
+// Для каждой схемы из components/schemas создается структура, для каждой
+// операции - метод Client. Операции с ответом 302 рассчитаны на браузер и в
+// клиент не попадают.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"masterdom/api/openapi"
+)
+
+func main() {
+	output := flag.String("o", "client_gen.go", "output file")
+	flag.Parse()
+
+	src, err := generate(openapi.Build())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clientgen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "clientgen:", err)
+		os.Exit(1)
+	}
+}
+
+// generate возвращает исходный код клиента для документа doc
+func generate(doc *openapi.Document) ([]byte, error) {
+	g := &generator{doc: doc}
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.structType(name, doc.Components.Schemas[name])
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		ops := doc.Paths[path].Operations()
+		methods := make([]string, 0, len(ops))
+		for method := range ops {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			if err := g.operation(method, path, ops[method]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Импортируются только пакеты, которые встречаются в коде
+	var out bytes.Buffer
+	out.WriteString("// Code generated by clientgen from the OpenAPI document. DO NOT EDIT.\n\n")
+	out.WriteString("package client\n\nimport (\n\t\"context\"\n")
+	for _, pkg := range []string{"net/url", "strconv", "time"} {
+		if bytes.Contains(g.buf.Bytes(), []byte(pkg[strings.LastIndex(pkg, "/")+1:]+".")) {
+			fmt.Fprintf(&out, "\t%q\n", pkg)
+		}
+	}
+	out.WriteString(")\n\n")
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	doc *openapi.Document
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// structType печатает структуру для схемы объекта
+func (g *generator) structType(name string, schema *openapi.Schema) {
+	g.printf("// %s is the %s schema.\n", name, name)
+	g.printf("type %s struct {\n", name)
+	for _, prop := range sortedKeys(schema.Properties) {
+		required := contains(schema.Required, prop)
+		g.printf("\t%s %s `json:\"%s\"`\n", goName(prop), g.fieldType(schema.Properties[prop], required), jsonTag(prop, required))
+	}
+	g.printf("}\n\n")
+}
+
+func jsonTag(name string, required bool) string {
+	if required {
+		return name
+	}
+	return name + ",omitempty"
+}
+
+// fieldType возвращает тип поля структуры. Значения, которые могут быть null
+// или отсутствовать, становятся указателями, кроме списков и словарей.
+func (g *generator) fieldType(s *openapi.Schema, required bool) string {
+	t := g.goType(s)
+	if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "interface{}" {
+		return t
+	}
+	if s.Nullable() || (!required && s.Ref != "") {
+		return "*" + t
+	}
+	return t
+}
+
+// goType возвращает тип Go для схемы
+func (g *generator) goType(s *openapi.Schema) string {
+	if s.Ref != "" {
+		return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+	}
+	if len(s.Type) == 0 {
+		return "interface{}"
+	}
+	switch s.Type[0] {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "time.Time"
+		case "byte":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(s.Items)
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "map[string]" + g.goType(s.AdditionalProperties)
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// operation печатает метод Client для операции и, если у нее есть параметры
+// строки запроса, структуру этих параметров
+func (g *generator) operation(method, path string, op *openapi.Operation) error {
+	success, resp := successResponse(op)
+	if success == http.StatusFound {
+		return nil
+	}
+	name := goName(op.OperationID)
+
+	var args []string
+	pathExpr := strconv.Quote(path)
+	var query []*openapi.Parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			arg := goArgName(p.Name)
+			typ := g.goType(p.Schema)
+			args = append(args, arg+" "+typ)
+			value := "url.PathEscape(" + arg + ")"
+			if typ == "int64" {
+				value = "strconv.FormatInt(" + arg + ", 10)"
+			}
+			pathExpr = strings.Replace(pathExpr, "{"+p.Name+"}", `" + `+value+` + "`, 1)
+		case "query":
+			query = append(query, p)
+		default:
+			return fmt.Errorf("%s: unsupported parameter location %q", op.OperationID, p.In)
+		}
+	}
+	pathExpr = strings.TrimSuffix(pathExpr, ` + ""`)
+
+	if len(query) > 0 {
+		g.queryType(name+"Params", op.OperationID, query)
+		args = append(args, "params *"+name+"Params")
+	}
+
+	bodyArg := "nil"
+	if op.RequestBody != nil {
+		typ := g.goType(op.RequestBody.Content["application/json"].Schema)
+		if !op.RequestBody.Required && !strings.HasPrefix(typ, "map[") && !strings.HasPrefix(typ, "[]") {
+			typ = "*" + typ
+		}
+		args = append(args, "body "+typ)
+		bodyArg = "body"
+	}
+
+	result := ""
+	raw := false
+	if resp != nil {
+		for contentType, media := range resp.Content {
+			if contentType == "application/json" {
+				result = g.goType(media.Schema)
+			} else {
+				raw = true
+				result = "[]byte"
+			}
+		}
+	}
+
+	g.printf("// %s calls %s %s: %s.\n", name, method, path, op.Summary)
+	g.printf("func (c *Client) %s(%s) ", name, strings.Join(append([]string{"ctx context.Context"}, args...), ", "))
+	queryArg := "nil"
+	if len(query) > 0 {
+		queryArg = "params.values()"
+	}
+	call := fmt.Sprintf("c.do(ctx, %q, %s, %s, %s", method, pathExpr, queryArg, bodyArg)
+	switch {
+	case result == "":
+		g.printf("error {\n\treturn %s, nil)\n}\n\n", call)
+	case raw || strings.HasPrefix(result, "[]") || strings.HasPrefix(result, "map["):
+		g.printf("(%s, error) {\n\tvar out %s\n\terr := %s, &out)\n\treturn out, err\n}\n\n", result, result, call)
+	default:
+		g.printf("(*%s, error) {\n\tvar out %s\n\tif err := %s, &out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n}\n\n", result, result, call)
+	}
+	return nil
+}
+
+// queryType печатает структуру параметров строки запроса и ее кодирование.
+// Пустые значения не передаются.
+func (g *generator) queryType(name, operationID string, params []*openapi.Parameter) {
+	g.printf("// %s holds the query parameters of %s.\n", name, goName(operationID))
+	g.printf("type %s struct {\n", name)
+	for _, p := range params {
+		typ := g.goType(p.Schema)
+		if typ == "time.Time" {
+			typ = "*time.Time"
+		}
+		if p.Description != "" {
+			g.printf("\t// %s\n", p.Description)
+		}
+		g.printf("\t%s %s\n", goName(p.Name), typ)
+	}
+	g.printf("}\n\n")
+
+	g.printf("func (p *%s) values() url.Values {\n", name)
+	g.printf("\tq := url.Values{}\n\tif p == nil {\n\t\treturn q\n\t}\n")
+	for _, p := range params {
+		field := "p." + goName(p.Name)
+		switch g.goType(p.Schema) {
+		case "string":
+			g.printf("\tif %s != \"\" {\n\t\tq.Set(%q, %s)\n\t}\n", field, p.Name, field)
+		case "int64":
+			g.printf("\tif %s != 0 {\n\t\tq.Set(%q, strconv.FormatInt(%s, 10))\n\t}\n", field, p.Name, field)
+		case "bool":
+			g.printf("\tif %s {\n\t\tq.Set(%q, \"true\")\n\t}\n", field, p.Name)
+		case "time.Time":
+			g.printf("\tif %s != nil {\n\t\tq.Set(%q, %s.Format(time.RFC3339))\n\t}\n", field, p.Name, field)
+		case "map[string]string":
+			g.printf("\tfor key, value := range %s {\n\t\tq.Set(%q+key+\"]\", value)\n\t}\n", field, p.Name+"[")
+		}
+	}
+	g.printf("\treturn q\n}\n\n")
+}
+
+// successResponse возвращает код и описание успешного ответа операции
+func successResponse(op *openapi.Operation) (int, *openapi.Response) {
+	best := 0
+	for code := range op.Responses {
+		status, err := strconv.Atoi(code)
+		if err == nil && status < 400 && (best == 0 || status < best) {
+			best = status
+		}
+	}
+	return best, op.Responses[strconv.Itoa(best)]
+}
+
+// initialisms пишутся в именах Go заглавными буквами целиком
+var initialisms = map[string]bool{
+	"Api": true, "Id": true, "Ip": true, "Jwks": true, "Json": true, "Oidc": true, "Url": true, "Uri": true,
+}
+
+// goName переводит имя из JSON в экспортируемое имя Go: apiKeyId -> APIKeyID
+func goName(name string) string {
+	var words []string
+	start := 0
+	runes := []rune(name)
+	for i := 1; i <= len(runes); i++ {
+		if i == len(runes) || unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1]) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	var b strings.Builder
+	for _, w := range words {
+		w = strings.ToUpper(w[:1]) + w[1:]
+		if initialisms[w] {
+			w = strings.ToUpper(w)
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// goArgName возвращает имя аргумента для параметра пути
+func goArgName(name string) string {
+	if name == "id" {
+		return "id"
+	}
+	n := goName(name)
+	return strings.ToLower(n[:1]) + n[1:]
+}
+
+func sortedKeys(m map[string]*openapi.Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
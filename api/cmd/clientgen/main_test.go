@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"masterdom/api/openapi"
+)
+
+// Сгенерированный клиент соответствует текущему документу OpenAPI. Если тест
+// не проходит, клиент нужно перегенерировать: go generate ./client
+func TestClientIsUpToDate(t *testing.T) {
+	want, err := generate(openapi.Build())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	got, err := os.ReadFile("../../client/client_gen.go")
+	if err != nil {
+		t.Fatalf("failed to read generated client: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("client/client_gen.go is out of date, run go generate ./client")
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"id":               "ID",
+		"apiKeyId":         "APIKeyID",
+		"lastUsedIp":       "LastUsedIP",
+		"getOIDCProviders": "GetOIDCProviders",
+		"redirectUrl":      "RedirectURL",
+		"attrMin":          "AttrMin",
+	}
+	for in, want := range tests {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
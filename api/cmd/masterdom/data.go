@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"masterdom/api/models"
+	"masterdom/api/store"
+	"masterdom/api/utils"
+)
+
+func deactivateOffers(ctx context.Context, s store.Store, args []string) error {
+	var filter models.OfferDeactivationFilter
+	fs := newFlagSet("offers deactivate")
+	author := fs.String("author", "", "")
+	category := fs.String("category", "", "")
+	olderThanDays := fs.Int("older-than-days", 0, "")
+	if rest, err := parseFlags(fs, args); err != nil {
+		return err
+	} else if len(rest) > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, rest[0])
+	}
+	// Без фильтров команда сняла бы с публикации все объявления
+	if *author == "" && *category == "" && *olderThanDays <= 0 {
+		return fmt.Errorf("%w: specify at least one of --author, --category, --older-than-days", errUsage)
+	}
+
+	if *author != "" {
+		user, err := s.GetUserByEmail(ctx, *author)
+		if err != nil {
+			return err
+		}
+		filter.AuthorID = &user.ID
+	}
+	if *category != "" {
+		id, err := strconv.Atoi(*category)
+		if err != nil {
+			return fmt.Errorf("%w: invalid category ID %q", errUsage, *category)
+		}
+		filter.CategoryID = &id
+	}
+	if *olderThanDays > 0 {
+		before := time.Now().AddDate(0, 0, -*olderThanDays)
+		filter.CreatedBefore = &before
+	}
+
+	ids, err := s.DeactivateOffers(ctx, filter)
+	for _, id := range ids {
+		fmt.Printf("Deactivated offer %s\n", id)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d offers deactivated\n", len(ids))
+	return nil
+}
+
+// recomputeRatings пересчитывает средние оценки всех пользователей по
+// отзывам о завершенных работах, например после удаления части отзывов.
+func recomputeRatings(ctx context.Context, s store.Store, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, args[0])
+	}
+	changed, err := s.RecomputeRatings(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d ratings updated\n", changed)
+	return nil
+}
+
+// export - формат выгрузки данных
+type export struct {
+	ExportedAt time.Time                   `json:"exportedAt"`
+	Users      []models.UserDetail         `json:"users"`
+	Offers     []models.AdminOfferResponse `json:"offers"`
+	Categories []models.ServiceCategory    `json:"categories"`
+}
+
+func exportData(ctx context.Context, s store.Store, args []string) error {
+	fs := newFlagSet("export")
+	out := fs.String("out", "", "")
+	if rest, err := parseFlags(fs, args); err != nil {
+		return err
+	} else if len(rest) > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, rest[0])
+	}
+
+	data := export{ExportedAt: time.Now().UTC()}
+	var err error
+	if data.Users, err = s.GetAllUsers(ctx); err != nil {
+		return err
+	}
+	if data.Offers, err = s.GetAllOffersForAdmin(ctx); err != nil {
+		return err
+	}
+	if data.Categories, err = s.GetAllCategories(ctx, utils.DefaultLocale); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "Exported %d users, %d offers and %d categories to %s\n",
+			len(data.Users), len(data.Offers), len(data.Categories), *out)
+	}
+	return nil
+}
+
+func printStats(ctx context.Context, s store.Store, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, args[0])
+	}
+	stats, err := s.GetAdminStats(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Users:             %d\n", stats.TotalUsers)
+	fmt.Printf("Offers:            %d\n", stats.TotalOffers)
+	fmt.Printf("  service offers:  %d\n", stats.TotalServiceOffers)
+	fmt.Printf("  requests:        %d\n", stats.TotalServiceRequests)
+	fmt.Printf("Jobs:              %d\n", stats.TotalJobs)
+	return nil
+}
@@ -0,0 +1,116 @@
+// Команда masterdom - утилита оператора для обслуживания базы данных без
+// ручного SQL. Она читает те же настройки, что и API, и работает через пакет
+// store, поэтому изменения проходят те же проверки и попадают в журнал аудита.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"masterdom/api/audit"
+	"masterdom/api/config"
+	"masterdom/api/db"
+	"masterdom/api/store"
+)
+
+const usage = `Usage: masterdom <command> [arguments]
+
+Commands:
+  user create --email E --first-name N [--last-name L] [--locale ru|en]
+              [--password P] [--admin]
+                         create a user; a password is generated when omitted
+  user promote EMAIL     grant the admin role
+  user demote EMAIL      revoke the admin role
+  user reset-password EMAIL [--password P]
+                         set a new password; a password is generated when omitted
+  offers deactivate [--author EMAIL] [--category ID] [--older-than-days N]
+                         unpublish all active offers matching the filters
+  ratings recompute      recalculate average user ratings from job reviews
+  export [--out FILE]    write users, offers and categories as JSON
+  stats                  print platform statistics
+
+Settings are read from the environment and CONFIG_FILE, as for the API.
+`
+
+// cliActor отмечает в журнале аудита изменения, сделанные через утилиту
+var cliActor = audit.Actor{UserAgent: "masterdom-cli"}
+
+// command - подкоманда утилиты
+type command func(ctx context.Context, s store.Store, args []string) error
+
+var commands = map[string]command{
+	"user create":         createUser,
+	"user promote":        promoteUser,
+	"user demote":         demoteUser,
+	"user reset-password": resetPassword,
+	"offers deactivate":   deactivateOffers,
+	"ratings recompute":   recomputeRatings,
+	"export":              exportData,
+	"stats":               printStats,
+}
+
+func main() {
+	log.SetFlags(0)
+	run, args, ok := lookup(os.Args[1:])
+	if !ok {
+		if len(os.Args) > 1 && isHelp(os.Args[1]) {
+			fmt.Print(usage)
+			return
+		}
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		log.Fatalf("Unable to load configuration: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dbp, err := db.Connect(ctx, cfg.Database)
+	if err != nil {
+		log.Fatalf("Unable to connect to the database: %v", err)
+	}
+	defer dbp.Close()
+	if err := db.CheckSchema(ctx, dbp); err != nil {
+		dbp.Close()
+		log.Fatalf("Database schema check failed: %v", err)
+	}
+
+	err = run(audit.WithActor(ctx, cliActor), store.NewPostgresStore(dbp), args)
+	if errors.Is(err, errUsage) {
+		dbp.Close()
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		dbp.Close()
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+// lookup находит подкоманду по одному или двум первым аргументам.
+func lookup(args []string) (command, []string, bool) {
+	if len(args) >= 2 {
+		if run, ok := commands[args[0]+" "+args[1]]; ok {
+			return run, args[2:], true
+		}
+	}
+	if len(args) >= 1 {
+		if run, ok := commands[args[0]]; ok {
+			return run, args[1:], true
+		}
+	}
+	return nil, nil, false
+}
+
+func isHelp(arg string) bool {
+	return arg == "help" || arg == "-h" || arg == "--help"
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"masterdom/api/models"
+	"masterdom/api/store"
+)
+
+// errUsage означает неверные аргументы: утилита печатает справку
+var errUsage = errors.New("invalid arguments")
+
+// minPasswordLength совпадает с ограничением при регистрации через API
+const minPasswordLength = 8
+
+// newFlagSet создает набор флагов, который не печатает ошибки сам.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseFlags разбирает флаги и возвращает позиционные аргументы. Флаги можно
+// указывать и после позиционных аргументов: "user reset-password EMAIL --password P".
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// generatePassword создает случайный пароль из 16 символов.
+func generatePassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashPassword возвращает bcrypt-хеш пароля. Пустой пароль заменяется
+// случайным, который возвращается вторым значением, чтобы его показали оператору.
+func hashPassword(password string) (string, string, error) {
+	var generated string
+	if password == "" {
+		var err error
+		if generated, err = generatePassword(); err != nil {
+			return "", "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password = generated
+	} else if len(password) < minPasswordLength {
+		return "", "", fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hashed), generated, nil
+}
+
+// printGeneratedPassword показывает созданный пароль: он нигде не сохраняется.
+func printGeneratedPassword(password string) {
+	if password != "" {
+		fmt.Printf("Generated password: %s\n", password)
+	}
+}
+
+// userByEmail возвращает ID пользователя по единственному позиционному аргументу.
+func userByEmail(ctx context.Context, s store.Store, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("%w: expected a single email", errUsage)
+	}
+	user, err := s.GetUserByEmail(ctx, strings.TrimSpace(args[0]))
+	if err != nil {
+		return "", err
+	}
+	return user.ID, nil
+}
+
+func createUser(ctx context.Context, s store.Store, args []string) error {
+	var payload models.RegisterPayload
+	var lastName string
+	fs := newFlagSet("user create")
+	fs.StringVar(&payload.Email, "email", "", "")
+	fs.StringVar(&payload.FirstName, "first-name", "", "")
+	fs.StringVar(&lastName, "last-name", "", "")
+	fs.StringVar(&payload.Password, "password", "", "")
+	fs.StringVar(&payload.Locale, "locale", "", "")
+	admin := fs.Bool("admin", false, "")
+	if rest, err := parseFlags(fs, args); err != nil {
+		return err
+	} else if len(rest) > 0 || payload.Email == "" || payload.FirstName == "" {
+		return fmt.Errorf("%w: --email and --first-name are required", errUsage)
+	}
+	if lastName != "" {
+		payload.LastName = &lastName
+	}
+
+	hashed, generated, err := hashPassword(payload.Password)
+	if err != nil {
+		return err
+	}
+	userID, err := s.CreateUser(ctx, payload, hashed)
+	if err != nil {
+		return err
+	}
+	printGeneratedPassword(generated)
+	if *admin {
+		if err := setAdmin(ctx, s, userID, true); err != nil {
+			return fmt.Errorf("user %s created, but not promoted: %w", userID, err)
+		}
+	}
+	fmt.Printf("Created user %s\n", userID)
+	return nil
+}
+
+func promoteUser(ctx context.Context, s store.Store, args []string) error {
+	userID, err := userByEmail(ctx, s, args)
+	if err != nil {
+		return err
+	}
+	if err := setAdmin(ctx, s, userID, true); err != nil {
+		return err
+	}
+	fmt.Printf("User %s is now an administrator\n", userID)
+	return nil
+}
+
+func demoteUser(ctx context.Context, s store.Store, args []string) error {
+	userID, err := userByEmail(ctx, s, args)
+	if err != nil {
+		return err
+	}
+	if err := setAdmin(ctx, s, userID, false); err != nil {
+		return err
+	}
+	fmt.Printf("User %s is no longer an administrator\n", userID)
+	return nil
+}
+
+func setAdmin(ctx context.Context, s store.Store, userID string, isAdmin bool) error {
+	return s.UpdateUserDetail(ctx, userID, models.UpdateUserPayload{IsAdmin: &isAdmin})
+}
+
+func resetPassword(ctx context.Context, s store.Store, args []string) error {
+	fs := newFlagSet("user reset-password")
+	password := fs.String("password", "", "")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	userID, err := userByEmail(ctx, s, rest)
+	if err != nil {
+		return err
+	}
+	hashed, generated, err := hashPassword(*password)
+	if err != nil {
+		return err
+	}
+	if err := s.SetUserPassword(ctx, userID, hashed); err != nil {
+		return err
+	}
+	printGeneratedPassword(generated)
+	fmt.Printf("Password of user %s has been reset\n", userID)
+	return nil
+}
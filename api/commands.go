@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"masterdom/api/config"
+	"masterdom/api/db"
+)
+
+// runMigrate выполняет подкоманды migrate up, down и status.
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected up, down or status")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dbp, err := db.Connect(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer dbp.Close()
+	migrator, err := db.NewMigrator(dbp)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			slog.Info("Applied migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			slog.Info("Schema is up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations %q", args[1])
+			}
+		}
+		// Откат удаляет данные, поэтому в продакшене он разрешен только явно
+		if cfg.IsProd() && os.Getenv("MIGRATE_ALLOW_DOWN") != "yes" {
+			return errors.New("rolling back in prod requires MIGRATE_ALLOW_DOWN=yes")
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			slog.Info("Reverted migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+		}
+		return err
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Schema version: %d (expected %d)\n", status.Version, db.SchemaVersion())
+		if status.Dirty {
+			fmt.Println("Schema is dirty: the last migration did not complete")
+		}
+		for _, m := range status.Pending {
+			fmt.Printf("Pending: %06d_%s\n", m.Version, m.Name)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+	return nil
+}
+
+// runSeed загружает тестовые данные. Сейчас есть только набор для разработки.
+func runSeed(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	dev := flags.Bool("dev", false, "load development test data")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !*dev {
+		return errors.New("specify the data set, e.g. --dev")
+	}
+	if cfg.IsProd() {
+		return errors.New("development data cannot be loaded in prod")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	dbp, err := db.Connect(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer dbp.Close()
+
+	if err := db.CheckSchema(ctx, dbp); err != nil {
+		return err
+	}
+	if err := db.Seed(ctx, dbp); err != nil {
+		return err
+	}
+	slog.Info("Development data loaded")
+	return nil
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// lookupEnv возвращает непустое значение переменной. Пустые значения, которые
+// docker compose передает для незаданных переменных, считаются отсутствующими.
+func lookupEnv(key string) (string, bool) {
+	value := os.Getenv(key)
+	return value, value != ""
+}
+
+// applyEnv заполняет поля с тегом env из переменных окружения.
+func applyEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if field.Type.Kind() == reflect.Struct && field.Type != timeType {
+			if err := applyEnv(value); err != nil {
+				return err
+			}
+			continue
+		}
+		key := field.Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		raw, ok := lookupEnv(key)
+		if !ok {
+			continue
+		}
+		if err := setValue(value, raw); err != nil {
+			return fmt.Errorf("invalid value %q for %s: %w", raw, key, err)
+		}
+	}
+	return nil
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if v.Type() == timeType {
+		// Моменты времени задаются в RFC 3339, например 2025-01-31T12:00:00Z
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		// Списки задаются через запятую
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// applyOIDCEnv читает провайдеров OIDC из окружения: OIDC_PROVIDERS содержит
+// имена через запятую, настройки каждого задаются переменными
+// OIDC_<ИМЯ>_ISSUER, _CLIENT_ID, _CLIENT_SECRET, _DISPLAY_NAME и _SCOPES.
+// Если OIDC_PROVIDERS задана, она заменяет список провайдеров из файла.
+func applyOIDCEnv(cfg *Config) {
+	names, ok := lookupEnv("OIDC_PROVIDERS")
+	if !ok {
+		return
+	}
+	cfg.OIDC = nil
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		cfg.OIDC = append(cfg.OIDC, OIDCProviderConfig{
+			Name:         name,
+			DisplayName:  os.Getenv(prefix + "DISPLAY_NAME"),
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"masterdom/api/config"
+	"masterdom/api/logging"
+)
+
+// Connect создает пул соединений и ждет, пока база данных станет доступна:
+// при запуске в docker compose API может стартовать раньше нее.
+func Connect(ctx context.Context, cfg config.DatabaseConfig, tracers ...pgx.QueryTracer) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database URL: %w", err)
+	}
+	poolConfig.MaxConns = cfg.MaxConns
+	poolConfig.MinConns = cfg.MinConns
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	switch len(tracers) {
+	case 0:
+	case 1:
+		poolConfig.ConnConfig.Tracer = tracers[0]
+	default:
+		poolConfig.ConnConfig.Tracer = multitracer.New(tracers...)
+	}
+
+	dbp, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+	}
+
+	deadline := time.Now().Add(cfg.ConnectTimeout)
+	delay := time.Second
+	for attempt := 1; ; attempt++ {
+		err = dbp.Ping(ctx)
+		if err == nil {
+			return dbp, nil
+		}
+		if time.Now().Add(delay).After(deadline) {
+			break
+		}
+		slog.WarnContext(ctx, "Database is not available, retrying",
+			slog.Int("attempt", attempt), slog.Duration("delay", delay), logging.Err(err))
+		select {
+		case <-ctx.Done():
+			dbp.Close()
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, 10*time.Second)
+	}
+	dbp.Close()
+	return nil, err
+}
+
+// CheckSchema проверяет, что схема базы данных соответствует коду. Более новая
+// схема допустима: при поэтапном обновлении старые экземпляры работают с ней,
+// пока их не заменят.
+func CheckSchema(ctx context.Context, dbp *pgxpool.Pool) error {
+	migrator, err := NewMigrator(dbp)
+	if err != nil {
+		return err
+	}
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	expected := SchemaVersion()
+	switch {
+	case status.Dirty:
+		return fmt.Errorf("schema is dirty at version %d", status.Version)
+	case status.Version < expected:
+		return fmt.Errorf("schema is at version %d, this build requires %d; run \"migrate up\"", status.Version, expected)
+	case status.Version > expected:
+		slog.WarnContext(ctx, "Schema is newer than expected", slog.Int("version", status.Version), slog.Int("expected", expected))
+	}
+	return nil
+}
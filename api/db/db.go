@@ -0,0 +1,255 @@
+// Package db содержит встроенные в бинарный файл миграции схемы и тестовые
+// данные. Версия схемы хранится в таблице schema_migrations в том же формате,
+// что у утилиты migrate, поэтому базы, размеченные ею, обновляются без изменений.
+package db
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migration/*.sql
+var migrationFiles embed.FS
+
+//go:embed seed/*.sql
+var seedFiles embed.FS
+
+// migrationLockID - ключ advisory-блокировки, чтобы несколько экземпляров API
+// не применяли миграции одновременно
+const migrationLockID = 72_616_173
+
+var migrationName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration - одна миграция схемы
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrations возвращает встроенные миграции по возрастанию версии.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migration")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		data, err := migrationFiles.ReadFile(path.Join("migration", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// SchemaVersion возвращает версию последней встроенной миграции, то есть
+// версию схемы, на которую рассчитан код.
+var SchemaVersion = sync.OnceValue(func() int {
+	migrations, err := Migrations()
+	if err != nil || len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+})
+
+// Status - состояние схемы базы данных
+type Status struct {
+	Version int
+	// Dirty означает, что миграция Version прервалась и схему нужно исправить вручную
+	Dirty   bool
+	Pending []Migration
+}
+
+// Migrator применяет встроенные миграции к базе данных.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// Status возвращает текущую версию схемы и еще не примененные миграции.
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	status := &Status{}
+	if status.Version, status.Dirty, err = currentVersion(ctx, conn.Conn()); err != nil {
+		return nil, err
+	}
+	for _, migration := range m.migrations {
+		if migration.Version > status.Version {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+// Up применяет все миграции новее текущей версии и возвращает примененные.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *pgx.Conn, version int) error {
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			if err := apply(ctx, conn, migration.Version, migration.Up); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down откатывает steps последних примененных миграций и возвращает откаченные.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.locked(ctx, func(conn *pgx.Conn, version int) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+			previous := 0
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := apply(ctx, conn, previous, migration.Down); err != nil {
+				return fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// locked выполняет fn под advisory-блокировкой. Схема в состоянии dirty
+// не изменяется: прерванную миграцию нужно сначала исправить вручную.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgx.Conn, version int) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	version, dirty, err := currentVersion(ctx, conn.Conn())
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("database schema is dirty at version %d, fix it manually and update schema_migrations", version)
+	}
+	return fn(conn.Conn(), version)
+}
+
+// apply выполняет SQL миграции так же, как утилита migrate: версия
+// помечается dirty до выполнения и очищается после успеха. Файлы миграций
+// сами управляют транзакциями.
+func apply(ctx context.Context, conn *pgx.Conn, version int, sql string) error {
+	if err := setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+	if _, err := conn.Exec(ctx, sql); err != nil {
+		return err
+	}
+	return setVersion(ctx, conn, version, false)
+}
+
+func currentVersion(ctx context.Context, conn *pgx.Conn) (int, bool, error) {
+	_, err := conn.Exec(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)")
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	var version int
+	var dirty bool
+	err = conn.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// setVersion записывает версию схемы. Версия 0 означает пустую схему.
+func setVersion(ctx context.Context, conn *pgx.Conn, version int, dirty bool) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, "TRUNCATE schema_migrations"); err != nil {
+		return fmt.Errorf("failed to update schema version: %w", err)
+	}
+	if version > 0 || dirty {
+		if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)", version, dirty); err != nil {
+			return fmt.Errorf("failed to update schema version: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// Seed загружает тестовые данные для разработки. Скрипт идемпотентен и
+// рассчитан на последнюю версию схемы.
+func Seed(ctx context.Context, pool *pgxpool.Pool) error {
+	data, err := seedFiles.ReadFile("seed/dev.sql")
+	if err != nil {
+		return err
+	}
+	if _, err := pool.Exec(ctx, string(data)); err != nil {
+		return fmt.Errorf("failed to load seed data: %w", err)
+	}
+	return nil
+}
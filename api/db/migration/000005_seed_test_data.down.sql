@@ -1 +1 @@
-TRUNCATE users, user_details, service_categories, offers RESTART IDENTITY CASCADE;
+-- Миграция 000005 ничего не создает, откатывать нечего.
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS user_sanctions;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS sanctioned_at,
+    DROP COLUMN IF EXISTS sanctioned_by,
+    DROP COLUMN IF EXISTS sanction_reason,
+    DROP COLUMN IF EXISTS suspended_until,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,21 @@
+-- Статус учетной записи: активна, временно приостановлена или заблокирована
+ALTER TABLE users
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'banned')),
+    ADD COLUMN suspended_until TIMESTAMPTZ,
+    ADD COLUMN sanction_reason TEXT,
+    ADD COLUMN sanctioned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN sanctioned_at TIMESTAMPTZ;
+
+-- История санкций: кто, когда, почему и до какого срока
+CREATE TABLE user_sanctions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('suspend', 'ban', 'lift')),
+    reason TEXT,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_sanctions_user_id ON user_sanctions(user_id);
+CREATE INDEX idx_users_status ON users(status) WHERE status <> 'active';
@@ -0,0 +1,17 @@
+DELETE FROM offers WHERE deleted_at IS NOT NULL;
+DELETE FROM service_categories WHERE deleted_at IS NOT NULL;
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_service_categories_deleted_at;
+DROP INDEX IF EXISTS idx_offers_deleted_at;
+DROP INDEX IF EXISTS idx_users_deleted_at;
+
+DROP INDEX IF EXISTS service_categories_name_active_key;
+ALTER TABLE service_categories ADD CONSTRAINT service_categories_name_key UNIQUE (name);
+
+DROP INDEX IF EXISTS users_email_active_key;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE service_categories DROP COLUMN deleted_at;
+ALTER TABLE offers DROP COLUMN deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
@@ -0,0 +1,16 @@
+-- Мягкое удаление: строки помечаются deleted_at и физически удаляются по истечении срока хранения
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE offers ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE service_categories ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Уникальность проверяется только среди неудаленных строк
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+CREATE UNIQUE INDEX users_email_active_key ON users(email) WHERE deleted_at IS NULL;
+
+ALTER TABLE service_categories DROP CONSTRAINT service_categories_name_key;
+CREATE UNIQUE INDEX service_categories_name_active_key ON service_categories(name) WHERE deleted_at IS NULL;
+
+-- Индексы для задачи очистки
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_offers_deleted_at ON offers(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_service_categories_deleted_at ON service_categories(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_offers_category_id;
+
+DROP INDEX IF EXISTS service_categories_parent_name_active_key;
+CREATE UNIQUE INDEX service_categories_name_active_key ON service_categories(name) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_service_categories_parent_id;
+DROP INDEX IF EXISTS service_categories_slug_active_key;
+
+ALTER TABLE service_categories
+    DROP COLUMN icon,
+    DROP COLUMN sort_order,
+    DROP COLUMN slug,
+    DROP COLUMN parent_id;
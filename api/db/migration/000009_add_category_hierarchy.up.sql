@@ -0,0 +1,21 @@
+-- Иерархия категорий: родитель, человекочитаемый адрес, ручной порядок и иконка
+ALTER TABLE service_categories
+    ADD COLUMN parent_id INT REFERENCES service_categories(id) ON DELETE SET NULL,
+    ADD COLUMN slug VARCHAR(120),
+    ADD COLUMN sort_order INT NOT NULL DEFAULT 0,
+    ADD COLUMN icon VARCHAR(100);
+
+UPDATE service_categories SET slug = 'category-' || id;
+ALTER TABLE service_categories ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX service_categories_slug_active_key ON service_categories(slug) WHERE deleted_at IS NULL;
+CREATE INDEX idx_service_categories_parent_id ON service_categories(parent_id);
+
+-- Одинаковые названия допустимы в разных ветках ("Ремонт → Розетки" и "Электрика → Розетки")
+DROP INDEX service_categories_name_active_key;
+CREATE UNIQUE INDEX service_categories_parent_name_active_key ON service_categories(COALESCE(parent_id, 0), name) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_offers_category_id ON offers(category_id);
+
+-- Категории могли быть вставлены с явными id, поэтому выравниваем последовательность
+SELECT setval(pg_get_serial_sequence('service_categories', 'id'), GREATEST(MAX(id), 1)) FROM service_categories;
@@ -0,0 +1,12 @@
+-- Переводы названий и описаний категорий
+CREATE TABLE service_category_translations (
+    category_id INT NOT NULL REFERENCES service_categories(id) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL CHECK (locale IN ('ru', 'en')),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    PRIMARY KEY (category_id, locale)
+);
+
+-- Существующие категории заведены на русском
+INSERT INTO service_category_translations (category_id, locale, name, description)
+SELECT id, 'ru', name, description FROM service_categories;
@@ -0,0 +1,2 @@
+ALTER TABLE offers DROP COLUMN IF EXISTS attributes;
+DROP TABLE IF EXISTS category_attributes;
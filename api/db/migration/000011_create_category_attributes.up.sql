@@ -0,0 +1,19 @@
+-- Схема дополнительных полей объявления для каждой категории
+CREATE TABLE category_attributes (
+    id SERIAL PRIMARY KEY,
+    category_id INT NOT NULL REFERENCES service_categories(id) ON DELETE CASCADE,
+    key VARCHAR(50) NOT NULL CHECK (key ~ '^[a-z][a-z0-9_]*$'),
+    label VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('string', 'integer', 'number', 'boolean', 'enum')),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    options JSONB, -- Допустимые значения для типа enum
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (category_id, key)
+);
+
+CREATE TRIGGER update_category_attributes_updated_at BEFORE UPDATE ON category_attributes FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Значения полей хранятся с типами JSON: числа как числа, флаги как boolean
+ALTER TABLE offers ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}'::jsonb;
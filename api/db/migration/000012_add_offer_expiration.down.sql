@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_offers_active_expires_at;
+ALTER TABLE offers DROP COLUMN IF EXISTS expiry_reminded_at;
+ALTER TABLE offers DROP COLUMN IF EXISTS expires_at;
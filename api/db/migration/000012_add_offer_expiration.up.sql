@@ -0,0 +1,14 @@
+-- Срок жизни объявлений: по истечении expires_at объявление снимается с публикации
+ALTER TABLE offers ADD COLUMN expires_at TIMESTAMPTZ;
+-- Время отправки напоминания автору о скором истечении срока
+ALTER TABLE offers ADD COLUMN expiry_reminded_at TIMESTAMPTZ;
+
+-- Существующим активным объявлениям назначается срок по умолчанию для их типа,
+-- но не менее недели, чтобы авторы успели продлить старые объявления
+UPDATE offers
+SET expires_at = GREATEST(
+        created_at + CASE offer_type WHEN 'request_for_service' THEN INTERVAL '30 days' ELSE INTERVAL '90 days' END,
+        NOW() + INTERVAL '7 days')
+WHERE is_active = TRUE AND deleted_at IS NULL;
+
+CREATE INDEX idx_offers_active_expires_at ON offers (expires_at) WHERE is_active = TRUE AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE notifications DROP COLUMN IF EXISTS emailed_at;
+DROP TABLE IF EXISTS email_outbox;
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
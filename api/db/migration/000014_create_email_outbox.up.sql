@@ -0,0 +1,22 @@
+-- Язык писем пользователю
+ALTER TABLE users ADD COLUMN locale VARCHAR(5) NOT NULL DEFAULT 'ru' CHECK (locale IN ('ru', 'en'));
+
+-- Очередь исходящих писем. Письмо формируется из шаблона на языке получателя
+-- в момент отправки; неудачные отправки повторяются с увеличивающейся задержкой
+CREATE TABLE email_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    template VARCHAR(50) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}'::jsonb,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ DEFAULT NOW(), -- NULL, если попытки исчерпаны
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_outbox_pending ON email_outbox (next_attempt_at) WHERE sent_at IS NULL AND next_attempt_at IS NOT NULL;
+
+-- Уведомления о сообщениях отправляются на почту сводкой; emailed_at отмечает попавшие в сводку
+ALTER TABLE notifications ADD COLUMN emailed_at TIMESTAMPTZ;
+UPDATE notifications SET emailed_at = NOW() WHERE type = 'message.created';
@@ -0,0 +1,24 @@
+-- Очередь фоновых задач. Воркеры забирают задачи через SELECT ... FOR UPDATE SKIP LOCKED
+CREATE TABLE tasks (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    -- pending: ждет запуска; running: выполняется; succeeded: выполнена;
+    -- dead: попытки исчерпаны или ошибка неустранима, задачу можно перезапустить вручную
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'succeeded', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 10,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_by VARCHAR(100),
+    locked_at TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_tasks_pending_run_at ON tasks (run_at) WHERE status = 'pending';
+CREATE INDEX idx_tasks_running_locked_at ON tasks (locked_at) WHERE status = 'running';
+CREATE INDEX idx_tasks_status_created_at ON tasks (status, created_at DESC);
+
+CREATE TRIGGER update_tasks_updated_at BEFORE UPDATE ON tasks FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
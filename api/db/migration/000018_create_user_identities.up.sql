@@ -0,0 +1,26 @@
+-- Пользователи, вошедшие через OpenID Connect, могут не иметь пароля
+ALTER TABLE users ALTER COLUMN password_hash DROP NOT NULL;
+
+-- Внешние учетные записи (провайдер OIDC + sub), привязанные к пользователям
+CREATE TABLE user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
+
+-- Незавершенные входы через OIDC: state из ссылки на провайдера, nonce и
+-- code_verifier для PKCE. Запись удаляется при обработке callback
+CREATE TABLE oidc_login_states (
+    state VARCHAR(64) PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,14 @@
+-- Отзывы участников о завершенной работе: каждый участник оценивает другого
+-- один раз. Из оценок складывается user_details.average_rating
+CREATE TABLE job_reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subject_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (job_id, author_id)
+);
+
+CREATE INDEX idx_job_reviews_subject_id ON job_reviews (subject_id);
@@ -0,0 +1,6 @@
+-- Неотправленные письма снова отправляются из email_outbox по next_attempt_at
+ALTER TABLE email_outbox ADD COLUMN next_attempt_at TIMESTAMPTZ DEFAULT NOW();
+UPDATE email_outbox SET next_attempt_at = NULL WHERE sent_at IS NOT NULL;
+CREATE INDEX idx_email_outbox_pending ON email_outbox (next_attempt_at) WHERE sent_at IS NULL AND next_attempt_at IS NOT NULL;
+
+DELETE FROM tasks WHERE kind = 'email.send' AND status IN ('pending', 'running');
@@ -0,0 +1,9 @@
+-- Письма отправляются задачами очереди tasks (вид email.send); email_outbox
+-- остается журналом писем. Для писем, ожидающих отправки, ставятся задачи
+INSERT INTO tasks (kind, payload, max_attempts)
+SELECT 'email.send', jsonb_build_object('emailId', id), 8
+FROM email_outbox
+WHERE sent_at IS NULL AND next_attempt_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_email_outbox_pending;
+ALTER TABLE email_outbox DROP COLUMN next_attempt_at;
@@ -0,0 +1,9 @@
+-- Фотографии профилей. Миниатюру строит фоновая задача avatar.thumbnail,
+-- до этого thumbnail пуст
+CREATE TABLE user_avatars (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    content_type VARCHAR(50) NOT NULL,
+    image BYTEA NOT NULL,
+    thumbnail BYTEA,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -4,9 +4,17 @@
 # Exit immediately if a command exits with a non-zero status.
 set -e
 
-# Run the database migrations
+# Run the database migrations embedded in the API binary
 echo "Running database migrations..."
-/app/migrate -path /app/migration -database "$DB_URL" up
+/app/main migrate up
+
+# Load test data in development only; the seed script is idempotent
+case "${APP_ENV:-dev}" in
+  dev|development)
+    echo "Loading development data..."
+    /app/main seed --dev
+    ;;
+esac
 
 echo "Migrations complete. Starting server."
 
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/apperr"
+	"masterdom/api/models"
+	"masterdom/api/store"
+	"masterdom/api/utils"
+)
+
+// --- API Key Handlers ---
+
+// GetMyAPIKeys lists the current user's API keys, including revoked ones.
+func (h *Handler) GetMyAPIKeys(c *gin.Context) {
+	userID := c.GetString("userID")
+	keys, err := h.Store.GetAPIKeys(c.Request.Context(), &userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// apiKeyAttempts limits how many keys CreateAPIKey generates on prefix collisions.
+const apiKeyAttempts = 3
+
+// CreateAPIKey issues a new key for the current user. The plain key is only
+// returned in this response; the database keeps its hash.
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var payload models.CreateAPIKeyPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+	if slices.Contains(payload.Scopes, models.ScopeAdmin) && !c.GetBool("isAdmin") {
+		respondError(c, apperr.Forbidden("admin_scope_forbidden", "Only administrators can create keys with the admin scope"))
+		return
+	}
+	if payload.ExpiresAt != nil && !payload.ExpiresAt.After(time.Now()) {
+		respondError(c, apperr.Validation("expiration_in_past", "Expiration time must be in the future"))
+		return
+	}
+	slices.Sort(payload.Scopes)
+	payload.Scopes = slices.Compact(payload.Scopes)
+
+	// A prefix collision is unlikely but possible, so retry with a fresh key
+	var key string
+	var apiKey *models.APIKey
+	var err error
+	for attempt := 0; attempt < apiKeyAttempts; attempt++ {
+		var prefix string
+		key, prefix, err = utils.GenerateAPIKey()
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to generate API key: %w", err))
+			return
+		}
+		apiKey, err = h.Store.CreateAPIKey(c.Request.Context(), c.GetString("userID"), payload, prefix, utils.HashAPIKey(key))
+		if !errors.Is(err, store.ErrAPIKeyPrefixTaken) {
+			break
+		}
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"apiKey": apiKey, "key": key})
+}
+
+// RevokeMyAPIKey revokes one of the current user's keys.
+func (h *Handler) RevokeMyAPIKey(c *gin.Context) {
+	userID := c.GetString("userID")
+	if err := h.Store.RevokeAPIKey(c.Request.Context(), c.Param("id"), &userID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
+
+// GetAllAPIKeys lists the keys of all users for administrators.
+func (h *Handler) GetAllAPIKeys(c *gin.Context) {
+	keys, err := h.Store.GetAPIKeys(c.Request.Context(), nil)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey lets an administrator revoke any user's key.
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	if err := h.Store.RevokeAPIKey(c.Request.Context(), c.Param("id"), nil); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/apperr"
+	"masterdom/api/models"
+)
+
+// --- Category Attribute Handlers ---
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func (h *Handler) GetCategoryAttributes(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, invalidParam("id"))
+		return
+	}
+
+	attributes, err := h.Store.GetCategoryAttributes(c.Request.Context(), categoryID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, attributes)
+}
+
+func (h *Handler) CreateCategoryAttribute(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, invalidParam("id"))
+		return
+	}
+	payload, ok := bindAttributePayload(c)
+	if !ok {
+		return
+	}
+
+	attributeID, err := h.Store.CreateCategoryAttribute(c.Request.Context(), categoryID, payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Category attribute created successfully", "attributeId": attributeID})
+}
+
+func (h *Handler) UpdateCategoryAttribute(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, invalidParam("id"))
+		return
+	}
+	attributeID, err := strconv.Atoi(c.Param("attributeId"))
+	if err != nil {
+		respondError(c, invalidParam("attributeId"))
+		return
+	}
+	payload, ok := bindAttributePayload(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Store.UpdateCategoryAttribute(c.Request.Context(), categoryID, attributeID, payload); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Category attribute updated successfully"})
+}
+
+func (h *Handler) DeleteCategoryAttribute(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, invalidParam("id"))
+		return
+	}
+	attributeID, err := strconv.Atoi(c.Param("attributeId"))
+	if err != nil {
+		respondError(c, invalidParam("attributeId"))
+		return
+	}
+
+	if err := h.Store.DeleteCategoryAttribute(c.Request.Context(), categoryID, attributeID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Category attribute deleted successfully"})
+}
+
+func bindAttributePayload(c *gin.Context) (models.CategoryAttributePayload, bool) {
+	var payload models.CategoryAttributePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return payload, false
+	}
+	if !attributeKeyPattern.MatchString(payload.Key) {
+		respondError(c, apperr.Validation("invalid_attribute_key", "Attribute key must start with a letter and contain only lowercase latin letters, digits and underscores"))
+		return payload, false
+	}
+	if payload.Type == models.AttributeTypeEnum && len(payload.Options) == 0 {
+		respondError(c, apperr.Validation("enum_options_required", "Enum attributes require at least one option"))
+		return payload, false
+	}
+	if payload.Type != models.AttributeTypeEnum && len(payload.Options) > 0 {
+		respondError(c, apperr.Validation("options_not_allowed", "Only enum attributes may have options"))
+		return payload, false
+	}
+	return payload, true
+}
+
+// validateOfferAttributes checks submitted values against the category schema
+// and returns them converted to their JSON types: integers as int64, numbers
+// as float64, flags as bool and strings and enum values as string. Every
+// value that does not match the schema is reported as a field error.
+func validateOfferAttributes(schema []models.CategoryAttribute, values map[string]interface{}) (map[string]interface{}, []apperr.FieldError) {
+	var fields []apperr.FieldError
+	byKey := make(map[string]models.CategoryAttribute, len(schema))
+	for _, attr := range schema {
+		byKey[attr.Key] = attr
+	}
+	for key := range values {
+		if _, ok := byKey[key]; !ok {
+			fields = append(fields, apperr.FieldError{Field: "attributes." + key, Code: "unknown"})
+		}
+	}
+
+	typed := make(map[string]interface{}, len(values))
+	for _, attr := range schema {
+		field := "attributes." + attr.Key
+		value, ok := values[attr.Key]
+		if !ok || value == nil {
+			if attr.Required {
+				fields = append(fields, apperr.FieldError{Field: field, Code: "required"})
+			}
+			continue
+		}
+
+		switch attr.Type {
+		case models.AttributeTypeString:
+			v, ok := value.(string)
+			if !ok {
+				fields = append(fields, invalidType(field, "string"))
+				continue
+			}
+			typed[attr.Key] = v
+		case models.AttributeTypeInteger:
+			v, ok := value.(float64)
+			if !ok || v != math.Trunc(v) {
+				fields = append(fields, invalidType(field, "integer"))
+				continue
+			}
+			typed[attr.Key] = int64(v)
+		case models.AttributeTypeNumber:
+			v, ok := value.(float64)
+			if !ok {
+				fields = append(fields, invalidType(field, "number"))
+				continue
+			}
+			typed[attr.Key] = v
+		case models.AttributeTypeBoolean:
+			v, ok := value.(bool)
+			if !ok {
+				fields = append(fields, invalidType(field, "boolean"))
+				continue
+			}
+			typed[attr.Key] = v
+		case models.AttributeTypeEnum:
+			v, ok := value.(string)
+			if !ok || !containsString(attr.Options, v) {
+				fields = append(fields, apperr.FieldError{
+					Field:  field,
+					Code:   "oneof",
+					Params: map[string]interface{}{"allowed": attr.Options},
+				})
+				continue
+			}
+			typed[attr.Key] = v
+		}
+	}
+	return typed, fields
+}
+
+// parseAttributeFilters reads attr[key]=value, attrMin[key]=n and attrMax[key]=n
+// query parameters and reports every malformed one as a field error.
+func parseAttributeFilters(c *gin.Context) ([]models.AttributeFilter, []apperr.FieldError) {
+	var filters []models.AttributeFilter
+	var fields []apperr.FieldError
+	for param, op := range map[string]string{
+		"attr":    models.AttributeFilterEq,
+		"attrMin": models.AttributeFilterMin,
+		"attrMax": models.AttributeFilterMax,
+	} {
+		for key, value := range c.QueryMap(param) {
+			field := param + "[" + key + "]"
+			if !attributeKeyPattern.MatchString(key) {
+				fields = append(fields, apperr.FieldError{Field: field, Code: "invalid_key"})
+				continue
+			}
+			if op != models.AttributeFilterEq {
+				if _, err := strconv.ParseFloat(value, 64); err != nil {
+					fields = append(fields, invalidType(field, "number"))
+					continue
+				}
+			}
+			filters = append(filters, models.AttributeFilter{Key: key, Op: op, Value: value})
+		}
+	}
+	return filters, fields
+}
+
+// invalidType reports a value that is not of the expected JSON type.
+func invalidType(field, jsonType string) apperr.FieldError {
+	return apperr.FieldError{Field: field, Code: "invalid_type", Params: map[string]interface{}{"type": jsonType}}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/models"
+)
+
+// --- Audit Handlers ---
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+// parseAuditFilter reads the audit log filters shared by the list and export endpoints.
+func parseAuditFilter(c *gin.Context) (models.AuditFilter, bool) {
+	filter := models.AuditFilter{
+		ActorID:    c.Query("actorId"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("targetType"),
+		TargetID:   c.Query("targetId"),
+	}
+	for param, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				respondError(c, invalidParam(param).With("format", "RFC3339"))
+				return filter, false
+			}
+			*dst = &t
+		}
+	}
+	return filter, true
+}
+
+func (h *Handler) GetAuditLog(c *gin.Context) {
+	filter, ok := parseAuditFilter(c)
+	if !ok {
+		return
+	}
+
+	filter.Limit = defaultAuditPageSize
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			respondError(c, invalidParam("limit"))
+			return
+		}
+		filter.Limit = min(limit, maxAuditPageSize)
+	}
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			respondError(c, invalidParam("offset"))
+			return
+		}
+		filter.Offset = offset
+	}
+
+	entries, err := h.Store.GetAuditLog(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+func (h *Handler) ExportAuditLog(c *gin.Context) {
+	filter, ok := parseAuditFilter(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.Store.GetAuditLog(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="audit-log.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"id", "created_at", "actor_id", "actor_email", "action", "target_type", "target_id", "before", "after", "ip", "user_agent"})
+	for _, e := range entries {
+		w.Write([]string{
+			strconv.FormatInt(e.ID, 10),
+			e.CreatedAt.Format(time.RFC3339),
+			deref(e.ActorID),
+			deref(e.ActorEmail),
+			e.Action,
+			e.TargetType,
+			e.TargetID,
+			string(e.Before),
+			string(e.After),
+			deref(e.IP),
+			deref(e.UserAgent),
+		})
+	}
+	w.Flush()
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/apperr"
+	"masterdom/api/images"
+	"masterdom/api/models"
+)
+
+var (
+	errInvalidImage  = apperr.Validation("invalid_image", "Image must be a JPEG or PNG file")
+	errImageTooLarge = apperr.Validation("image_too_large", "Image is too large").With("maxBytes", images.MaxBytes)
+)
+
+// maxAvatarRequest limits the upload request: the image is base64 encoded,
+// which takes 4 bytes for every 3, plus room for the JSON around it.
+const maxAvatarRequest = images.MaxBytes/3*4 + 1024
+
+// --- Avatar Handlers ---
+
+// UploadMyAvatar replaces the avatar of the current user. The thumbnail is
+// built by a background task, until then the full image is served instead.
+func (h *Handler) UploadMyAvatar(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxAvatarRequest)
+	var payload models.UploadAvatarPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, errImageTooLarge.Wrap(err))
+			return
+		}
+		respondError(c, invalidInput(err))
+		return
+	}
+	if len(payload.Image) > images.MaxBytes {
+		respondError(c, errImageTooLarge)
+		return
+	}
+	contentType, err := images.Detect(payload.Image)
+	if err != nil {
+		respondError(c, errInvalidImage.Wrap(err))
+		return
+	}
+
+	if err := h.Store.SetUserAvatar(c.Request.Context(), userID.(string), contentType, payload.Image); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Avatar uploaded successfully"})
+}
+
+// GetUserAvatar serves the avatar of a user, or its thumbnail with
+// ?size=thumbnail once it has been built.
+func (h *Handler) GetUserAvatar(c *gin.Context) {
+	avatar, err := h.Store.GetUserAvatar(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if c.Query("size") == "thumbnail" && avatar.Thumbnail != nil {
+		c.Data(http.StatusOK, "image/jpeg", avatar.Thumbnail)
+		return
+	}
+	c.Data(http.StatusOK, avatar.ContentType, avatar.Image)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/apperr"
+	"masterdom/api/models"
+	"masterdom/api/utils"
+)
+
+// --- Category Handlers ---
+
+// requestLocale picks the response language from ?lang= or Accept-Language
+// and advertises it in the response headers.
+func requestLocale(c *gin.Context) string {
+	locale := c.Query("lang")
+	if !utils.IsSupportedLocale(locale) {
+		locale = utils.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	}
+	c.Header("Content-Language", locale)
+	c.Header("Vary", "Accept-Language")
+	return locale
+}
+
+func (h *Handler) GetAllCategories(c *gin.Context) {
+	categories, err := h.Store.GetAllCategories(c.Request.Context(), requestLocale(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(200, categories)
+}
+
+// GetCategoryTree returns root categories with their subcategories nested
+// under "children", each level ordered by sortOrder and name.
+func (h *Handler) GetCategoryTree(c *gin.Context) {
+	categories, err := h.Store.GetAllCategories(c.Request.Context(), requestLocale(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, buildCategoryTree(categories))
+}
+
+// GetAdminCategories returns categories in the base language together with
+// all their translations, for editing in the admin panel.
+func (h *Handler) GetAdminCategories(c *gin.Context) {
+	categories, err := h.Store.GetAllCategories(c.Request.Context(), utils.DefaultLocale)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	translations, err := h.Store.GetCategoryTranslations(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for i := range categories {
+		categories[i].Translations = translations[categories[i].ID]
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// buildCategoryTree nests an ordered flat list. Categories whose parent is not
+// in the list are treated as roots so nothing disappears from the tree.
+func buildCategoryTree(categories []models.ServiceCategory) []*models.CategoryNode {
+	nodes := make(map[int]*models.CategoryNode, len(categories))
+	for _, cat := range categories {
+		nodes[cat.ID] = &models.CategoryNode{ServiceCategory: cat, Children: make([]*models.CategoryNode, 0)}
+	}
+
+	roots := make([]*models.CategoryNode, 0)
+	for _, cat := range categories {
+		node := nodes[cat.ID]
+		if cat.ParentID != nil {
+			if parent, ok := nodes[*cat.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+func (h *Handler) CreateCategory(c *gin.Context) {
+	var payload models.CategoryPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+	if !validCategorySlug(c, payload.Slug) {
+		return
+	}
+
+	categoryID, err := h.Store.CreateCategory(c.Request.Context(), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(201, gin.H{"message": "Category created successfully", "categoryId": categoryID})
+}
+
+func (h *Handler) UpdateCategory(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, invalidParam("id"))
+		return
+	}
+
+	var payload models.UpdateCategoryPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+	if !validCategorySlug(c, payload.Slug) {
+		return
+	}
+
+	err = h.Store.UpdateCategory(c.Request.Context(), categoryID, payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Category updated successfully"})
+}
+
+// DeleteCategory removes a category. If it still has subcategories or offers,
+// the caller must pass ?reassignTo=<categoryId> to move them first.
+func (h *Handler) DeleteCategory(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, invalidParam("id"))
+		return
+	}
+
+	var reassignTo *int
+	if value := c.Query("reassignTo"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			respondError(c, invalidParam("reassignTo"))
+			return
+		}
+		reassignTo = &id
+	}
+
+	err = h.Store.DeleteCategory(c.Request.Context(), categoryID, reassignTo)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Category deleted successfully"})
+}
+
+func validCategorySlug(c *gin.Context, slug *string) bool {
+	if slug != nil && *slug != "" && !utils.IsValidSlug(*slug) {
+		respondError(c, apperr.Validation("invalid_slug", "Slug may contain only lowercase latin letters, digits and single dashes"))
+		return false
+	}
+	return true
+}
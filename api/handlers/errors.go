@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"masterdom/api/apperr"
+	"masterdom/api/middleware"
+)
+
+// --- Error Responses ---
+
+var (
+	errInvalidInput       = apperr.Validation("invalid_input", "Invalid input")
+	errUnauthorized       = apperr.Unauthorized("unauthorized", "Unauthorized")
+	errInvalidCredentials = apperr.Unauthorized("invalid_credentials", "Invalid credentials")
+	errNotOfferAuthor     = apperr.Forbidden("not_offer_author", "Only the author of the offer can do this")
+	errInvalidAttributes  = apperr.Validation("invalid_attributes", "Invalid attributes")
+	errInvalidFilter      = apperr.Validation("invalid_attribute_filter", "Invalid attribute filter")
+)
+
+func init() {
+	// Report validation failures by JSON field name rather than Go field name.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// invalidParam reports a path or query parameter that could not be parsed.
+func invalidParam(name string) *apperr.Error {
+	return apperr.Validation("invalid_parameter", "Invalid parameter "+name).With("name", name)
+}
+
+// invalidInput converts a request binding error into errInvalidInput with a
+// field error for every field that failed validation or had the wrong JSON type.
+func invalidInput(err error) *apperr.Error {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make([]apperr.FieldError, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			fields = append(fields, fieldError(fe))
+		}
+		return errInvalidInput.WithFields(fields...).Wrap(err)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return errInvalidInput.WithFields(invalidType(typeErr.Field, jsonType(typeErr.Type))).Wrap(err)
+	}
+	return errInvalidInput.Wrap(err)
+}
+
+// fieldError maps a failed validation rule to a field error code. Length
+// limits get separate codes for strings and lists so clients can word them.
+func fieldError(fe validator.FieldError) apperr.FieldError {
+	_, field, _ := strings.Cut(fe.Namespace(), ".")
+	result := apperr.FieldError{Field: field, Code: fe.Tag()}
+
+	switch fe.Tag() {
+	case "required", "email":
+	case "http_url":
+		result.Code = "url"
+	case "min", "max":
+		switch fe.Kind() {
+		case reflect.String:
+			result.Code += "_length"
+		case reflect.Slice, reflect.Map, reflect.Array:
+			result.Code += "_items"
+		}
+		limit, err := strconv.Atoi(fe.Param())
+		if err != nil {
+			result.Params = map[string]interface{}{fe.Tag(): fe.Param()}
+			break
+		}
+		result.Params = map[string]interface{}{fe.Tag(): limit}
+	case "oneof":
+		result.Params = map[string]interface{}{"allowed": strings.Fields(fe.Param())}
+	default:
+		result.Code = "invalid"
+		result.Params = map[string]interface{}{"rule": fe.Tag()}
+	}
+	return result
+}
+
+// jsonType names the JSON type that decodes into t.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// respondError writes err as the error response. Domain errors keep their
+// status, code and parameters and get a message in the language from
+// Accept-Language. Anything else is reported as an internal error without
+// details; the cause is attached to the context so the access log records it.
+func respondError(c *gin.Context, err error) {
+	middleware.AbortWithError(c, err)
+}
@@ -1,44 +1,83 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
+
+	"masterdom/api/apperr"
+	"masterdom/api/config"
+	"masterdom/api/jwtkeys"
+	"masterdom/api/metrics"
 	"masterdom/api/models"
+	"masterdom/api/oidc"
 	"masterdom/api/store"
 	"masterdom/api/utils"
 )
 
 type Handler struct {
 	Store store.Store
+	// OfferTTL - срок публикации объявлений по умолчанию для каждого типа
+	OfferTTL models.OfferTTL
+	// OIDC - провайдеры для входа через OpenID Connect
+	OIDC oidc.Providers
+	// AppBaseURL - адрес веб-приложения, куда возвращается пользователь после входа через OIDC
+	AppBaseURL string
+	// Keys - ключи подписи токенов сессии
+	Keys *jwtkeys.KeySet
+	// TokenTTL - срок действия токена сессии
+	TokenTTL time.Duration
+	// OIDCLoginTTL - сколько пользователь может находиться на странице входа провайдера
+	OIDCLoginTTL time.Duration
+
+	// draining включается при остановке сервера, см. SetDraining
+	draining atomic.Bool
 }
 
-func NewHandler(s store.Store) *Handler {
-	return &Handler{Store: s}
+func NewHandler(s store.Store, cfg *config.Config, oidcProviders oidc.Providers, keys *jwtkeys.KeySet) *Handler {
+	day := 24 * time.Hour
+	return &Handler{
+		Store: s,
+		OfferTTL: models.OfferTTL{
+			"request_for_service": time.Duration(cfg.Offers.RequestTTLDays) * day,
+			"service_offer":       time.Duration(cfg.Offers.ServiceTTLDays) * day,
+		},
+		OIDC:         oidcProviders,
+		AppBaseURL:   cfg.AppBaseURL,
+		Keys:         keys,
+		TokenTTL:     cfg.Auth.TokenTTL,
+		OIDCLoginTTL: cfg.Auth.OIDCLoginTTL,
+	}
 }
 
 func (h *Handler) Register(c *gin.Context) {
 	var payload models.RegisterPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid input", "details": err.Error()})
+		respondError(c, invalidInput(err))
 		return
 	}
+	if payload.Locale == "" {
+		payload.Locale = utils.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(payload.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to hash password"})
+		respondError(c, fmt.Errorf("failed to hash password: %w", err))
 		return
 	}
 
 	userID, err := h.Store.CreateUser(c.Request.Context(), payload, string(hashedPassword))
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to register user", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
+	metrics.UsersRegistered.Inc()
 
 	c.JSON(201, gin.H{"message": "User registered successfully", "userId": userID})
 }
@@ -46,41 +85,59 @@ func (h *Handler) Register(c *gin.Context) {
 func (h *Handler) Login(c *gin.Context) {
 	var payload models.LoginPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid input"})
+		respondError(c, invalidInput(err))
 		return
 	}
 
 	user, err := h.Store.GetUserByEmail(c.Request.Context(), payload.Email)
+	if errors.Is(err, store.ErrUserNotFound) {
+		respondError(c, errInvalidCredentials)
+		return
+	}
 	if err != nil {
-		c.JSON(401, gin.H{"error": "Invalid credentials"})
+		respondError(c, err)
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(payload.Password)); err != nil {
-		c.JSON(401, gin.H{"error": "Invalid credentials"})
+		respondError(c, errInvalidCredentials)
+		return
+	}
+
+	if user.IsBlocked(time.Now()) {
+		respondError(c, store.AccountBlocked(user.AccountStatus))
+		return
+	}
+
+	tokenString, err := h.issueToken(user)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to create token: %w", err))
 		return
 	}
 
-	isAdmin := user.Role == "admin"
+	c.JSON(200, gin.H{"token": tokenString, "isAdmin": user.Role == "admin"})
+}
 
-	expirationTime := time.Now().Add(24 * time.Hour)
+// issueToken creates the session token returned after a successful login.
+func (h *Handler) issueToken(user *models.User) (string, error) {
+	now := time.Now()
 	claims := &models.Claims{
 		UserID:  user.ID,
 		Email:   user.Email,
-		IsAdmin: isAdmin,
+		IsAdmin: user.Role == "admin",
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(h.TokenTTL)),
 		},
 	}
+	return h.Keys.Sign(claims)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(utils.GetJWTKey())
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to create token"})
-		return
-	}
-
-	c.JSON(200, gin.H{"token": tokenString, "isAdmin": isAdmin})
+// GetJWKS publishes the public keys other services use to verify session tokens.
+func (h *Handler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.Keys.JWKS())
 }
 
 // ... other handlers are mostly fine, just ensure they call the correct store methods ...
@@ -88,21 +145,55 @@ func (h *Handler) Login(c *gin.Context) {
 func (h *Handler) CreateOffer(c *gin.Context) {
 	var payload models.CreateOfferPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid input", "details": err.Error()})
+		respondError(c, invalidInput(err))
 		return
 	}
 
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(401, gin.H{"error": "Unauthorized"})
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	// Validate category-specific attributes against the category schema
+	var schema []models.CategoryAttribute
+	if payload.CategoryID != nil {
+		var err error
+		schema, err = h.Store.GetCategoryAttributes(c.Request.Context(), *payload.CategoryID)
+		if errors.Is(err, store.ErrCategoryNotFound) {
+			// The category is part of the payload, not the resource being addressed
+			respondError(c, store.ErrRelatedCategoryNotFound)
+			return
+		}
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+	attributes, fields := validateOfferAttributes(schema, payload.Attributes)
+	if len(fields) > 0 {
+		respondError(c, errInvalidAttributes.WithFields(fields...))
+		return
+	}
+	payload.Attributes = attributes
+
+	// The author may shorten the publication period but not extend it past the default
+	now := time.Now()
+	maxExpiresAt := now.Add(h.OfferTTL.For(payload.OfferType))
+	if payload.ExpiresAt == nil {
+		payload.ExpiresAt = &maxExpiresAt
+	} else if !payload.ExpiresAt.After(now) || payload.ExpiresAt.After(maxExpiresAt) {
+		respondError(c, apperr.Validation("invalid_expiration", "Expiration must be in the future and no later than "+maxExpiresAt.Format(time.RFC3339)).
+			With("max", maxExpiresAt.UTC().Format(time.RFC3339)))
 		return
 	}
 
 	offerID, err := h.Store.CreateOffer(c.Request.Context(), userID.(string), payload)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create offer", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
+	metrics.OffersCreated.WithLabelValues(payload.OfferType).Inc()
 
 	c.JSON(http.StatusCreated, gin.H{"offerId": offerID})
 }
@@ -111,13 +202,13 @@ func (h *Handler) RespondToOffer(c *gin.Context) {
 	offerID := c.Param("id")
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, errUnauthorized)
 		return
 	}
 
 	var payload models.RespondToOfferPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		respondError(c, invalidInput(err))
 		return
 	}
 
@@ -129,60 +220,123 @@ func (h *Handler) RespondToOffer(c *gin.Context) {
 
 	responseID, err := h.Store.CreateOfferResponse(c.Request.Context(), &response)
 	if err != nil {
-		if err.Error() == "response already exists" {
-			c.JSON(http.StatusConflict, gin.H{"error": "You have already responded to this offer"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create offer response", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
+	metrics.Applications.Inc()
 
 	c.JSON(http.StatusCreated, gin.H{"responseId": responseID})
 }
 
+func (h *Handler) RenewOffer(c *gin.Context) {
+	offerID := c.Param("id")
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	authorID, err := h.Store.GetOfferAuthor(c.Request.Context(), offerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if authorID != userID.(string) {
+		respondError(c, errNotOfferAuthor)
+		return
+	}
+
+	expiresAt, err := h.Store.RenewOffer(c.Request.Context(), offerID, h.OfferTTL)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Offer renewed successfully", "expiresAt": expiresAt})
+}
+
 func (h *Handler) GetOfferApplications(c *gin.Context) {
 	offerID := c.Param("id")
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, errUnauthorized)
 		return
 	}
 
 	// Verify that the current user is the author of the offer
 	authorID, err := h.Store.GetOfferAuthor(c.Request.Context(), offerID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		respondError(c, err)
 		return
 	}
 	if authorID != userID.(string) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view applications for this offer"})
+		respondError(c, errNotOfferAuthor)
 		return
 	}
 
 	applications, err := h.Store.GetOfferApplications(c.Request.Context(), offerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch offer applications", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, applications)
 }
 
+func (h *Handler) UpdateApplicationStatus(c *gin.Context) {
+	offerID := c.Param("id")
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	var payload models.UpdateApplicationStatusPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+
+	// Only the author of the offer decides on its applications
+	authorID, err := h.Store.GetOfferAuthor(c.Request.Context(), offerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if authorID != userID.(string) {
+		respondError(c, errNotOfferAuthor)
+		return
+	}
+
+	if err := h.Store.UpdateApplicationStatus(c.Request.Context(), offerID, c.Param("applicationId"), payload.Status); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Application status updated successfully"})
+}
+
 func (h *Handler) GetOffers(c *gin.Context) {
-	offerType := c.Query("type")
-	search := c.Query("search")
-	category := c.Query("category")
+	filter := models.OfferFilter{
+		OfferType: c.Query("type"),
+		Search:    c.Query("search"),
+		Category:  c.Query("category"),
+	}
+
+	attributes, fields := parseAttributeFilters(c)
+	if len(fields) > 0 {
+		respondError(c, errInvalidFilter.WithFields(fields...))
+		return
+	}
+	filter.Attributes = attributes
 
 	// userID может быть nil, если пользователь не аутентифицирован
-	var userID *string
 	if id, exists := c.Get("userID"); exists {
 		idStr := id.(string)
-		userID = &idStr
+		filter.ViewerID = &idStr
 	}
 
-	offers, err := h.Store.GetOffers(c.Request.Context(), offerType, search, category, userID)
+	offers, err := h.Store.GetOffers(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch offers", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -191,7 +345,7 @@ func (h *Handler) GetOffers(c *gin.Context) {
 func (h *Handler) GetUsers(c *gin.Context) {
 	users, err := h.Store.GetAllUsers(c.Request.Context())
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to fetch users", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(200, users)
@@ -201,7 +355,7 @@ func (h *Handler) GetUserByID(c *gin.Context) {
 	userID := c.Param("id")
 	user, err := h.Store.GetUserDetailByID(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(404, gin.H{"error": "User not found", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(200, user)
@@ -211,7 +365,7 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 	userID := c.Param("id")
 	var payload models.UpdateUserPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid input", "details": err.Error()})
+		respondError(c, invalidInput(err))
 		return
 	}
 
@@ -220,20 +374,20 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 		// Get the ID of the admin making the request from the token
 		loggedInUserID, exists := c.Get("userID")
 		if !exists {
-			c.JSON(401, gin.H{"error": "Unauthorized"})
+			respondError(c, errUnauthorized)
 			return
 		}
 
 		// Prevent an admin from demoting themselves
 		if loggedInUserID.(string) == userID {
-			c.JSON(403, gin.H{"error": "Cannot remove admin rights from yourself"})
+			respondError(c, apperr.Forbidden("self_demotion", "Cannot remove admin rights from yourself"))
 			return
 		}
 
 		// Check if the user making the request is the super admin
 		loggedInUserEmail, err := h.Store.GetUserEmailByID(c.Request.Context(), loggedInUserID.(string))
 		if err != nil {
-			c.JSON(500, gin.H{"error": "Could not verify requesting user's identity"})
+			respondError(c, err)
 			return
 		}
 
@@ -242,11 +396,11 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 			// Check if the target user is currently an admin
 			isTargetAdmin, err := h.Store.IsUserAdmin(c.Request.Context(), userID)
 			if err != nil {
-				c.JSON(500, gin.H{"error": "Could not check target user status"})
+				respondError(c, err)
 				return
 			}
 			if isTargetAdmin {
-				c.JSON(403, gin.H{"error": "Forbidden: Only the super admin can demote other administrators"})
+				respondError(c, apperr.Forbidden("super_admin_required", "Only the super admin can demote other administrators"))
 				return
 			}
 		}
@@ -254,7 +408,7 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 
 	err := h.Store.UpdateUserDetail(c.Request.Context(), userID, payload)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to update user", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(200, gin.H{"message": "User updated successfully"})
@@ -265,17 +419,17 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 
 	isTargetAdmin, err := h.Store.IsUserAdmin(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to check target user admin status", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	if isTargetAdmin {
-		c.JSON(403, gin.H{"error": "Cannot delete an administrator"})
+		respondError(c, apperr.Forbidden("admin_protected", "Cannot delete an administrator"))
 		return
 	}
 
 	err = h.Store.DeleteUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to delete user", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(200, gin.H{"message": "User deleted successfully"})
@@ -284,7 +438,7 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 func (h *Handler) GetAdminAllOffers(c *gin.Context) {
 	offers, err := h.Store.GetAllOffersForAdmin(c.Request.Context())
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to fetch offers for admin", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(200, offers)
@@ -294,18 +448,18 @@ func (h *Handler) UpdateOfferStatus(c *gin.Context) {
 	offerID := c.Param("id")
 	var payload models.UpdateOfferPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid input", "details": err.Error()})
+		respondError(c, invalidInput(err))
 		return
 	}
 
 	if payload.IsActive == nil {
-		c.JSON(400, gin.H{"error": "isActive field is required"})
+		respondError(c, apperr.Validation("is_active_required", "isActive field is required"))
 		return
 	}
 
 	err := h.Store.UpdateOfferStatus(c.Request.Context(), offerID, payload)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to update offer status", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(200, gin.H{"message": "Offer status updated successfully"})
@@ -315,79 +469,16 @@ func (h *Handler) DeleteOffer(c *gin.Context) {
 	offerID := c.Param("id")
 	err := h.Store.DeleteOffer(c.Request.Context(), offerID)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to delete offer", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(200, gin.H{"message": "Offer deleted successfully"})
 }
 
-func (h *Handler) GetAllCategories(c *gin.Context) {
-	categories, err := h.Store.GetAllCategories(c.Request.Context())
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to fetch categories", "details": err.Error()})
-		return
-	}
-	c.JSON(200, categories)
-}
-
-func (h *Handler) CreateCategory(c *gin.Context) {
-	var payload models.CategoryPayload
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid input", "details": err.Error()})
-		return
-	}
-
-	categoryID, err := h.Store.CreateCategory(c.Request.Context(), payload)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to create category", "details": err.Error()})
-		return
-	}
-
-	c.JSON(201, gin.H{"message": "Category created successfully", "categoryId": categoryID})
-}
-
-func (h *Handler) UpdateCategory(c *gin.Context) {
-	categoryID, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid category ID"})
-		return
-	}
-
-	var payload models.CategoryPayload
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid input", "details": err.Error()})
-		return
-	}
-
-	err = h.Store.UpdateCategory(c.Request.Context(), categoryID, payload)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to update category", "details": err.Error()})
-		return
-	}
-
-	c.JSON(200, gin.H{"message": "Category updated successfully"})
-}
-
-func (h *Handler) DeleteCategory(c *gin.Context) {
-	categoryID, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid category ID"})
-		return
-	}
-
-	err = h.Store.DeleteCategory(c.Request.Context(), categoryID)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to delete category", "details": err.Error()})
-		return
-	}
-
-	c.JSON(200, gin.H{"message": "Category deleted successfully"})
-}
-
 func (h *Handler) GetAdminStats(c *gin.Context) {
 	stats, err := h.Store.GetAdminStats(c.Request.Context())
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to get admin stats", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(200, stats)
@@ -396,13 +487,13 @@ func (h *Handler) GetAdminStats(c *gin.Context) {
 func (h *Handler) GetMyProfile(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(401, gin.H{"error": "Unauthorized"})
+		respondError(c, errUnauthorized)
 		return
 	}
 
 	user, err := h.Store.GetUserDetailByID(c.Request.Context(), userID.(string))
 	if err != nil {
-		c.JSON(404, gin.H{"error": "User not found", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(200, user)
@@ -411,13 +502,13 @@ func (h *Handler) GetMyProfile(c *gin.Context) {
 func (h *Handler) UpdateMyProfile(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(401, gin.H{"error": "Unauthorized"})
+		respondError(c, errUnauthorized)
 		return
 	}
 
 	var payload models.UpdateUserPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid input", "details": err.Error()})
+		respondError(c, invalidInput(err))
 		return
 	}
 
@@ -426,7 +517,7 @@ func (h *Handler) UpdateMyProfile(c *gin.Context) {
 
 	err := h.Store.UpdateUserDetail(c.Request.Context(), userID.(string), payload)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to update profile", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(200, gin.H{"message": "Profile updated successfully"})
@@ -437,25 +528,25 @@ func (h *Handler) UpdateMyProfile(c *gin.Context) {
 func (h *Handler) InitiateChat(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, errUnauthorized)
 		return
 	}
 
 	var payload models.InitiateChatPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		respondError(c, invalidInput(err))
 		return
 	}
 
 	// Ensure user is not trying to start a chat with themselves
 	if userID.(string) == payload.RecipientID {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot start a chat with yourself"})
+		respondError(c, apperr.Validation("chat_with_self", "Cannot start a chat with yourself"))
 		return
 	}
 
 	conversationID, err := h.Store.InitiateChat(c.Request.Context(), payload.OfferID, userID.(string), payload.RecipientID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initiate chat", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -465,24 +556,19 @@ func (h *Handler) InitiateChat(c *gin.Context) {
 func (h *Handler) GetChatDetails(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, errUnauthorized)
 		return
 	}
 
 	conversationID := c.Param("id")
 	if conversationID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Conversation ID is required"})
+		respondError(c, invalidParam("id"))
 		return
 	}
 
 	details, err := h.Store.GetChatDetails(c.Request.Context(), conversationID, userID.(string))
 	if err != nil {
-		// Check for specific "not a participant" error to return a 403
-		if err.Error() == "user is not a participant in this conversation" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chat details", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -492,23 +578,19 @@ func (h *Handler) GetChatDetails(c *gin.Context) {
 func (h *Handler) GetMessages(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, errUnauthorized)
 		return
 	}
 
 	conversationID := c.Param("id")
 	if conversationID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Conversation ID is required"})
+		respondError(c, invalidParam("id"))
 		return
 	}
 
 	messages, err := h.Store.GetMessages(c.Request.Context(), conversationID, userID.(string))
 	if err != nil {
-		if err.Error() == "user is not a participant in this conversation" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get messages", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -518,30 +600,29 @@ func (h *Handler) GetMessages(c *gin.Context) {
 func (h *Handler) PostMessage(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, errUnauthorized)
 		return
 	}
 
 	conversationID := c.Param("id")
 	if conversationID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Conversation ID is required"})
+		respondError(c, invalidParam("id"))
 		return
 	}
 
 	var payload models.SendMessagePayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		respondError(c, invalidInput(err))
 		return
 	}
 
-	// Here you might add a check to ensure the user is a participant before posting.
-	// The store method already does this, but an early check can be good.
-
+	// The store rejects senders who are not participants of the conversation
 	message, err := h.Store.PostMessage(c.Request.Context(), conversationID, userID.(string), payload.Content)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to post message", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
+	metrics.MessagesSent.Inc()
 
 	// TODO: Broadcast message via WebSocket to other participants
 
@@ -551,17 +632,15 @@ func (h *Handler) PostMessage(c *gin.Context) {
 func (h *Handler) GetConversations(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, errUnauthorized)
 		return
 	}
 
 	conversations, err := h.Store.GetConversations(c.Request.Context(), userID.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get conversations", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, conversations)
 }
-
-
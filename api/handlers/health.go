@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/db"
+)
+
+// --- Health Handlers ---
+
+const readinessTimeout = 2 * time.Second
+
+// SetDraining makes the readiness probe fail so the load balancer stops
+// sending new requests while the server shuts down.
+func (h *Handler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Livez reports that the process is running. It does not check dependencies,
+// so a database outage does not get the API restarted.
+func (h *Handler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the API can serve traffic: the database is reachable
+// and its schema is migrated at least to the version this build expects.
+func (h *Handler) Readyz(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting down"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := h.Store.Ping(ctx); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "database": "unreachable"})
+		return
+	}
+	version, dirty, err := h.Store.GetSchemaVersion(ctx)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "database": "unreachable"})
+		return
+	}
+	expected := db.SchemaVersion()
+	schema := gin.H{"version": version, "expected": expected, "dirty": dirty}
+	if dirty || version < expected {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "schema": schema})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "schema": schema})
+}
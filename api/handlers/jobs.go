@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/metrics"
+	"masterdom/api/models"
+)
+
+// --- Job Handlers ---
+
+func (h *Handler) GetMyJobs(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	jobs, err := h.Store.GetUserJobs(c.Request.Context(), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+// UpdateJobStatus lets the client or the master move the job forward or
+// cancel it; the other participant is notified.
+func (h *Handler) UpdateJobStatus(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	var payload models.UpdateJobStatusPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+
+	changed, err := h.Store.UpdateJobStatus(c.Request.Context(), c.Param("id"), userID.(string), payload.Status)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if changed && payload.Status == models.JobStatusCompleted {
+		metrics.JobsCompleted.Inc()
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Job status updated successfully"})
+}
+
+// CreateJobReview lets a participant of a completed job rate the other one.
+func (h *Handler) CreateJobReview(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	var payload models.CreateReviewPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+
+	reviewID, err := h.Store.CreateJobReview(c.Request.Context(), c.Param("id"), userID.(string), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"reviewId": reviewID})
+}
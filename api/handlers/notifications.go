@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/apperr"
+	"masterdom/api/models"
+)
+
+// --- Notification Handlers ---
+
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+)
+
+func (h *Handler) GetNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	filter := models.NotificationFilter{
+		UnreadOnly: c.Query("unread") == "true",
+		Limit:      defaultNotificationPageSize,
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			respondError(c, invalidParam("limit"))
+			return
+		}
+		filter.Limit = min(limit, maxNotificationPageSize)
+	}
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			respondError(c, invalidParam("offset"))
+			return
+		}
+		filter.Offset = offset
+	}
+
+	notifications, err := h.Store.GetNotifications(c.Request.Context(), userID.(string), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, notifications)
+}
+
+func (h *Handler) MarkNotificationRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	if err := h.Store.MarkNotificationRead(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+func (h *Handler) MarkAllNotificationsRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	updated, err := h.Store.MarkAllNotificationsRead(c.Request.Context(), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notifications marked as read", "updated": updated})
+}
+
+func (h *Handler) GetNotificationPreferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	preferences, err := h.Store.GetNotificationPreferences(c.Request.Context(), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, preferences)
+}
+
+// UpdateNotificationPreferences accepts a map of notification type to enabled
+// flag. Types that are not listed keep their current setting.
+func (h *Handler) UpdateNotificationPreferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	var payload map[string]bool
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+	for eventType := range payload {
+		if !slices.Contains(models.NotificationTypes, eventType) {
+			respondError(c, apperr.Validation("unknown_notification_type", "Unknown notification type: "+eventType).With("type", eventType))
+			return
+		}
+	}
+
+	if err := h.Store.UpdateNotificationPreferences(c.Request.Context(), userID.(string), payload); err != nil {
+		respondError(c, err)
+		return
+	}
+	h.GetNotificationPreferences(c)
+}
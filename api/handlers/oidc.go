@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/apperr"
+	"masterdom/api/logging"
+	"masterdom/api/metrics"
+	"masterdom/api/models"
+	"masterdom/api/oidc"
+	"masterdom/api/store"
+	"masterdom/api/utils"
+)
+
+// --- OpenID Connect Handlers ---
+
+// Error codes passed to the web app when an OIDC login fails
+const (
+	oidcErrorInvalidState    = "invalid_state"
+	oidcErrorExchangeFailed  = "exchange_failed"
+	oidcErrorEmailRequired   = "email_required"
+	oidcErrorEmailUnverified = "email_unverified"
+	oidcErrorAccountBanned   = "account_banned"
+	oidcErrorAccountBlocked  = "account_suspended"
+	oidcErrorServer          = "server_error"
+)
+
+var (
+	errUnknownProvider     = apperr.NotFound("unknown_provider", "Unknown identity provider")
+	errProviderUnavailable = apperr.Unavailable("provider_unavailable", "Identity provider is unavailable")
+)
+
+// GetOIDCProviders lists the providers that can be used to sign in.
+func (h *Handler) GetOIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, h.OIDC.List())
+}
+
+// OIDCLogin starts an authorization code flow with PKCE and redirects the
+// browser to the provider's login page.
+func (h *Handler) OIDCLogin(c *gin.Context) {
+	provider, ok := h.OIDC[c.Param("provider")]
+	if !ok {
+		respondError(c, errUnknownProvider)
+		return
+	}
+
+	state := models.OIDCLoginState{Provider: provider.Name, ExpiresAt: time.Now().Add(h.OIDCLoginTTL)}
+	var err error
+	for _, v := range []*string{&state.State, &state.Nonce, &state.CodeVerifier} {
+		if *v, err = oidc.RandomString(32); err != nil {
+			respondError(c, fmt.Errorf("failed to start login: %w", err))
+			return
+		}
+	}
+
+	authURL, err := provider.AuthCodeURL(c.Request.Context(), state.State, state.Nonce, state.CodeVerifier)
+	if err != nil {
+		respondError(c, errProviderUnavailable.Wrap(err))
+		return
+	}
+	if err := h.Store.SaveOIDCLoginState(c.Request.Context(), state); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback completes the login. The browser is sent back to the web app
+// with the session token, or an error code, in the URL fragment.
+func (h *Handler) OIDCCallback(c *gin.Context) {
+	provider, ok := h.OIDC[c.Param("provider")]
+	if !ok {
+		respondError(c, errUnknownProvider)
+		return
+	}
+	ctx := c.Request.Context()
+
+	// The state is consumed even if the provider reports an error, so it cannot be replayed
+	state, err := h.Store.ConsumeOIDCLoginState(ctx, c.Query("state"))
+	if err != nil || state.Provider != provider.Name {
+		h.oidcRedirect(c, url.Values{"error": {oidcErrorInvalidState}})
+		return
+	}
+	if providerError := c.Query("error"); providerError != "" {
+		h.oidcRedirect(c, url.Values{"error": {providerError}})
+		return
+	}
+
+	identity, err := provider.Exchange(ctx, c.Query("code"), state.CodeVerifier, state.Nonce)
+	if err != nil {
+		slog.WarnContext(ctx, "OIDC login failed", slog.String("provider", provider.Name), logging.Err(err))
+		h.oidcRedirect(c, url.Values{"error": {oidcErrorExchangeFailed}})
+		return
+	}
+	if identity.Locale == "" {
+		identity.Locale = utils.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	}
+
+	user, err := h.Store.ResolveOIDCUser(ctx, *identity)
+	switch {
+	case errors.Is(err, store.ErrIdentityEmailRequired):
+		h.oidcRedirect(c, url.Values{"error": {oidcErrorEmailRequired}})
+		return
+	case errors.Is(err, store.ErrIdentityEmailUnverified):
+		h.oidcRedirect(c, url.Values{"error": {oidcErrorEmailUnverified}})
+		return
+	case err != nil:
+		slog.ErrorContext(ctx, "Failed to resolve OIDC user",
+			slog.String("provider", provider.Name), slog.String("subject", identity.Subject), logging.Err(err))
+		h.oidcRedirect(c, url.Values{"error": {oidcErrorServer}})
+		return
+	}
+	if user.Created {
+		metrics.UsersRegistered.Inc()
+	}
+
+	if user.IsBlocked(time.Now()) {
+		code := oidcErrorAccountBlocked
+		if user.Status == models.UserStatusBanned {
+			code = oidcErrorAccountBanned
+		}
+		h.oidcRedirect(c, url.Values{"error": {code}})
+		return
+	}
+
+	token, err := h.issueToken(user)
+	if err != nil {
+		h.oidcRedirect(c, url.Values{"error": {oidcErrorServer}})
+		return
+	}
+	h.oidcRedirect(c, url.Values{"token": {token}, "isAdmin": {strconv.FormatBool(user.Role == "admin")}})
+}
+
+// oidcRedirect returns the browser to the web app. Values go into the fragment
+// so the token does not reach server logs or the Referer header.
+func (h *Handler) oidcRedirect(c *gin.Context, values url.Values) {
+	c.Redirect(http.StatusFound, strings.TrimSuffix(h.AppBaseURL, "/")+"/auth/callback#"+values.Encode())
+}
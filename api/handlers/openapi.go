@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/openapi"
+)
+
+// --- OpenAPI Handlers ---
+
+// GetOpenAPI serves the OpenAPI document describing every route of the API.
+func (h *Handler) GetOpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openapi.JSON())
+}
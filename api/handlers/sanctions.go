@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/apperr"
+	"masterdom/api/models"
+)
+
+// --- User Sanction Handlers ---
+
+// checkSanctionTarget verifies that the requesting admin may sanction the target user.
+// It writes the error response and returns false when the action is not allowed.
+func (h *Handler) checkSanctionTarget(c *gin.Context, targetID string) (string, bool) {
+	actorID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, errUnauthorized)
+		return "", false
+	}
+	if actorID.(string) == targetID {
+		respondError(c, apperr.Forbidden("self_sanction", "Cannot sanction yourself"))
+		return "", false
+	}
+
+	isTargetAdmin, err := h.Store.IsUserAdmin(c.Request.Context(), targetID)
+	if err != nil {
+		respondError(c, err)
+		return "", false
+	}
+	if isTargetAdmin {
+		respondError(c, apperr.Forbidden("admin_protected", "Cannot sanction an administrator"))
+		return "", false
+	}
+	return actorID.(string), true
+}
+
+func (h *Handler) SuspendUser(c *gin.Context) {
+	userID := c.Param("id")
+	var payload models.SuspendUserPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+	if !payload.Until.After(time.Now()) {
+		respondError(c, apperr.Validation("invalid_suspension_end", "Suspension end must be in the future"))
+		return
+	}
+
+	actorID, ok := h.checkSanctionTarget(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.Store.SuspendUser(c.Request.Context(), userID, actorID, payload); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User suspended successfully"})
+}
+
+func (h *Handler) BanUser(c *gin.Context) {
+	userID := c.Param("id")
+	var payload models.BanUserPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+
+	actorID, ok := h.checkSanctionTarget(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.Store.BanUser(c.Request.Context(), userID, actorID, payload); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User banned successfully"})
+}
+
+func (h *Handler) LiftUserSanction(c *gin.Context) {
+	userID := c.Param("id")
+	var payload models.LiftSanctionPayload
+	// The reason is optional, so an empty body is accepted
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			respondError(c, invalidInput(err))
+			return
+		}
+	}
+
+	actorID, ok := h.checkSanctionTarget(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.Store.LiftUserSanction(c.Request.Context(), userID, actorID, payload); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sanction lifted successfully"})
+}
+
+func (h *Handler) GetUserSanctions(c *gin.Context) {
+	sanctions, err := h.Store.GetUserSanctions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sanctions)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Soft Delete Handlers ---
+
+func (h *Handler) RestoreUser(c *gin.Context) {
+	err := h.Store.RestoreUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User restored successfully"})
+}
+
+func (h *Handler) RestoreOffer(c *gin.Context) {
+	err := h.Store.RestoreOffer(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Offer restored successfully"})
+}
+
+func (h *Handler) RestoreCategory(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, invalidParam("id"))
+		return
+	}
+
+	err = h.Store.RestoreCategory(c.Request.Context(), categoryID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Category restored successfully"})
+}
+
+func (h *Handler) GetTrash(c *gin.Context) {
+	trash, err := h.Store.GetTrash(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, trash)
+}
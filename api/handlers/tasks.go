@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/models"
+)
+
+// --- Background Task Handlers ---
+
+const (
+	defaultTaskPageSize = 50
+	maxTaskPageSize     = 500
+)
+
+var taskStatuses = []string{models.TaskStatusPending, models.TaskStatusRunning, models.TaskStatusSucceeded, models.TaskStatusDead}
+
+func (h *Handler) GetTasks(c *gin.Context) {
+	filter := models.TaskFilter{
+		Status: c.Query("status"),
+		Kind:   c.Query("kind"),
+		Limit:  defaultTaskPageSize,
+	}
+	if filter.Status != "" && !slices.Contains(taskStatuses, filter.Status) {
+		respondError(c, invalidParam("status"))
+		return
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			respondError(c, invalidParam("limit"))
+			return
+		}
+		filter.Limit = min(limit, maxTaskPageSize)
+	}
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			respondError(c, invalidParam("offset"))
+			return
+		}
+		filter.Offset = offset
+	}
+
+	tasks, err := h.Store.GetTasks(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+func (h *Handler) GetTask(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, invalidParam("id"))
+		return
+	}
+
+	task, err := h.Store.GetTask(c.Request.Context(), taskID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// RetryTask requeues a dead task with its attempt counter reset.
+func (h *Handler) RetryTask(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, invalidParam("id"))
+		return
+	}
+
+	if err := h.Store.RetryTask(c.Request.Context(), taskID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Task queued for retry"})
+}
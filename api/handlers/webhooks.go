@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/models"
+)
+
+// --- Webhook Handlers ---
+
+const (
+	defaultDeliveryPageSize = 50
+	maxDeliveryPageSize     = 500
+)
+
+// newWebhookSecret generates a random signing secret.
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func webhookID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, invalidParam("id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *Handler) GetWebhooks(c *gin.Context) {
+	webhooks, err := h.Store.GetWebhooks(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, webhooks)
+}
+
+func (h *Handler) GetWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	webhook, err := h.Store.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	webhook.Secret = ""
+	c.JSON(http.StatusOK, webhook)
+}
+
+// CreateWebhook registers a webhook and returns its signing secret. The secret
+// is not shown again; it can only be replaced via rotate-secret.
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	var payload models.WebhookPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to generate webhook secret: %w", err))
+		return
+	}
+
+	id, err := h.Store.CreateWebhook(c.Request.Context(), payload, secret, c.GetString("userID"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Webhook created successfully", "webhookId": id, "secret": secret})
+}
+
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	var payload models.WebhookPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondError(c, invalidInput(err))
+		return
+	}
+
+	if err := h.Store.UpdateWebhook(c.Request.Context(), id, payload); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook updated successfully"})
+}
+
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	if err := h.Store.DeleteWebhook(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+func (h *Handler) RotateWebhookSecret(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to generate webhook secret: %w", err))
+		return
+	}
+	if err := h.Store.RotateWebhookSecret(c.Request.Context(), id, secret); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook secret rotated successfully", "secret": secret})
+}
+
+// TestWebhook queues a single webhook.test event for the webhook. The result
+// appears in the delivery log.
+func (h *Handler) TestWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	eventID, err := h.Store.EnqueueWebhookTest(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Test event queued", "eventId": eventID})
+}
+
+func (h *Handler) GetWebhookDeliveries(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	limit := defaultDeliveryPageSize
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			respondError(c, invalidParam("limit"))
+			return
+		}
+		limit = min(parsed, maxDeliveryPageSize)
+	}
+	offset := 0
+	if value := c.Query("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			respondError(c, invalidParam("offset"))
+			return
+		}
+		offset = parsed
+	}
+
+	deliveries, err := h.Store.GetWebhookDeliveries(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
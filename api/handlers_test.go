@@ -0,0 +1,936 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"masterdom/api/client"
+	"masterdom/api/handlers"
+	"masterdom/api/images"
+	"masterdom/api/jwtkeys"
+	"masterdom/api/mailer"
+	"masterdom/api/maintenance"
+	"masterdom/api/metrics"
+	"masterdom/api/models"
+	"masterdom/api/oidc"
+	"masterdom/api/queue"
+	"masterdom/api/store"
+)
+
+// Тесты обработчиков обращаются к серверу с хранилищем в памяти через
+// сгенерированный клиент, поэтому заодно проверяют, что ответы соответствуют
+// документу OpenAPI. Маршруты, которые вызывались в тестах, запоминаются:
+// при полном прогоне TestMain проверяет, что не пропущен ни один.
+
+var (
+	exercisedMu sync.Mutex
+	exercised   = make(map[string]bool)
+)
+
+func recordRoute(c *gin.Context) {
+	c.Next()
+	if route := c.FullPath(); route != "" {
+		exercisedMu.Lock()
+		exercised[c.Request.Method+" "+route] = true
+		exercisedMu.Unlock()
+	}
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	code := m.Run()
+	if code == 0 && flag.Lookup("test.run").Value.String() == "" {
+		var missing []string
+		for _, route := range newTestRouter().Routes() {
+			if !exercised[route.Method+" "+route.Path] {
+				missing = append(missing, route.Method+" "+route.Path)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			fmt.Fprintf(os.Stderr, "routes without handler tests:\n  %s\n", strings.Join(missing, "\n  "))
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
+
+// testServer - сервер API с хранилищем в памяти
+type testServer struct {
+	*httptest.Server
+	store   *store.MemoryStore
+	handler *handlers.Handler
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	keys, err := jwtkeys.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate keys: %v", err)
+	}
+	memStore := store.NewMemoryStore()
+	h := &handlers.Handler{
+		Store: memStore,
+		OfferTTL: models.OfferTTL{
+			"request_for_service": 30 * 24 * time.Hour,
+			"service_offer":       90 * 24 * time.Hour,
+		},
+		AppBaseURL:   "https://app.example.com",
+		Keys:         keys,
+		TokenTTL:     time.Hour,
+		OIDCLoginTTL: 10 * time.Minute,
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(recordRoute)
+	registerRoutes(r, h, memStore, keys)
+
+	srv := &testServer{Server: httptest.NewServer(r), store: memStore, handler: h}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (s *testServer) client(opts ...client.Option) *client.Client {
+	return client.New(s.URL, opts...)
+}
+
+var userCounter atomic.Int64
+
+// testUser - зарегистрированный пользователь и клиент с его токеном
+type testUser struct {
+	ID     string
+	Email  string
+	Client *client.Client
+}
+
+const testPassword = "password123"
+
+func (s *testServer) register(t *testing.T, firstName string) testUser {
+	t.Helper()
+	ctx := context.Background()
+	email := fmt.Sprintf("%s-%d@example.com", strings.ToLower(firstName), userCounter.Add(1))
+	registered, err := s.client().Register(ctx, client.RegisterPayload{Email: email, Password: testPassword, FirstName: firstName})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return testUser{ID: registered.UserID, Email: email, Client: s.login(t, email)}
+}
+
+func (s *testServer) login(t *testing.T, email string) *client.Client {
+	t.Helper()
+	result, err := s.client().Login(context.Background(), client.LoginPayload{Email: email, Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return s.client(client.WithToken(result.Token))
+}
+
+// admin регистрирует пользователя с ролью администратора
+func (s *testServer) admin(t *testing.T) testUser {
+	t.Helper()
+	user := s.register(t, "Admin")
+	isAdmin := true
+	if err := s.store.UpdateUserDetail(context.Background(), user.ID, models.UpdateUserPayload{IsAdmin: &isAdmin}); err != nil {
+		t.Fatalf("failed to grant admin: %v", err)
+	}
+	user.Client = s.login(t, user.Email)
+	return user
+}
+
+func (s *testServer) createOffer(t *testing.T, author testUser, title string) string {
+	t.Helper()
+	created, err := author.Client.CreateOffer(context.Background(), client.CreateOfferPayload{Title: title, OfferType: "service_offer"})
+	if err != nil {
+		t.Fatalf("CreateOffer: %v", err)
+	}
+	return created.OfferID
+}
+
+// runTasks выполняет обработчиком handler все задачи вида kind из очереди,
+// как это сделал бы воркер очереди
+func (s *testServer) runTasks(t *testing.T, kind string, handler queue.Handler) {
+	t.Helper()
+	ctx := context.Background()
+	for {
+		task, err := s.store.ClaimTask(ctx, []string{kind}, "test", time.Minute)
+		if err != nil {
+			t.Fatalf("ClaimTask: %v", err)
+		}
+		if task == nil {
+			return
+		}
+		if err := handler(ctx, *task); err != nil {
+			t.Fatalf("%s task %d: %v", kind, task.ID, err)
+		}
+		if err := s.store.CompleteTask(ctx, task.ID); err != nil {
+			t.Fatalf("CompleteTask: %v", err)
+		}
+	}
+}
+
+// counterValue возвращает текущее значение счетчика Prometheus
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := counter.Write(&m); err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+// recordingSender запоминает отправленные письма
+type recordingSender struct {
+	sent []mailer.Message
+}
+
+func (r *recordingSender) Send(ctx context.Context, msg mailer.Message) error {
+	r.sent = append(r.sent, msg)
+	return nil
+}
+
+// expectStatus проверяет, что запрос завершился ошибкой API с кодом status
+func expectStatus(t *testing.T, op string, err error, status int) {
+	t.Helper()
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		t.Errorf("%s: error = %v, want status %d", op, err, status)
+		return
+	}
+	if apiErr.StatusCode != status {
+		t.Errorf("%s: status = %d (%s), want %d", op, apiErr.StatusCode, apiErr.Response.Code, status)
+	}
+}
+
+func must[T any](t *testing.T, op string) func(T, error) T {
+	return func(v T, err error) T {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", op, err)
+		}
+		return v
+	}
+}
+
+func TestHealthAndDiscovery(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	c := srv.client()
+
+	if status := must[*client.HealthStatus](t, "Livez")(c.Livez(ctx)); status.Status != "ok" {
+		t.Errorf("Livez = %+v", status)
+	}
+	ready := must[*client.Readiness](t, "Readyz")(c.Readyz(ctx))
+	if ready.Status != "ok" || ready.Schema == nil || ready.Schema.Version != ready.Schema.Expected {
+		t.Errorf("Readyz = %+v", ready)
+	}
+	must[*client.Readiness](t, "Health")(c.Health(ctx))
+	if doc := must[map[string]interface{}](t, "GetOpenAPI")(c.GetOpenAPI(ctx)); doc["openapi"] == nil {
+		t.Error("GetOpenAPI returned a document without the openapi field")
+	}
+	jwks := must[*client.JWKSet](t, "GetJWKS")(c.GetJWKS(ctx))
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != srv.handler.Keys.SigningKeyID() {
+		t.Errorf("GetJWKS = %+v", jwks)
+	}
+
+	srv.handler.SetDraining()
+	_, err := c.Readyz(ctx)
+	expectStatus(t, "Readyz while draining", err, http.StatusServiceUnavailable)
+}
+
+func TestRegisterAndProfile(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	user := srv.register(t, "Anna")
+
+	_, err := srv.client().Register(ctx, client.RegisterPayload{Email: user.Email, Password: testPassword, FirstName: "Anna"})
+	expectStatus(t, "Register with taken email", err, http.StatusConflict)
+	_, err = srv.client().Register(ctx, client.RegisterPayload{Email: "not-an-email", Password: "short", FirstName: "Bad"})
+	expectStatus(t, "Register with invalid payload", err, http.StatusBadRequest)
+	_, err = srv.client().Login(ctx, client.LoginPayload{Email: user.Email, Password: "wrong-password"})
+	expectStatus(t, "Login with wrong password", err, http.StatusUnauthorized)
+	_, err = srv.client().GetMyProfile(ctx)
+	expectStatus(t, "GetMyProfile without token", err, http.StatusUnauthorized)
+
+	bio := "Electrician"
+	must[*client.ActionResult](t, "UpdateMyProfile")(user.Client.UpdateMyProfile(ctx, client.UpdateUserPayload{Bio: &bio}))
+	profile := must[*client.UserDetail](t, "GetMyProfile")(user.Client.GetMyProfile(ctx))
+	if profile.ID != user.ID || profile.Email != user.Email || profile.Bio == nil || *profile.Bio != bio {
+		t.Errorf("GetMyProfile = %+v", profile)
+	}
+
+	// Пользователь не может назначить себя администратором: поле игнорируется
+	isAdmin := true
+	must[*client.ActionResult](t, "UpdateMyProfile")(user.Client.UpdateMyProfile(ctx, client.UpdateUserPayload{IsAdmin: &isAdmin}))
+	if profile := must[*client.UserDetail](t, "GetMyProfile")(user.Client.GetMyProfile(ctx)); profile.Role != "user" {
+		t.Errorf("role after UpdateMyProfile with isAdmin = %q, want user", profile.Role)
+	}
+}
+
+func TestOffersAndApplications(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	author := srv.register(t, "Author")
+	applicant := srv.register(t, "Applicant")
+	offerID := srv.createOffer(t, author, "Fix the roof")
+
+	offers := must[[]client.OfferResponse](t, "GetOffers")(applicant.Client.GetOffers(ctx, &client.GetOffersParams{Search: "roof"}))
+	if len(offers) != 1 || offers[0].ID != offerID || offers[0].HasResponded || offers[0].ExpiresAt == nil {
+		t.Fatalf("GetOffers = %+v", offers)
+	}
+	if offers := must[[]client.OfferResponse](t, "GetOffers")(srv.client().GetOffers(ctx, &client.GetOffersParams{Type: "request_for_service"})); len(offers) != 0 {
+		t.Errorf("GetOffers by another type = %+v", offers)
+	}
+
+	created := must[*client.ApplicationCreated](t, "RespondToOffer")(
+		applicant.Client.RespondToOffer(ctx, offerID, client.RespondToOfferPayload{Message: "I can do it"}))
+	_, err := applicant.Client.RespondToOffer(ctx, offerID, client.RespondToOfferPayload{Message: "Again"})
+	expectStatus(t, "second RespondToOffer", err, http.StatusConflict)
+
+	offers = must[[]client.OfferResponse](t, "GetOffers")(applicant.Client.GetOffers(ctx, &client.GetOffersParams{Search: "roof"}))
+	if len(offers) != 1 || !offers[0].HasResponded {
+		t.Errorf("HasResponded is not set after responding: %+v", offers)
+	}
+
+	_, err = applicant.Client.GetOfferApplications(ctx, offerID)
+	expectStatus(t, "GetOfferApplications by applicant", err, http.StatusForbidden)
+	applications := must[[]client.OfferApplication](t, "GetOfferApplications")(author.Client.GetOfferApplications(ctx, offerID))
+	if len(applications) != 1 || applications[0].ID != created.ResponseID || applications[0].ApplicantFirstName != "Applicant" {
+		t.Errorf("GetOfferApplications = %+v", applications)
+	}
+
+	_, err = applicant.Client.UpdateApplicationStatus(ctx, offerID, created.ResponseID, client.UpdateApplicationStatusPayload{Status: "accepted"})
+	expectStatus(t, "UpdateApplicationStatus by applicant", err, http.StatusForbidden)
+	must[*client.ActionResult](t, "UpdateApplicationStatus")(
+		author.Client.UpdateApplicationStatus(ctx, offerID, created.ResponseID, client.UpdateApplicationStatusPayload{Status: "accepted"}))
+
+	// Принятый отклик на предложение услуги начинает работу, где автор - мастер
+	jobs := must[[]client.Job](t, "GetMyJobs")(applicant.Client.GetMyJobs(ctx))
+	if len(jobs) != 1 || jobs[0].OfferID != offerID || jobs[0].ClientID != applicant.ID || jobs[0].MasterID != author.ID ||
+		jobs[0].Status != models.JobStatusAssigned || jobs[0].OfferTitle != "Fix the roof" {
+		t.Fatalf("GetMyJobs = %+v", jobs)
+	}
+	_, err = author.Client.UpdateJobStatus(ctx, jobs[0].ID, client.UpdateJobStatusPayload{Status: models.JobStatusCompleted})
+	expectStatus(t, "UpdateJobStatus skipping in_progress", err, http.StatusConflict)
+	outsider := srv.register(t, "Outsider")
+	_, err = outsider.Client.UpdateJobStatus(ctx, jobs[0].ID, client.UpdateJobStatusPayload{Status: models.JobStatusInProgress})
+	expectStatus(t, "UpdateJobStatus by an outsider", err, http.StatusNotFound)
+	must[*client.ActionResult](t, "UpdateJobStatus")(
+		author.Client.UpdateJobStatus(ctx, jobs[0].ID, client.UpdateJobStatusPayload{Status: models.JobStatusInProgress}))
+	completed := counterValue(t, metrics.JobsCompleted)
+	must[*client.ActionResult](t, "UpdateJobStatus")(
+		applicant.Client.UpdateJobStatus(ctx, jobs[0].ID, client.UpdateJobStatusPayload{Status: models.JobStatusCompleted}))
+	must[*client.ActionResult](t, "repeated UpdateJobStatus")(
+		author.Client.UpdateJobStatus(ctx, jobs[0].ID, client.UpdateJobStatusPayload{Status: models.JobStatusCompleted}))
+	if got := counterValue(t, metrics.JobsCompleted) - completed; got != 1 {
+		t.Errorf("jobs_completed_total grew by %v, want 1", got)
+	}
+	jobs = must[[]client.Job](t, "GetMyJobs")(author.Client.GetMyJobs(ctx))
+	if len(jobs) != 1 || jobs[0].Status != models.JobStatusCompleted || jobs[0].StartedAt == nil || jobs[0].CompletedAt == nil {
+		t.Errorf("GetMyJobs after completion = %+v", jobs)
+	}
+	notifications := must[*client.NotificationList](t, "GetNotifications")(author.Client.GetNotifications(ctx, nil))
+	if len(notifications.Items) == 0 || notifications.Items[0].Type != models.NotificationJobStatusChanged {
+		t.Errorf("author notifications after completion = %+v", notifications.Items)
+	}
+
+	must[*client.ReviewCreated](t, "CreateJobReview")(
+		applicant.Client.CreateJobReview(ctx, jobs[0].ID, client.CreateReviewPayload{Rating: 5}))
+	_, err = applicant.Client.CreateJobReview(ctx, jobs[0].ID, client.CreateReviewPayload{Rating: 4})
+	expectStatus(t, "second CreateJobReview", err, http.StatusConflict)
+	_, err = author.Client.CreateJobReview(ctx, jobs[0].ID, client.CreateReviewPayload{Rating: 6})
+	expectStatus(t, "CreateJobReview with rating 6", err, http.StatusBadRequest)
+	srv.runTasks(t, models.TaskKindRatingRecalculate, maintenance.RecalculateRatingHandler(srv.store))
+	profile := must[*client.UserDetail](t, "GetMyProfile")(author.Client.GetMyProfile(ctx))
+	if profile.AverageRating == nil || *profile.AverageRating != 5 {
+		t.Errorf("author rating after review = %v, want 5", profile.AverageRating)
+	}
+
+	_, err = applicant.Client.RenewOffer(ctx, offerID)
+	expectStatus(t, "RenewOffer by applicant", err, http.StatusForbidden)
+	renewed := must[*client.OfferRenewed](t, "RenewOffer")(author.Client.RenewOffer(ctx, offerID))
+	if !renewed.ExpiresAt.After(time.Now().Add(89 * 24 * time.Hour)) {
+		t.Errorf("RenewOffer expiresAt = %v, want the service_offer TTL", renewed.ExpiresAt)
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	author := srv.register(t, "Author")
+	applicant := srv.register(t, "Applicant")
+	offerID := srv.createOffer(t, author, "Paint the fence")
+	must[*client.ApplicationCreated](t, "RespondToOffer")(applicant.Client.RespondToOffer(ctx, offerID, client.RespondToOfferPayload{}))
+	srv.createOffer(t, author, "Second offer")
+	second := must[[]client.OfferResponse](t, "GetOffers")(author.Client.GetOffers(ctx, &client.GetOffersParams{Search: "second"}))
+	must[*client.ApplicationCreated](t, "RespondToOffer")(applicant.Client.RespondToOffer(ctx, second[0].ID, client.RespondToOfferPayload{}))
+
+	list := must[*client.NotificationList](t, "GetNotifications")(author.Client.GetNotifications(ctx, &client.GetNotificationsParams{Limit: 1}))
+	if len(list.Items) != 1 || list.UnreadCount != 2 || list.Items[0].Type != models.NotificationApplicationCreated {
+		t.Fatalf("GetNotifications = %+v", list)
+	}
+
+	_, err := applicant.Client.MarkNotificationRead(ctx, list.Items[0].ID)
+	expectStatus(t, "MarkNotificationRead by another user", err, http.StatusNotFound)
+	must[*client.ActionResult](t, "MarkNotificationRead")(author.Client.MarkNotificationRead(ctx, list.Items[0].ID))
+	marked := must[*client.NotificationsMarked](t, "MarkAllNotificationsRead")(author.Client.MarkAllNotificationsRead(ctx))
+	if marked.Updated != 1 {
+		t.Errorf("MarkAllNotificationsRead updated %d, want 1", marked.Updated)
+	}
+	list = must[*client.NotificationList](t, "GetNotifications")(author.Client.GetNotifications(ctx, &client.GetNotificationsParams{Unread: true}))
+	if len(list.Items) != 0 || list.UnreadCount != 0 {
+		t.Errorf("unread notifications after marking all read = %+v", list)
+	}
+
+	_, err = author.Client.UpdateNotificationPreferences(ctx, map[string]bool{"unknown.type": false})
+	expectStatus(t, "UpdateNotificationPreferences with unknown type", err, http.StatusBadRequest)
+	preferences := must[map[string]bool](t, "UpdateNotificationPreferences")(
+		author.Client.UpdateNotificationPreferences(ctx, map[string]bool{models.NotificationMessageCreated: false}))
+	if preferences[models.NotificationMessageCreated] || !preferences[models.NotificationApplicationCreated] {
+		t.Errorf("UpdateNotificationPreferences = %v", preferences)
+	}
+	preferences = must[map[string]bool](t, "GetNotificationPreferences")(author.Client.GetNotificationPreferences(ctx))
+	if preferences[models.NotificationMessageCreated] {
+		t.Errorf("GetNotificationPreferences = %v, want message.created disabled", preferences)
+	}
+
+	// Письма о новых откликах отправляются задачами очереди
+	sender := &recordingSender{}
+	deliverer := mailer.NewDeliverer(srv.store, sender, &mailer.Renderer{BaseURL: srv.handler.AppBaseURL})
+	srv.runTasks(t, models.TaskKindEmailSend, deliverer.Handle)
+	if len(sender.sent) != 2 || sender.sent[0].To != author.Email || sender.sent[0].Subject == "" {
+		t.Errorf("sent emails = %+v, want two to the author", sender.sent)
+	}
+	srv.runTasks(t, models.TaskKindEmailSend, deliverer.Handle)
+	if len(sender.sent) != 2 {
+		t.Errorf("%d emails sent after running the tasks again, want 2", len(sender.sent))
+	}
+}
+
+func TestAvatars(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	user := srv.register(t, "Pictured")
+
+	_, err := srv.client().GetUserAvatar(ctx, user.ID, nil)
+	expectStatus(t, "GetUserAvatar before upload", err, http.StatusNotFound)
+	_, err = user.Client.UploadMyAvatar(ctx, client.UploadAvatarPayload{Image: []byte("not an image")})
+	expectStatus(t, "UploadMyAvatar with text", err, http.StatusBadRequest)
+	_, err = user.Client.UploadMyAvatar(ctx, client.UploadAvatarPayload{Image: make([]byte, images.MaxBytes+1)})
+	expectStatus(t, "UploadMyAvatar with a large image", err, http.StatusBadRequest)
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 600, 300))); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	must[*client.ActionResult](t, "UploadMyAvatar")(user.Client.UploadMyAvatar(ctx, client.UploadAvatarPayload{Image: img.Bytes()}))
+
+	// Пока миниатюры нет, вместо нее отдается исходное изображение
+	thumbnail := must[[]byte](t, "GetUserAvatar")(srv.client().GetUserAvatar(ctx, user.ID, &client.GetUserAvatarParams{Size: "thumbnail"}))
+	if !bytes.Equal(thumbnail, img.Bytes()) {
+		t.Error("GetUserAvatar(thumbnail) before the task ran did not return the original image")
+	}
+	srv.runTasks(t, models.TaskKindAvatarThumbnail, images.ThumbnailHandler(srv.store))
+	thumbnail = must[[]byte](t, "GetUserAvatar")(srv.client().GetUserAvatar(ctx, user.ID, &client.GetUserAvatarParams{Size: "thumbnail"}))
+	config, format, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+	if err != nil || format != "jpeg" || config.Width != images.ThumbnailSize || config.Height != images.ThumbnailSize/2 {
+		t.Errorf("thumbnail = %s %dx%d, %v; want a %dx%d jpeg", format, config.Width, config.Height, err, images.ThumbnailSize, images.ThumbnailSize/2)
+	}
+	original := must[[]byte](t, "GetUserAvatar")(srv.client().GetUserAvatar(ctx, user.ID, nil))
+	if !bytes.Equal(original, img.Bytes()) {
+		t.Error("GetUserAvatar did not return the uploaded image")
+	}
+}
+
+func TestChat(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	author := srv.register(t, "Author")
+	client1 := srv.register(t, "Client")
+	outsider := srv.register(t, "Outsider")
+	offerID := srv.createOffer(t, author, "Install a sink")
+
+	_, err := author.Client.InitiateChat(ctx, client.InitiateChatPayload{OfferID: offerID, RecipientID: author.ID})
+	expectStatus(t, "InitiateChat with self", err, http.StatusBadRequest)
+	chat := must[*client.ChatInitiated](t, "InitiateChat")(
+		client1.Client.InitiateChat(ctx, client.InitiateChatPayload{OfferID: offerID, RecipientID: author.ID}))
+
+	_, err = outsider.Client.PostMessage(ctx, chat.ConversationID, client.SendMessagePayload{Content: "Hi"})
+	expectStatus(t, "PostMessage by outsider", err, http.StatusForbidden)
+	_, err = outsider.Client.GetMessages(ctx, chat.ConversationID)
+	expectStatus(t, "GetMessages by outsider", err, http.StatusForbidden)
+	_, err = outsider.Client.GetChatDetails(ctx, chat.ConversationID)
+	expectStatus(t, "GetChatDetails by outsider", err, http.StatusForbidden)
+
+	msg := must[*client.MessageResponse](t, "PostMessage")(
+		client1.Client.PostMessage(ctx, chat.ConversationID, client.SendMessagePayload{Content: "When can you come?"}))
+	if msg.SenderID != client1.ID || msg.SenderFirstName != "Client" {
+		t.Errorf("PostMessage = %+v", msg)
+	}
+	messages := must[[]client.MessageResponse](t, "GetMessages")(author.Client.GetMessages(ctx, chat.ConversationID))
+	if len(messages) != 1 || messages[0].ID != msg.ID {
+		t.Errorf("GetMessages = %+v", messages)
+	}
+	details := must[*client.ChatDetailsResponse](t, "GetChatDetails")(author.Client.GetChatDetails(ctx, chat.ConversationID))
+	if details.OfferID != offerID || details.OfferTitle != "Install a sink" || len(details.Participants) != 2 {
+		t.Errorf("GetChatDetails = %+v", details)
+	}
+	previews := must[[]client.ConversationPreview](t, "GetConversations")(author.Client.GetConversations(ctx))
+	if len(previews) != 1 || previews[0].OtherParticipantID != client1.ID || previews[0].LastMessageContent != msg.Content {
+		t.Errorf("GetConversations = %+v", previews)
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	user := srv.register(t, "Integrator")
+	admin := srv.admin(t)
+
+	created := must[*client.APIKeyCreated](t, "CreateAPIKey")(
+		user.Client.CreateAPIKey(ctx, client.CreateAPIKeyPayload{Name: "CI", Scopes: []string{models.ScopeOffersWrite}}))
+	keyClient := srv.client(client.WithAPIKey(created.Key))
+
+	// Ключ дает только свои права и не управляет ключами
+	srv.createOffer(t, testUser{Client: keyClient}, "Created with a key")
+	_, err := keyClient.GetMyProfile(ctx)
+	expectStatus(t, "GetMyProfile without the scope", err, http.StatusForbidden)
+	_, err = keyClient.GetMyAPIKeys(ctx)
+	expectStatus(t, "GetMyAPIKeys with a key", err, http.StatusForbidden)
+	_, err = keyClient.GetOffers(ctx, nil)
+	expectStatus(t, "GetOffers without the scope", err, http.StatusForbidden)
+
+	keys := must[[]client.APIKey](t, "GetMyAPIKeys")(user.Client.GetMyAPIKeys(ctx))
+	if len(keys) != 1 || keys[0].ID != created.APIKey.ID || keys[0].LastUsedAt == nil {
+		t.Errorf("GetMyAPIKeys = %+v", keys)
+	}
+	all := must[[]client.APIKey](t, "GetAllAPIKeys")(admin.Client.GetAllAPIKeys(ctx))
+	if len(all) != 1 || all[0].UserID != user.ID {
+		t.Errorf("GetAllAPIKeys = %+v", all)
+	}
+
+	_, err = admin.Client.RevokeMyAPIKey(ctx, created.APIKey.ID)
+	expectStatus(t, "RevokeMyAPIKey of another user's key", err, http.StatusNotFound)
+	must[*client.ActionResult](t, "RevokeMyAPIKey")(user.Client.RevokeMyAPIKey(ctx, created.APIKey.ID))
+	_, err = keyClient.CreateOffer(ctx, client.CreateOfferPayload{Title: "Revoked", OfferType: "service_offer"})
+	expectStatus(t, "CreateOffer with a revoked key", err, http.StatusUnauthorized)
+
+	second := must[*client.APIKeyCreated](t, "CreateAPIKey")(
+		user.Client.CreateAPIKey(ctx, client.CreateAPIKeyPayload{Name: "Other", Scopes: []string{models.ScopeOffersRead}}))
+	if offers := must[[]client.OfferResponse](t, "GetOffers with a key")(srv.client(client.WithAPIKey(second.Key)).GetOffers(ctx, nil)); len(offers) != 1 {
+		t.Errorf("GetOffers with a key returned %d offers, want 1", len(offers))
+	}
+	must[*client.ActionResult](t, "RevokeAPIKey")(admin.Client.RevokeAPIKey(ctx, second.APIKey.ID))
+	_, err = admin.Client.RevokeAPIKey(ctx, "00000000-0000-0000-0000-000000000000")
+	expectStatus(t, "RevokeAPIKey of unknown key", err, http.StatusNotFound)
+}
+
+func TestAdminUsers(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	admin := srv.admin(t)
+	user := srv.register(t, "Boris")
+
+	_, err := user.Client.GetUsers(ctx)
+	expectStatus(t, "GetUsers by non-admin", err, http.StatusForbidden)
+	if users := must[[]client.UserDetail](t, "GetUsers")(admin.Client.GetUsers(ctx)); len(users) != 2 {
+		t.Errorf("GetUsers returned %d users, want 2", len(users))
+	}
+
+	firstName := "Boris Jr"
+	must[*client.ActionResult](t, "UpdateUser")(admin.Client.UpdateUser(ctx, user.ID, client.UpdateUserPayload{FirstName: &firstName}))
+	detail := must[*client.UserDetail](t, "GetUserByID")(admin.Client.GetUserByID(ctx, user.ID))
+	if detail.FirstName == nil || *detail.FirstName != firstName {
+		t.Errorf("GetUserByID = %+v", detail)
+	}
+
+	until := time.Now().Add(time.Hour)
+	must[*client.ActionResult](t, "SuspendUser")(admin.Client.SuspendUser(ctx, user.ID, client.SuspendUserPayload{Reason: "spam", Until: until}))
+	_, err = user.Client.GetMyProfile(ctx)
+	expectStatus(t, "GetMyProfile while suspended", err, http.StatusForbidden)
+	must[*client.ActionResult](t, "LiftUserSanction")(admin.Client.LiftUserSanction(ctx, user.ID, &client.LiftSanctionPayload{Reason: "appeal"}))
+	must[*client.UserDetail](t, "GetMyProfile after lift")(user.Client.GetMyProfile(ctx))
+	must[*client.ActionResult](t, "BanUser")(admin.Client.BanUser(ctx, user.ID, client.BanUserPayload{Reason: "fraud"}))
+	_, err = srv.client().Login(ctx, client.LoginPayload{Email: user.Email, Password: testPassword})
+	expectStatus(t, "Login while banned", err, http.StatusForbidden)
+
+	sanctions := must[[]client.UserSanction](t, "GetUserSanctions")(admin.Client.GetUserSanctions(ctx, user.ID))
+	if len(sanctions) != 3 || sanctions[0].Action != "ban" {
+		t.Errorf("GetUserSanctions = %+v", sanctions)
+	}
+
+	_, err = admin.Client.DeleteUser(ctx, admin.ID)
+	expectStatus(t, "DeleteUser of an admin", err, http.StatusForbidden)
+	must[*client.ActionResult](t, "DeleteUser")(admin.Client.DeleteUser(ctx, user.ID))
+	trash := must[*client.TrashResponse](t, "GetTrash")(admin.Client.GetTrash(ctx))
+	if len(trash.Users) != 1 || trash.Users[0].ID != user.ID {
+		t.Errorf("GetTrash = %+v", trash)
+	}
+	must[*client.ActionResult](t, "RestoreUser")(admin.Client.RestoreUser(ctx, user.ID))
+	_, err = admin.Client.RestoreUser(ctx, user.ID)
+	expectStatus(t, "RestoreUser of a live user", err, http.StatusNotFound)
+
+	entries := must[[]client.AuditEntry](t, "GetAuditLog")(admin.Client.GetAuditLog(ctx, &client.GetAuditLogParams{TargetID: user.ID, Action: "user.ban"}))
+	if len(entries) != 1 || entries[0].ActorID == nil || *entries[0].ActorID != admin.ID || entries[0].ActorEmail == nil {
+		t.Errorf("GetAuditLog = %+v", entries)
+	}
+	csv := must[[]byte](t, "ExportAuditLog")(admin.Client.ExportAuditLog(ctx, &client.ExportAuditLogParams{TargetID: user.ID}))
+	if lines := strings.Split(strings.TrimSpace(string(csv)), "\n"); len(lines) != 7 {
+		t.Errorf("ExportAuditLog returned %d lines, want a header and 6 entries:\n%s", len(lines), csv)
+	}
+
+	stats := must[*client.AdminStats](t, "GetAdminStats")(admin.Client.GetAdminStats(ctx))
+	if stats.TotalUsers != 2 {
+		t.Errorf("GetAdminStats = %+v", stats)
+	}
+}
+
+func TestAdminOffers(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	admin := srv.admin(t)
+	author := srv.register(t, "Author")
+	offerID := srv.createOffer(t, author, "Lay tiles")
+
+	offers := must[[]client.AdminOfferResponse](t, "GetAdminAllOffers")(admin.Client.GetAdminAllOffers(ctx))
+	if len(offers) != 1 || offers[0].ID != offerID || offers[0].AuthorEmail != author.Email || !offers[0].IsActive {
+		t.Fatalf("GetAdminAllOffers = %+v", offers)
+	}
+
+	_, err := admin.Client.UpdateOfferStatus(ctx, offerID, client.UpdateOfferPayload{})
+	expectStatus(t, "UpdateOfferStatus without isActive", err, http.StatusBadRequest)
+	inactive := false
+	must[*client.ActionResult](t, "UpdateOfferStatus")(admin.Client.UpdateOfferStatus(ctx, offerID, client.UpdateOfferPayload{IsActive: &inactive}))
+	if offers := must[[]client.OfferResponse](t, "GetOffers")(srv.client().GetOffers(ctx, nil)); len(offers) != 0 {
+		t.Errorf("inactive offer is listed: %+v", offers)
+	}
+
+	must[*client.ActionResult](t, "DeleteOffer")(admin.Client.DeleteOffer(ctx, offerID))
+	if offers := must[[]client.AdminOfferResponse](t, "GetAdminAllOffers")(admin.Client.GetAdminAllOffers(ctx)); len(offers) != 0 {
+		t.Errorf("deleted offer is listed: %+v", offers)
+	}
+	must[*client.ActionResult](t, "RestoreOffer")(admin.Client.RestoreOffer(ctx, offerID))
+	_, err = admin.Client.RestoreOffer(ctx, offerID)
+	expectStatus(t, "RestoreOffer of a live offer", err, http.StatusNotFound)
+}
+
+func TestAdminCategories(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	admin := srv.admin(t)
+	author := srv.register(t, "Author")
+
+	_, err := author.Client.CreateCategory(ctx, client.CategoryPayload{Name: "Repair"})
+	expectStatus(t, "CreateCategory by non-admin", err, http.StatusForbidden)
+	parent := must[*client.CategoryCreated](t, "CreateCategory")(admin.Client.CreateCategory(ctx, client.CategoryPayload{
+		Name:         "Ремонт",
+		Translations: map[string]client.CategoryTranslation{"en": {Name: "Repair"}},
+	}))
+	child := must[*client.CategoryCreated](t, "CreateCategory")(admin.Client.CreateCategory(ctx, client.CategoryPayload{
+		Name: "Сантехника", ParentID: &parent.CategoryID,
+	}))
+	_, err = admin.Client.UpdateCategory(ctx, parent.CategoryID, client.UpdateCategoryPayload{ParentID: &child.CategoryID})
+	expectStatus(t, "UpdateCategory under its child", err, http.StatusBadRequest)
+	description := "Трубы и краны"
+	must[*client.ActionResult](t, "UpdateCategory")(admin.Client.UpdateCategory(ctx, child.CategoryID, client.UpdateCategoryPayload{
+		Description: &description,
+	}))
+
+	categories := must[[]client.ServiceCategory](t, "GetAllCategories")(srv.client().GetAllCategories(ctx, &client.GetAllCategoriesParams{Lang: "en"}))
+	if len(categories) != 2 {
+		t.Fatalf("GetAllCategories = %+v", categories)
+	}
+	for _, c := range categories {
+		if c.ID == parent.CategoryID && c.Name != "Repair" {
+			t.Errorf("parent category name in English = %q, want Repair", c.Name)
+		}
+	}
+	tree := must[[]client.CategoryNode](t, "GetCategoryTree")(srv.client().GetCategoryTree(ctx, nil))
+	if len(tree) != 1 || len(tree[0].Children) != 1 || tree[0].Children[0].Description != description {
+		t.Errorf("GetCategoryTree = %+v", tree)
+	}
+	admins := must[[]client.ServiceCategory](t, "GetAdminCategories")(admin.Client.GetAdminCategories(ctx))
+	if len(admins) != 2 {
+		t.Errorf("GetAdminCategories = %+v", admins)
+	}
+
+	attribute := must[*client.AttributeCreated](t, "CreateCategoryAttribute")(admin.Client.CreateCategoryAttribute(ctx, child.CategoryID,
+		client.CategoryAttributePayload{Key: "diameter", Label: "Диаметр", Type: models.AttributeTypeInteger}))
+	_, err = admin.Client.CreateCategoryAttribute(ctx, child.CategoryID,
+		client.CategoryAttributePayload{Key: "diameter", Label: "Диаметр", Type: models.AttributeTypeInteger})
+	expectStatus(t, "CreateCategoryAttribute with taken key", err, http.StatusConflict)
+	must[*client.ActionResult](t, "UpdateCategoryAttribute")(admin.Client.UpdateCategoryAttribute(ctx, child.CategoryID, attribute.AttributeID,
+		client.CategoryAttributePayload{Key: "diameter", Label: "Диаметр, мм", Type: models.AttributeTypeInteger, Required: true}))
+	attributes := must[[]client.CategoryAttribute](t, "GetCategoryAttributes")(srv.client().GetCategoryAttributes(ctx, child.CategoryID))
+	if len(attributes) != 1 || attributes[0].Label != "Диаметр, мм" || !attributes[0].Required {
+		t.Errorf("GetCategoryAttributes = %+v", attributes)
+	}
+
+	// Значения полей проверяются по схеме категории и доступны в фильтрах
+	_, err = author.Client.CreateOffer(ctx, client.CreateOfferPayload{Title: "No diameter", OfferType: "service_offer", CategoryID: &child.CategoryID})
+	expectStatus(t, "CreateOffer without a required attribute", err, http.StatusBadRequest)
+	offer := must[*client.OfferCreated](t, "CreateOffer")(author.Client.CreateOffer(ctx, client.CreateOfferPayload{
+		Title: "Replace pipes", OfferType: "service_offer", CategoryID: &child.CategoryID,
+		Attributes: map[string]interface{}{"diameter": 20},
+	}))
+	filter := &client.GetOffersParams{Category: fmt.Sprint(parent.CategoryID), AttrMin: map[string]string{"diameter": "15"}}
+	if offers := must[[]client.OfferResponse](t, "GetOffers")(srv.client().GetOffers(ctx, filter)); len(offers) != 1 || offers[0].ID != offer.OfferID {
+		t.Errorf("GetOffers by category and attribute = %+v", offers)
+	}
+
+	must[*client.ActionResult](t, "DeleteCategoryAttribute")(admin.Client.DeleteCategoryAttribute(ctx, child.CategoryID, attribute.AttributeID))
+	_, err = admin.Client.DeleteCategoryAttribute(ctx, child.CategoryID, attribute.AttributeID)
+	expectStatus(t, "DeleteCategoryAttribute twice", err, http.StatusNotFound)
+
+	_, err = admin.Client.DeleteCategory(ctx, parent.CategoryID, nil)
+	expectStatus(t, "DeleteCategory with a subcategory", err, http.StatusConflict)
+	must[*client.ActionResult](t, "DeleteCategory")(admin.Client.DeleteCategory(ctx, child.CategoryID, &client.DeleteCategoryParams{ReassignTo: parent.CategoryID}))
+	if offers := must[[]client.OfferResponse](t, "GetOffers")(srv.client().GetOffers(ctx, nil)); len(offers) != 1 || offers[0].CategoryID == nil || *offers[0].CategoryID != parent.CategoryID {
+		t.Errorf("offer was not reassigned to the parent category: %+v", offers)
+	}
+	_, err = author.Client.CreateOffer(ctx, client.CreateOfferPayload{Title: "Deleted category", OfferType: "service_offer", CategoryID: &child.CategoryID})
+	expectStatus(t, "CreateOffer in a deleted category", err, http.StatusBadRequest)
+	must[*client.ActionResult](t, "RestoreCategory")(admin.Client.RestoreCategory(ctx, child.CategoryID))
+}
+
+func TestAdminWebhooksAndTasks(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	admin := srv.admin(t)
+
+	webhook := must[*client.WebhookCreated](t, "CreateWebhook")(admin.Client.CreateWebhook(ctx, client.WebhookPayload{
+		URL: "https://hooks.example.com/masterdom", Events: []string{models.WebhookEventOfferCreated},
+	}))
+	if webhook.Secret == "" {
+		t.Error("CreateWebhook did not return the secret")
+	}
+	_, err := admin.Client.CreateWebhook(ctx, client.WebhookPayload{URL: "https://hooks.example.com", Events: []string{"unknown"}})
+	expectStatus(t, "CreateWebhook with unknown event", err, http.StatusBadRequest)
+
+	if webhooks := must[[]client.Webhook](t, "GetWebhooks")(admin.Client.GetWebhooks(ctx)); len(webhooks) != 1 || webhooks[0].Secret != "" {
+		t.Errorf("GetWebhooks = %+v", webhooks)
+	}
+	inactive := false
+	must[*client.ActionResult](t, "UpdateWebhook")(admin.Client.UpdateWebhook(ctx, webhook.WebhookID, client.WebhookPayload{
+		URL: "https://hooks.example.com/v2", Events: []string{models.WebhookEventOfferCreated, models.WebhookEventUserRegistered}, IsActive: &inactive,
+	}))
+	rotated := must[*client.SecretRotated](t, "RotateWebhookSecret")(admin.Client.RotateWebhookSecret(ctx, webhook.WebhookID))
+	if rotated.Secret == "" || rotated.Secret == webhook.Secret {
+		t.Errorf("RotateWebhookSecret = %+v, want a new secret", rotated)
+	}
+	got := must[*client.Webhook](t, "GetWebhook")(admin.Client.GetWebhook(ctx, webhook.WebhookID))
+	if got.URL != "https://hooks.example.com/v2" || got.IsActive || got.Secret != "" || len(got.Events) != 2 {
+		t.Errorf("GetWebhook = %+v", got)
+	}
+
+	queued := must[*client.WebhookTestQueued](t, "TestWebhook")(admin.Client.TestWebhook(ctx, webhook.WebhookID))
+	tasks := must[[]client.Task](t, "GetTasks")(admin.Client.GetTasks(ctx, &client.GetTasksParams{Kind: models.TaskKindWebhookDelivery}))
+	if len(tasks) != 1 || tasks[0].MaxAttempts != 1 {
+		t.Fatalf("GetTasks = %+v", tasks)
+	}
+
+	// Доставка выполняется воркером: имитируем неудачную попытку через хранилище
+	task := must[*models.Task](t, "ClaimTask")(srv.store.ClaimTask(ctx, []string{models.TaskKindWebhookDelivery}, "test-worker", time.Minute))
+	if task == nil || task.ID != tasks[0].ID {
+		t.Fatalf("ClaimTask = %+v", task)
+	}
+	if err := srv.store.FailTask(ctx, task.ID, "connection refused", nil); err != nil {
+		t.Fatalf("FailTask: %v", err)
+	}
+	statusCode := http.StatusBadGateway
+	err = srv.store.RecordWebhookDelivery(ctx, models.WebhookDelivery{
+		WebhookID: int(webhook.WebhookID), EventID: queued.EventID, EventType: models.WebhookEventTest, Attempt: 1, StatusCode: &statusCode,
+	})
+	if err != nil {
+		t.Fatalf("RecordWebhookDelivery: %v", err)
+	}
+	deliveries := must[[]client.WebhookDelivery](t, "GetWebhookDeliveries")(
+		admin.Client.GetWebhookDeliveries(ctx, webhook.WebhookID, &client.GetWebhookDeliveriesParams{Limit: 10}))
+	if len(deliveries) != 1 || deliveries[0].EventID != queued.EventID || deliveries[0].Success {
+		t.Errorf("GetWebhookDeliveries = %+v", deliveries)
+	}
+
+	dead := must[*client.Task](t, "GetTask")(admin.Client.GetTask(ctx, tasks[0].ID))
+	if dead.Status != models.TaskStatusDead || dead.LastError == nil {
+		t.Errorf("GetTask = %+v", dead)
+	}
+	must[*client.ActionResult](t, "RetryTask")(admin.Client.RetryTask(ctx, tasks[0].ID))
+	_, err = admin.Client.RetryTask(ctx, tasks[0].ID)
+	expectStatus(t, "RetryTask of a pending task", err, http.StatusNotFound)
+	_, err = admin.Client.GetTask(ctx, 999)
+	expectStatus(t, "GetTask of unknown task", err, http.StatusNotFound)
+
+	must[*client.ActionResult](t, "DeleteWebhook")(admin.Client.DeleteWebhook(ctx, webhook.WebhookID))
+	_, err = admin.Client.GetWebhook(ctx, webhook.WebhookID)
+	expectStatus(t, "GetWebhook after delete", err, http.StatusNotFound)
+}
+
+// fakeProvider - провайдер OIDC, который выдает подписанный ID-токен для
+// любого кода авторизации. Утверждения токена задаются по коду.
+type fakeProvider struct {
+	*httptest.Server
+	keys *jwtkeys.KeySet
+
+	mu     sync.Mutex
+	claims map[string]jwt.MapClaims
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	keys, err := jwtkeys.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate provider keys: %v", err)
+	}
+	p := &fakeProvider{keys: keys, claims: make(map[string]jwt.MapClaims)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, p.keys.JWKS())
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		claims, ok := p.claims[r.FormValue("code")]
+		p.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token, err := p.keys.Sign(claims)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, map[string]string{"id_token": token})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// issueCode регистрирует код авторизации для входа с утверждениями claims
+func (p *fakeProvider) issueCode(code, nonce string, claims jwt.MapClaims) {
+	claims["iss"] = p.URL
+	claims["aud"] = "test-client"
+	claims["exp"] = time.Now().Add(time.Minute).Unix()
+	claims["nonce"] = nonce
+	p.mu.Lock()
+	p.claims[code] = claims
+	p.mu.Unlock()
+}
+
+func TestOIDCLogin(t *testing.T) {
+	srv := newTestServer(t)
+	provider := newFakeProvider(t)
+	srv.handler.OIDC = oidc.Providers{"test": oidc.NewProvider(oidc.Config{
+		Name:        "test",
+		DisplayName: "Test",
+		Issuer:      provider.URL,
+		ClientID:    "test-client",
+		RedirectURL: srv.URL + "/api/auth/oidc/test/callback",
+	})}
+	ctx := context.Background()
+
+	providers := must[[]client.OIDCProvider](t, "GetOIDCProviders")(srv.client().GetOIDCProviders(ctx))
+	if len(providers) != 1 || providers[0].Name != "test" || providers[0].DisplayName != "Test" {
+		t.Errorf("GetOIDCProviders = %+v", providers)
+	}
+
+	// Маршруты входа рассчитаны на браузер и отвечают перенаправлением
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	redirect := func(path string) *url.URL {
+		t.Helper()
+		resp, err := noRedirect.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusFound {
+			t.Fatalf("GET %s: status = %d, want %d", path, resp.StatusCode, http.StatusFound)
+		}
+		location, err := resp.Location()
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		return location
+	}
+	// fragment возвращает параметры, переданные веб-приложению после #
+	fragment := func(location *url.URL) url.Values {
+		t.Helper()
+		if !strings.HasPrefix(location.String(), "https://app.example.com/auth/callback#") {
+			t.Fatalf("callback redirected to %s", location)
+		}
+		values, err := url.ParseQuery(location.Fragment)
+		if err != nil {
+			t.Fatalf("failed to parse fragment: %v", err)
+		}
+		return values
+	}
+
+	resp, err := noRedirect.Get(srv.URL + "/api/auth/oidc/unknown/login")
+	if err != nil {
+		t.Fatalf("GET login of unknown provider: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("login of unknown provider: status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	login := redirect("/api/auth/oidc/test/login")
+	if login.Host != strings.TrimPrefix(provider.URL, "http://") || login.Path != "/authorize" {
+		t.Fatalf("login redirected to %s", login)
+	}
+	state, nonce := login.Query().Get("state"), login.Query().Get("nonce")
+	provider.issueCode("code-1", nonce, jwt.MapClaims{
+		"sub": "subject-1", "email": "olga@example.com", "email_verified": true, "given_name": "Olga",
+	})
+
+	values := fragment(redirect("/api/auth/oidc/test/callback?" + url.Values{"state": {state}, "code": {"code-1"}}.Encode()))
+	if values.Get("token") == "" || values.Get("isAdmin") != "false" {
+		t.Fatalf("callback fragment = %v", values)
+	}
+	profile := must[*client.UserDetail](t, "GetMyProfile")(srv.client(client.WithToken(values.Get("token"))).GetMyProfile(ctx))
+	if profile.Email != "olga@example.com" || profile.FirstName == nil || *profile.FirstName != "Olga" {
+		t.Errorf("profile of the OIDC user = %+v", profile)
+	}
+
+	// Состояние входа одноразовое
+	values = fragment(redirect("/api/auth/oidc/test/callback?" + url.Values{"state": {state}, "code": {"code-1"}}.Encode()))
+	if values.Get("error") == "" || values.Get("token") != "" {
+		t.Errorf("replayed callback fragment = %v, want an error", values)
+	}
+}
@@ -0,0 +1,136 @@
+// Package images проверяет загружаемые изображения и строит их миниатюры.
+package images
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // декодер PNG для image.Decode
+
+	"masterdom/api/models"
+	"masterdom/api/queue"
+	"masterdom/api/store"
+)
+
+const (
+	// MaxBytes - наибольший размер загружаемого изображения
+	MaxBytes = 5 << 20
+	// maxPixels ограничивает размер изображения после распаковки
+	maxPixels = 40_000_000
+	// ThumbnailSize - наибольшая сторона миниатюры в пикселях
+	ThumbnailSize    = 256
+	thumbnailQuality = 85
+)
+
+// ErrUnsupported - данные не являются изображением JPEG или PNG допустимого размера
+var ErrUnsupported = errors.New("unsupported image")
+
+// Detect проверяет заголовок изображения и возвращает его тип MIME.
+func Detect(data []byte) (string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return "", fmt.Errorf("%w: %dx%d", ErrUnsupported, config.Width, config.Height)
+	}
+	switch format {
+	case "jpeg":
+		return "image/jpeg", nil
+	case "png":
+		return "image/png", nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupported, format)
+}
+
+// Thumbnail уменьшает изображение так, чтобы большая сторона не превышала
+// ThumbnailSize, и кодирует его в JPEG. Прозрачные области заливаются белым.
+// Изображения меньше миниатюры не увеличиваются.
+func Thumbnail(data []byte) ([]byte, error) {
+	if _, err := Detect(data); err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > ThumbnailSize || h > ThumbnailSize {
+		if w >= h {
+			w, h = ThumbnailSize, max(h*ThumbnailSize/w, 1)
+		} else {
+			w, h = max(w*ThumbnailSize/h, 1), ThumbnailSize
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scale(src, w, h), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scale уменьшает src до w x h усреднением пикселей, попадающих в каждый
+// пиксель результата, на белом фоне.
+func scale(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := b.Min.Y + y*b.Dy()/h
+		y1 := max(b.Min.Y+(y+1)*b.Dy()/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := b.Min.X + x*b.Dx()/w
+			x1 := max(b.Min.X+(x+1)*b.Dx()/w, x0+1)
+
+			var r, g, bl, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					// Цвета в image.Color уже умножены на альфу, поэтому
+					// белый фон добавляется в долю, не закрытую пикселем
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					bg := uint64(0xffff - ca)
+					r += uint64(cr) + bg
+					g += uint64(cg) + bg
+					bl += uint64(cb) + bg
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r / n) >> 8), G: uint8((g / n) >> 8), B: uint8((bl / n) >> 8), A: 0xff,
+			})
+		}
+	}
+	return dst
+}
+
+// ThumbnailHandler строит миниатюру фотографии профиля. Используется как
+// обработчик фоновых задач вида models.TaskKindAvatarThumbnail.
+func ThumbnailHandler(s store.Store) queue.Handler {
+	return func(ctx context.Context, task models.Task) error {
+		var payload models.UserTask
+		if err := json.Unmarshal(task.Payload, &payload); err != nil {
+			return queue.Permanent(fmt.Errorf("invalid thumbnail task payload: %w", err))
+		}
+
+		avatar, err := s.GetUserAvatar(ctx, payload.UserID)
+		if errors.Is(err, store.ErrAvatarNotFound) {
+			return queue.Permanent(fmt.Errorf("avatar of user %s no longer exists", payload.UserID))
+		}
+		if err != nil {
+			return err
+		}
+
+		thumbnail, err := Thumbnail(avatar.Image)
+		if err != nil {
+			return queue.Permanent(err)
+		}
+		return s.SetAvatarThumbnail(ctx, payload.UserID, avatar.UpdatedAt, thumbnail)
+	}
+}
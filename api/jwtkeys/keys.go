@@ -0,0 +1,240 @@
+// Package jwtkeys хранит ключи подписи токенов сессии. Токены подписываются
+// активным ключом (RS256 или EdDSA) с заголовком kid, а проверяются любым из
+// загруженных ключей, поэтому ключи можно менять без выхода пользователей.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits - минимальный допустимый размер ключа RSA
+const minRSABits = 2048
+
+// Key - ключ подписи или проверки токенов
+type Key struct {
+	ID        string
+	Algorithm string
+	public    crypto.PublicKey
+	// private задан только у ключей, которыми можно подписывать
+	private crypto.Signer
+}
+
+// KeySet - набор ключей: активный ключ подписи и все ключи проверки
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	// legacySecret позволяет принимать ранее выданные токены HS256 на время перехода
+	legacySecret []byte
+	// legacyCutoff - момент перехода на ключи, после него токены HS256 не выдавались
+	legacyCutoff time.Time
+}
+
+// LegacyTokenTTL - срок действия токенов HS256, которые выдавались до перехода на ключи
+const LegacyTokenTTL = 24 * time.Hour
+
+// Load читает ключи из каталога dir: каждый файл *.pem содержит закрытый
+// (PKCS#8 или PKCS#1) или открытый (PKIX) ключ RSA или Ed25519, имя файла без
+// расширения становится kid. Подписывает ключ signingKeyID; если он не задан,
+// в каталоге должен быть ровно один закрытый ключ.
+func Load(dir, signingKeyID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	ks := &KeySet{keys: make(map[string]*Key)}
+	var privateKeys []*Key
+	for _, path := range paths {
+		key, err := loadKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		ks.keys[key.ID] = key
+		if key.private != nil {
+			privateKeys = append(privateKeys, key)
+		}
+	}
+
+	switch {
+	case signingKeyID != "":
+		key, ok := ks.keys[signingKeyID]
+		if !ok || key.private == nil {
+			return nil, fmt.Errorf("private key %q not found in %s", signingKeyID, dir)
+		}
+		ks.signing = key
+	case len(privateKeys) == 1:
+		ks.signing = privateKeys[0]
+	case len(privateKeys) == 0:
+		return nil, fmt.Errorf("no private keys found in %s", dir)
+	default:
+		return nil, fmt.Errorf("%d private keys found in %s, set the signing key ID", len(privateKeys), dir)
+	}
+	return ks, nil
+}
+
+// Generate создает набор из одного временного ключа Ed25519. Подходит только
+// для разработки: после перезапуска выданные токены становятся недействительными.
+func Generate() (*KeySet, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	key := &Key{ID: "dev-" + base64.RawURLEncoding.EncodeToString(public)[:8], Algorithm: "EdDSA", public: public, private: private}
+	return &KeySet{signing: key, keys: map[string]*Key{key.ID: key}}, nil
+}
+
+// AcceptLegacySecret разрешает проверять токены HS256 без kid, подписанные
+// прежним общим секретом до момента cutoff. Новые токены этим секретом не
+// подписываются. В старых токенах нет iat, поэтому время выдачи оценивается по
+// exp: токен, действующий дольше cutoff + LegacyTokenTTL, выдан после перехода.
+func (ks *KeySet) AcceptLegacySecret(secret string, cutoff time.Time) {
+	ks.legacySecret = []byte(secret)
+	ks.legacyCutoff = cutoff
+}
+
+// checkLegacyToken отклоняет токены HS256, выданные после перехода на ключи.
+func (ks *KeySet) checkLegacyToken(claims jwt.Claims) error {
+	if issuedAt, err := claims.GetIssuedAt(); err != nil || issuedAt != nil && issuedAt.After(ks.legacyCutoff) {
+		return errors.New("legacy token issued after the switch to signing keys")
+	}
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil || expiresAt.After(ks.legacyCutoff.Add(LegacyTokenTTL)) {
+		return errors.New("legacy token issued after the switch to signing keys")
+	}
+	return nil
+}
+
+// SigningKeyID возвращает kid активного ключа подписи.
+func (ks *KeySet) SigningKeyID() string {
+	return ks.signing.ID
+}
+
+// Sign подписывает утверждения активным ключом.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(ks.signing.Algorithm), claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.private)
+}
+
+// Parse проверяет подпись и срок действия токена и заполняет claims. Ключ
+// выбирается по kid, а алгоритм токена должен совпадать с алгоритмом ключа.
+func (ks *KeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" && ks.legacySecret != nil && token.Method == jwt.SigningMethodHS256 {
+			if err := ks.checkLegacyToken(token.Claims); err != nil {
+				return nil, err
+			}
+			return ks.legacySecret, nil
+		}
+		key, ok := ks.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return key.public, nil
+	}, jwt.WithValidMethods([]string{"RS256", "EdDSA", "HS256"}), jwt.WithExpirationRequired())
+}
+
+// JWK - открытый ключ в формате JWK (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet - содержимое /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS возвращает открытые ключи проверки, отсортированные по kid.
+// Общий секрет HS256 не публикуется.
+func (ks *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, key := range ks.keys {
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Algorithm}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+func loadKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key := &Key{ID: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))}
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.private = signer
+		parsed = signer.Public()
+	}
+	switch public := parsed.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSABits)
+		}
+		key.Algorithm = "RS256"
+	case ed25519.PublicKey:
+		key.Algorithm = "EdDSA"
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", public)
+	}
+	key.public = parsed
+	return key, nil
+}
@@ -0,0 +1,82 @@
+// Package logging настраивает структурированный журнал на log/slog. Атрибуты,
+// добавленные в контекст через With (ID запроса, ID пользователя), и ID
+// трассировки попадают в каждую запись, сделанную с этим контекстом.
+package logging
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"masterdom/api/config"
+)
+
+// Setup делает журнал из настроек журналом по умолчанию. Записи пакета log
+// тоже проходят через него с уровнем INFO.
+func Setup(cfg config.LogConfig) *slog.Logger {
+	logger := New(cfg, os.Stderr)
+	slog.SetDefault(logger)
+	log.SetFlags(0)
+	return logger
+}
+
+// New создает журнал, который пишет в w в формате из настроек.
+func New(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+	options := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if cfg.Format == "text" {
+		handler = slog.NewTextHandler(w, options)
+	} else {
+		handler = slog.NewJSONHandler(w, options)
+	}
+	return slog.New(contextHandler{handler})
+}
+
+// Err - атрибут с ошибкой
+func Err(err error) slog.Attr {
+	return slog.Any("error", err)
+}
+
+type attrsKey struct{}
+
+// With возвращает контекст, записи с которым дополнительно содержат attrs.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	combined := make([]slog.Attr, 0, len(existing)+len(attrs))
+	combined = append(combined, existing...)
+	combined = append(combined, attrs...)
+	return context.WithValue(ctx, attrsKey{}, combined)
+}
+
+// contextHandler добавляет к записи атрибуты из контекста и идентификаторы
+// текущей трассировки, чтобы по записи журнала можно было найти трассировку
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
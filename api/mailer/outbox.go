@@ -0,0 +1,83 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"masterdom/api/logging"
+	"masterdom/api/models"
+	"masterdom/api/queue"
+	"masterdom/api/scheduler"
+	"masterdom/api/store"
+)
+
+// Deliverer отправляет письма из email_outbox. Используется как обработчик
+// фоновых задач вида models.TaskKindEmailSend; повторы с задержкой выполняет
+// очередь, а ошибка последней попытки сохраняется и в письме.
+type Deliverer struct {
+	store    store.Store
+	sender   Sender
+	renderer *Renderer
+}
+
+func NewDeliverer(s store.Store, sender Sender, renderer *Renderer) *Deliverer {
+	return &Deliverer{store: s, sender: sender, renderer: renderer}
+}
+
+func (d *Deliverer) Handle(ctx context.Context, task models.Task) error {
+	var payload models.EmailTask
+	if err := json.Unmarshal(task.Payload, &payload); err != nil {
+		return queue.Permanent(fmt.Errorf("invalid email task payload: %w", err))
+	}
+
+	email, err := d.store.GetOutboxEmail(ctx, payload.EmailID)
+	if errors.Is(err, store.ErrEmailNotFound) {
+		// Письмо уже отправлено предыдущей попыткой или получатель удален
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	msg, err := d.renderer.Render(email.Template, email.Locale, email.Data)
+	if err != nil {
+		return d.fail(ctx, email, queue.Permanent(err))
+	}
+	msg.To = email.To
+	if err := d.sender.Send(ctx, msg); err != nil {
+		return d.fail(ctx, email, err)
+	}
+
+	if err := d.store.MarkEmailSent(ctx, email.ID); err != nil {
+		// Повтор задачи отправил бы письмо второй раз
+		slog.ErrorContext(ctx, "Email was sent but not marked as sent", slog.Int64("email_id", email.ID), logging.Err(err))
+	}
+	return nil
+}
+
+// fail сохраняет ошибку отправки в письме и возвращает ее очереди.
+func (d *Deliverer) fail(ctx context.Context, email *models.OutboxEmail, sendErr error) error {
+	if err := d.store.MarkEmailFailed(ctx, email.ID, sendErr.Error()); err != nil {
+		return errors.Join(sendErr, err)
+	}
+	return sendErr
+}
+
+// EnqueueMessageDigestsTask ставит в очередь сводки сообщений, которые
+// остаются непрочитанными дольше delay.
+func EnqueueMessageDigestsTask(s store.Store, delay time.Duration) scheduler.Task {
+	return func(ctx context.Context) error {
+		queued, err := s.EnqueueMessageDigests(ctx, time.Now().Add(-delay))
+		if err != nil {
+			return fmt.Errorf("message digests: %w", err)
+		}
+		if queued > 0 {
+			slog.InfoContext(ctx, "Queued message digest emails", slog.Int64("count", queued))
+		}
+		return nil
+	}
+}
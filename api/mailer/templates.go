@@ -0,0 +1,73 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"masterdom/api/utils"
+)
+
+// Шаблоны лежат в templates/<язык>/<имя>.txt (блоки subject и text)
+// и templates/<язык>/<имя>.html.
+//
+//go:embed templates
+var templateFS embed.FS
+
+// Renderer формирует письма из встроенных шаблонов.
+type Renderer struct {
+	// BaseURL - адрес веб-приложения для ссылок в письмах
+	BaseURL string
+}
+
+// templateData - данные, доступные в шаблонах
+type templateData struct {
+	BaseURL string
+	Data    map[string]interface{}
+}
+
+// Render формирует письмо по шаблону на языке locale. Если перевода нет,
+// используется язык по умолчанию.
+func (r *Renderer) Render(name, locale string, data map[string]interface{}) (Message, error) {
+	if !utils.IsSupportedLocale(locale) {
+		locale = utils.DefaultLocale
+	}
+	if _, err := templateFS.Open(templatePath(locale, name, "txt")); err != nil {
+		locale = utils.DefaultLocale
+	}
+
+	td := templateData{BaseURL: strings.TrimRight(r.BaseURL, "/"), Data: data}
+	var msg Message
+
+	text, err := texttemplate.ParseFS(templateFS, templatePath(locale, name, "txt"))
+	if err != nil {
+		return msg, fmt.Errorf("failed to parse %s/%s text template: %w", locale, name, err)
+	}
+	var subject, body bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", td); err != nil {
+		return msg, fmt.Errorf("failed to render %s/%s subject: %w", locale, name, err)
+	}
+	if err := text.ExecuteTemplate(&body, "text", td); err != nil {
+		return msg, fmt.Errorf("failed to render %s/%s text: %w", locale, name, err)
+	}
+	msg.Subject = strings.TrimSpace(subject.String())
+	msg.Text = strings.TrimSpace(body.String()) + "\n"
+
+	html, err := htmltemplate.ParseFS(templateFS, "templates/layout.html", templatePath(locale, name, "html"))
+	if err != nil {
+		return msg, fmt.Errorf("failed to parse %s/%s html template: %w", locale, name, err)
+	}
+	var htmlBody bytes.Buffer
+	if err := html.ExecuteTemplate(&htmlBody, "layout", td); err != nil {
+		return msg, fmt.Errorf("failed to render %s/%s html: %w", locale, name, err)
+	}
+	msg.HTML = htmlBody.String()
+	return msg, nil
+}
+
+func templatePath(locale, name, ext string) string {
+	return fmt.Sprintf("templates/%s/%s.%s", locale, name, ext)
+}
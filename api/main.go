@@ -3,99 +3,262 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
+	"masterdom/api/config"
+	"masterdom/api/db"
 	"masterdom/api/handlers"
+	"masterdom/api/images"
+	"masterdom/api/jwtkeys"
+	"masterdom/api/logging"
+	"masterdom/api/mailer"
+	"masterdom/api/maintenance"
+	"masterdom/api/metrics"
 	"masterdom/api/middleware"
+	"masterdom/api/models"
+	"masterdom/api/oidc"
+	"masterdom/api/queue"
+	"masterdom/api/scheduler"
 	"masterdom/api/store"
+	"masterdom/api/tracing"
+	"masterdom/api/webhooks"
 )
 
+const usage = `Usage: %[1]s [command]
+
+Commands:
+  serve                  start the API server (default)
+  migrate up             apply all pending migrations
+  migrate down [N]       roll back the last N migrations (default 1)
+  migrate status         print the schema version and pending migrations
+  seed --dev             load development test data
+`
+
 func main() {
-	dbUrl := "postgres://user:password@db:5432/masterdom?sslmode=disable"
+	// Настройки читаются из окружения и необязательного YAML-файла CONFIG_FILE
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		fatal("Unable to load configuration", err)
+	}
+	logging.Setup(cfg.Log)
 
-	dbp, err := pgxpool.New(context.Background(), dbUrl)
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+	switch command {
+	case "serve":
+		serve(cfg)
+	case "migrate":
+		err = runMigrate(cfg, args)
+	case "seed":
+		err = runSeed(cfg, args)
+	case "help", "-h", "--help":
+		fmt.Printf(usage, os.Args[0])
+	default:
+		fmt.Fprintf(os.Stderr, usage, os.Args[0])
+		os.Exit(2)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unable to create connection pool: %v\n", err)
-		os.Exit(1)
+		fatal("Command failed", err, slog.String("command", command))
+	}
+}
+
+// fatal записывает ошибку в журнал и завершает процесс.
+func fatal(msg string, err error, attrs ...any) {
+	slog.Error(msg, append([]any{logging.Err(err)}, attrs...)...)
+	os.Exit(1)
+}
+
+// serve запускает HTTP-сервер и фоновые задачи до получения SIGINT/SIGTERM.
+func serve(cfg *config.Config) {
+	if cfg.IsProd() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// Без ключей подписи токенов продакшен не запускается, см. loadJWTKeys
+	jwtKeys, err := loadJWTKeys(cfg)
+	if err != nil {
+		fatal("Unable to load JWT keys", err)
+	}
+
+	// Остановка по SIGINT/SIGTERM: сервер перестает принимать запросы,
+	// дожидается текущих и останавливает фоновые задачи
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, cfg.Env)
+	if err != nil {
+		fatal("Unable to set up tracing", err)
+	}
+
+	dbp, err := db.Connect(ctx, cfg.Database, store.QueryLogger{}, store.QueryTracer{})
+	if err != nil {
+		fatal("Unable to connect to the database", err)
 	}
 	defer dbp.Close()
+	slog.Info("Connected to the database")
 
-	if err := dbp.Ping(context.Background()); err != nil {
-		log.Fatalf("Unable to ping database: %v\n", err)
+	if err := db.CheckSchema(ctx, dbp); err != nil {
+		fatal("Database schema check failed", err)
 	}
-	log.Println("Successfully connected to the database")
+
+	day := 24 * time.Hour
+	oidcProviders := oidc.NewProviders(cfg.OIDC, cfg.APIBaseURL)
 
 	appStore := store.NewPostgresStore(dbp)
-	appHandlers := handlers.NewHandler(appStore)
-
-	r := gin.Default()
-	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{"http://localhost:3000"}
-	config.AllowMethods = []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
-	r.Use(cors.New(config))
-
-	api := r.Group("/api")
-	api.Use(middleware.MaybeAuthMiddleware())
-	{
-		api.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{"status": "ok"})
-		})
-
-		auth := api.Group("/auth")
-		{
-			auth.POST("/register", appHandlers.Register)
-			auth.POST("/login", appHandlers.Login)
-		}
+	appHandlers := handlers.NewHandler(appStore, cfg, oidcProviders, jwtKeys)
+
+	// Периодические задачи выполняются внутри процесса API
+	jobs := scheduler.New()
+	// Мягко удаленные записи хранятся retention.softDeleteDays дней, затем удаляются окончательно
+	retention := time.Duration(cfg.Retention.SoftDeleteDays) * day
+	jobs.Register("retention-purge", time.Hour, maintenance.PurgeDeletedTask(appStore, retention))
+	jobs.Register("expire-offers", 5*time.Minute, maintenance.ExpireOffersTask(appStore))
+	// Авторам напоминают о продлении за offers.expiryReminderDays дней до истечения срока
+	remindBefore := time.Duration(cfg.Offers.ExpiryReminderDays) * day
+	jobs.Register("offer-expiry-reminders", 15*time.Minute, maintenance.RemindExpiringOffersTask(appStore, remindBefore))
 
-		api.GET("/offers", appHandlers.GetOffers)
-		api.GET("/categories", appHandlers.GetAllCategories)
-
-		protected := api.Group("/")
-		protected.Use(middleware.AuthMiddleware())
-		{
-			protected.GET("/profile", appHandlers.GetMyProfile)
-			protected.PATCH("/profile", appHandlers.UpdateMyProfile)
-			protected.POST("/offers", appHandlers.CreateOffer)
-			protected.POST("/offers/:id/respond", appHandlers.RespondToOffer)
-			protected.GET("/offers/:id/applications", appHandlers.GetOfferApplications)
-
-			// Chat routes
-			chatGroup := protected.Group("/chats")
-			{
-				chatGroup.GET("", appHandlers.GetConversations)
-				chatGroup.POST("/initiate", appHandlers.InitiateChat)
-				chatGroup.GET("/:id", appHandlers.GetChatDetails)
-				chatGroup.GET("/:id/messages", appHandlers.GetMessages)
-				chatGroup.POST("/:id/messages", appHandlers.PostMessage)
-			}
-
-			// Admin routes
-			admin := protected.Group("/admin")
-			admin.Use(middleware.AdminAuthMiddleware())
-			{
-				admin.GET("/users", appHandlers.GetUsers)
-				admin.GET("/users/:id", appHandlers.GetUserByID)
-				admin.PATCH("/users/:id", appHandlers.UpdateUser)
-				admin.DELETE("/users/:id", appHandlers.DeleteUser)
-
-				admin.GET("/offers", appHandlers.GetAdminAllOffers)
-				admin.PATCH("/offers/:id", appHandlers.UpdateOfferStatus)
-				admin.DELETE("/offers/:id", appHandlers.DeleteOffer)
-
-				admin.POST("/categories", appHandlers.CreateCategory)
-				admin.PATCH("/categories/:id", appHandlers.UpdateCategory)
-				admin.DELETE("/categories/:id", appHandlers.DeleteCategory)
-
-				admin.GET("/stats", appHandlers.GetAdminStats)
-			}
+	// Письма отправляются задачами очереди; без SMTP-сервера они только пишутся в лог
+	var sender mailer.Sender = mailer.LogSender{}
+	if cfg.Email.SMTPHost != "" {
+		sender = &mailer.SMTPSender{
+			Host:     cfg.Email.SMTPHost,
+			Port:     cfg.Email.SMTPPort,
+			Username: cfg.Email.SMTPUsername,
+			Password: cfg.Email.SMTPPassword,
+			From:     cfg.Email.From,
 		}
 	}
+	renderer := &mailer.Renderer{BaseURL: cfg.AppBaseURL}
+	// Непрочитанные сообщения собираются в сводку через email.digestDelayMinutes минут
+	digestDelay := time.Duration(cfg.Email.DigestDelayMinutes) * time.Minute
+	jobs.Register("message-digests", 5*time.Minute, mailer.EnqueueMessageDigestsTask(appStore, digestDelay))
+
+	// Выполненные фоновые задачи хранятся неделю
+	jobs.Register("purge-finished-tasks", time.Hour, maintenance.PurgeFinishedTasksTask(appStore, 7*day))
+
+	jobs.Start(context.Background())
+
+	// Фоновые задачи из таблицы tasks выполняются пулом из tasks.workers воркеров
+	tasks := queue.New(appStore, cfg.Tasks.Workers)
+	tasks.Register(models.TaskKindWebhookDelivery, webhooks.NewDeliverer(appStore).Handle)
+	tasks.Register(models.TaskKindEmailSend, mailer.NewDeliverer(appStore, sender, renderer).Handle)
+	tasks.Register(models.TaskKindRatingRecalculate, maintenance.RecalculateRatingHandler(appStore))
+	tasks.Register(models.TaskKindAvatarThumbnail, images.ThumbnailHandler(appStore))
+	tasks.Start(context.Background())
+
+	r := gin.New()
+	// Трассировка начинается первой, чтобы ID трассировки попал и в журнал запросов
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithFilter(tracedRequest)))
+	r.Use(middleware.RequestID(), middleware.AccessLog("/livez", "/readyz"), middleware.Recovery())
+
+	// Метрики Prometheus доступны только с токеном metrics.token
+	if cfg.Metrics.Token != "" {
+		registry := metrics.New(dbp)
+		r.Use(registry.Middleware())
+		r.GET("/metrics", registry.Handler(cfg.Metrics.Token))
+	} else {
+		slog.Info("Metrics are disabled, set metrics.token to enable /metrics")
+	}
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOrigins = cfg.HTTP.CORSOrigins
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{
+		"Origin", "Content-Type", "Authorization", middleware.APIKeyHeader, middleware.RequestIDHeader,
+		// Заголовки W3C Trace Context, если веб-приложение продолжает трассировку
+		"traceparent", "tracestate",
+	}
+	corsConfig.ExposeHeaders = []string{middleware.RequestIDHeader}
+	r.Use(cors.New(corsConfig))
+
+	registerRoutes(r, appHandlers, appStore, jwtKeys)
+
+	srv := &http.Server{
+		Addr:              cfg.HTTP.Addr,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Listening", slog.String("addr", cfg.HTTP.Addr))
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	failed := false
+	select {
+	case err := <-serverErr:
+		slog.Error("HTTP server failed", logging.Err(err))
+		failed = true
+	case <-ctx.Done():
+		slog.Info("Shutting down")
+	}
+	stop()
+
+	// Readiness сразу начинает отвечать 503, но сервер еще http.drainDelay принимает
+	// запросы, пока балансировщик не перестанет их присылать; затем текущие дорабатывают
+	appHandlers.SetDraining()
+	if !failed && cfg.HTTP.DrainDelay > 0 {
+		slog.Info("Draining", slog.Duration("delay", cfg.HTTP.DrainDelay))
+		time.Sleep(cfg.HTTP.DrainDelay)
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("HTTP server did not drain in time", logging.Err(err))
+	}
 
-	r.Run(":8080")
+	tasks.Stop()
+	jobs.Stop()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("Failed to flush traces", logging.Err(err))
+	}
+	slog.Info("Shutdown complete")
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// tracedRequest исключает из трассировки служебные запросы оркестратора и Prometheus.
+func tracedRequest(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/livez", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}
+
+// loadJWTKeys загружает ключи подписи токенов из auth.keysDir. Без каталога
+// вне продакшена создается временный ключ; в prod каталог обязателен (см. config.Validate).
+// Прежний секрет HS256, если задан, используется только для проверки ранее
+// выданных токенов и нужен лишь на время перехода.
+func loadJWTKeys(cfg *config.Config) (*jwtkeys.KeySet, error) {
+	var keys *jwtkeys.KeySet
+	var err error
+	if cfg.Auth.KeysDir != "" {
+		keys, err = jwtkeys.Load(cfg.Auth.KeysDir, cfg.Auth.SigningKeyID)
+	} else {
+		slog.Warn("JWT keys directory is not set, using a temporary signing key; tokens will not survive a restart")
+		keys, err = jwtkeys.Generate()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Auth.LegacySecret != "" {
+		keys.AcceptLegacySecret(cfg.Auth.LegacySecret, cfg.Auth.LegacySecretCutoff)
+		slog.Warn("Accepting legacy HS256 tokens issued before the cutoff",
+			slog.Time("cutoff", cfg.Auth.LegacySecretCutoff),
+			slog.Time("until", cfg.Auth.LegacySecretCutoff.Add(jwtkeys.LegacyTokenTTL)))
+	}
+	slog.Info("Signing tokens", slog.String("kid", keys.SigningKeyID()))
+	return keys, nil
 }
@@ -0,0 +1,50 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"masterdom/api/logging"
+	"masterdom/api/models"
+	"masterdom/api/scheduler"
+	"masterdom/api/store"
+)
+
+// ExpireOffersTask снимает с публикации объявления с истекшим сроком.
+func ExpireOffersTask(s store.Store) scheduler.Task {
+	return func(ctx context.Context) error {
+		expired, err := s.ExpireOffers(ctx)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			slog.InfoContext(ctx, "Expired offers", slog.Int64("count", expired))
+		}
+		return nil
+	}
+}
+
+// RemindExpiringOffersTask напоминает авторам об объявлениях, срок которых
+// истекает в ближайшие remindBefore.
+func RemindExpiringOffersTask(s store.Store, remindBefore time.Duration) scheduler.Task {
+	return func(ctx context.Context) error {
+		offers, err := s.MarkExpiringOffers(ctx, time.Now().Add(remindBefore))
+		if err != nil {
+			return fmt.Errorf("expiry reminders: %w", err)
+		}
+		for _, offer := range offers {
+			err := s.CreateNotification(ctx, offer.AuthorID, models.NotificationOfferExpiring, map[string]interface{}{
+				"offerId":    offer.ID,
+				"offerTitle": offer.Title,
+				"expiresAt":  offer.ExpiresAt,
+			})
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to notify about expiring offer",
+					slog.String("user_id", offer.AuthorID), slog.String("offer_id", offer.ID), logging.Err(err))
+			}
+		}
+		return nil
+	}
+}
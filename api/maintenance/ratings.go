@@ -0,0 +1,24 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"masterdom/api/models"
+	"masterdom/api/queue"
+	"masterdom/api/store"
+)
+
+// RecalculateRatingHandler пересчитывает средний рейтинг пользователя по
+// полученным отзывам. Используется как обработчик фоновых задач вида
+// models.TaskKindRatingRecalculate, которые ставятся при новом отзыве.
+func RecalculateRatingHandler(s store.Store) queue.Handler {
+	return func(ctx context.Context, task models.Task) error {
+		var payload models.UserTask
+		if err := json.Unmarshal(task.Payload, &payload); err != nil {
+			return queue.Permanent(fmt.Errorf("invalid rating task payload: %w", err))
+		}
+		return s.RecalculateUserRating(ctx, payload.UserID)
+	}
+}
@@ -0,0 +1,41 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"masterdom/api/scheduler"
+	"masterdom/api/store"
+)
+
+// PurgeDeletedTask окончательно удаляет записи, мягко удаленные раньше,
+// чем retention назад.
+func PurgeDeletedTask(s store.Store, retention time.Duration) scheduler.Task {
+	return func(ctx context.Context) error {
+		result, err := s.PurgeDeleted(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return fmt.Errorf("retention purge: %w", err)
+		}
+		if result.Users+result.Offers+result.Categories > 0 {
+			slog.InfoContext(ctx, "Retention purge completed",
+				slog.Int64("users", result.Users), slog.Int64("offers", result.Offers), slog.Int64("categories", result.Categories))
+		}
+		return nil
+	}
+}
+
+// PurgeFinishedTasksTask удаляет успешно выполненные фоновые задачи старше retention.
+func PurgeFinishedTasksTask(s store.Store, retention time.Duration) scheduler.Task {
+	return func(ctx context.Context) error {
+		purged, err := s.PurgeFinishedTasks(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if purged > 0 {
+			slog.InfoContext(ctx, "Purged finished tasks", slog.Int64("count", purged))
+		}
+		return nil
+	}
+}
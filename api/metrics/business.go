@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Счетчики бизнес-событий. Их увеличивают обработчики там, где событие
+// произошло, поэтому удаление записей значения не уменьшает. Счетчики живут в
+// процессе и после перезапуска начинаются с нуля; число событий за период по
+// всем экземплярам API дает increase() в Prometheus.
+var (
+	UsersRegistered = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "users_registered_total",
+		Help:      "Registered users, including accounts created on the first OpenID Connect login.",
+	})
+	OffersCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "offers_created_total",
+		Help:      "Created offers by type.",
+	}, []string{"type"})
+	Applications = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "applications_total",
+		Help:      "Applications to offers.",
+	})
+	MessagesSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_sent_total",
+		Help:      "Chat messages sent.",
+	})
+	JobsCompleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_completed_total",
+		Help:      "Jobs moved to the completed status.",
+	})
+)
+
+func init() {
+	// Ряды по типам объявлений публикуются сразу, а не с первого объявления
+	// типа, чтобы increase() учитывал и его
+	for _, offerType := range []string{"request_for_service", "service_offer"} {
+		OffersCreated.WithLabelValues(offerType)
+	}
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func desc(subsystem, name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, name), help, labels, nil)
+}
+
+// poolCollector читает статистику пула pgxpool при каждом сборе метрик
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired        *prometheus.Desc
+	idle            *prometheus.Desc
+	total           *prometheus.Desc
+	max             *prometheus.Desc
+	acquires        *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	emptyAcquires   *prometheus.Desc
+	emptyWait       *prometheus.Desc
+	canceled        *prometheus.Desc
+}
+
+func newPoolCollector(pool *pgxpool.Pool) *poolCollector {
+	return &poolCollector{
+		pool:            pool,
+		acquired:        desc("db_pool", "acquired_connections", "Connections currently in use."),
+		idle:            desc("db_pool", "idle_connections", "Idle connections in the pool."),
+		total:           desc("db_pool", "total_connections", "Open connections, including those being established."),
+		max:             desc("db_pool", "max_connections", "Maximum size of the pool."),
+		acquires:        desc("db_pool", "acquires_total", "Successful connection acquisitions."),
+		acquireDuration: desc("db_pool", "acquire_duration_seconds_total", "Total time spent acquiring connections."),
+		emptyAcquires:   desc("db_pool", "empty_acquires_total", "Acquisitions that had to wait for a connection."),
+		emptyWait:       desc("db_pool", "empty_acquire_wait_seconds_total", "Total time spent waiting for a free connection."),
+		canceled:        desc("db_pool", "canceled_acquires_total", "Acquisitions canceled by the caller's context."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+	gauge(c.acquired, float64(stat.AcquiredConns()))
+	gauge(c.idle, float64(stat.IdleConns()))
+	gauge(c.total, float64(stat.TotalConns()))
+	gauge(c.max, float64(stat.MaxConns()))
+	counter(c.acquires, float64(stat.AcquireCount()))
+	counter(c.acquireDuration, stat.AcquireDuration().Seconds())
+	counter(c.emptyAcquires, float64(stat.EmptyAcquireCount()))
+	counter(c.emptyWait, stat.EmptyAcquireWaitTime().Seconds())
+	counter(c.canceled, float64(stat.CanceledAcquireCount()))
+}
@@ -0,0 +1,97 @@
+// Package metrics публикует метрики Prometheus: запросы HTTP по маршрутам,
+// состояние пула соединений с базой данных и счетчики бизнес-событий.
+package metrics
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"masterdom/api/apperr"
+	"masterdom/api/middleware"
+)
+
+const namespace = "masterdom"
+
+// errInvalidToken - ответ на запрос метрик без верного токена
+var errInvalidToken = apperr.Unauthorized("invalid_token", "Invalid metrics token")
+
+// unmatchedRoute - метка для запросов без маршрута, чтобы произвольные пути
+// не порождали новые временные ряды
+const unmatchedRoute = "unmatched"
+
+// Registry - набор метрик процесса API
+type Registry struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// New регистрирует метрики процесса, HTTP, пула соединений и бизнес-событий.
+func New(pool *pgxpool.Pool) *Registry {
+	r := &Registry{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method and route.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"method", "route"}),
+	}
+	r.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		r.requests,
+		r.duration,
+		newPoolCollector(pool),
+		UsersRegistered,
+		OffersCreated,
+		Applications,
+		MessagesSent,
+		JobsCompleted,
+	)
+	return r
+}
+
+// Middleware учитывает каждый запрос. Маршрут берется из шаблона Gin
+// (/api/offers/:id), а не из пути запроса.
+func (r *Registry) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+		r.requests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		r.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler отдает метрики только запросам с токеном из metrics.token.
+func (r *Registry) Handler(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+	handler := promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{Registry: r.registry})
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+			middleware.AbortWithError(c, errInvalidToken)
+			return
+		}
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+}
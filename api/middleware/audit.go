@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/audit"
+)
+
+// AuditActorMiddleware помещает в контекст запроса сведения об инициаторе,
+// чтобы хранилище записало их в журнал аудита. Используется после AuthMiddleware.
+func AuditActorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		actorID, _ := userID.(string)
+		ctx := audit.WithActor(c.Request.Context(), audit.Actor{
+			UserID:    actorID,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -1,77 +1,181 @@
 package middleware
 
 import (
-	"fmt"
+	"crypto/subtle"
+	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 
+	"masterdom/api/apperr"
+	"masterdom/api/jwtkeys"
+	"masterdom/api/logging"
 	"masterdom/api/models"
+	"masterdom/api/store"
 	"masterdom/api/utils"
 )
 
-// AuthMiddleware (остается без изменений)
-func AuthMiddleware() gin.HandlerFunc {
+// APIKeyHeader - заголовок, в котором интеграции передают ключ API
+const APIKeyHeader = "X-API-Key"
+
+// Ошибки аутентификации и проверки прав
+var (
+	errUserNotFound       = apperr.Unauthorized("user_not_found", "User not found")
+	errInvalidAuthHeader  = apperr.Unauthorized("invalid_auth_header", "Invalid authorization header format")
+	errInvalidToken       = apperr.Unauthorized("invalid_token", "Invalid or expired token")
+	errInvalidAPIKey      = apperr.Unauthorized("invalid_api_key", "Invalid API key")
+	errAPIKeyRevoked      = apperr.Unauthorized("api_key_revoked", "API key has been revoked")
+	errAPIKeyExpired      = apperr.Unauthorized("api_key_expired", "API key has expired")
+	errMissingCredentials = apperr.Unauthorized("missing_credentials", "Authorization header is missing")
+	errInsufficientScope  = apperr.Forbidden("insufficient_scope", "API key does not have the required scope")
+	errSessionRequired    = apperr.Forbidden("session_required", "This endpoint is not available with an API key")
+	errAdminRequired      = apperr.Forbidden("admin_required", "Admin access required")
+)
+
+// principal - аутентифицированный пользователь запроса
+type principal struct {
+	userID  string
+	isAdmin bool
+	// apiKeyID и scopes заданы, только если запрос выполнен с ключом API
+	apiKeyID string
+	scopes   []string
+}
+
+// authenticate определяет пользователя по токену из Authorization или по
+// ключу из X-API-Key. Возвращает nil и nil, если учетные данные не переданы.
+func authenticate(c *gin.Context, s store.Store, keys *jwtkeys.KeySet) (*principal, error) {
+	var p *principal
+	var authErr error
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		p, authErr = authenticateAPIKey(c, s, key)
+	} else if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+		p, authErr = authenticateToken(keys, authHeader)
+	} else {
+		return nil, nil
+	}
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	// Токен живет сутки, поэтому статус учетной записи проверяем при каждом запросе
+	status, err := s.GetAccountStatus(c.Request.Context(), p.userID)
+	if err != nil {
+		return nil, errUserNotFound.Wrap(err)
+	}
+	if status.IsBlocked(time.Now()) {
+		return nil, store.AccountBlocked(*status)
+	}
+	return p, nil
+}
+
+func authenticateToken(keys *jwtkeys.KeySet, authHeader string) (*principal, error) {
+	const bearerSchema = "Bearer "
+	if len(authHeader) < len(bearerSchema) || authHeader[:len(bearerSchema)] != bearerSchema {
+		return nil, errInvalidAuthHeader
+	}
+	tokenString := authHeader[len(bearerSchema):]
+	claims := &models.Claims{}
+	token, err := keys.Parse(tokenString, claims)
+	if err != nil || !token.Valid {
+		return nil, errInvalidToken
+	}
+	return &principal{userID: claims.UserID, isAdmin: claims.IsAdmin}, nil
+}
+
+func authenticateAPIKey(c *gin.Context, s store.Store, key string) (*principal, error) {
+	prefix, ok := utils.ParseAPIKeyPrefix(key)
+	if !ok {
+		return nil, errInvalidAPIKey
+	}
+	creds, err := s.GetAPIKeyCredentials(c.Request.Context(), prefix)
+	if err != nil {
+		return nil, errInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(creds.KeyHash), []byte(utils.HashAPIKey(key))) != 1 {
+		return nil, errInvalidAPIKey
+	}
+	if creds.RevokedAt != nil {
+		return nil, errAPIKeyRevoked
+	}
+	if creds.ExpiresAt != nil && !creds.ExpiresAt.After(time.Now()) {
+		return nil, errAPIKeyExpired
+	}
+
+	if err := s.TouchAPIKey(c.Request.Context(), creds.ID, c.ClientIP()); err != nil {
+		slog.WarnContext(c.Request.Context(), "Failed to record API key usage",
+			slog.String("api_key_id", creds.ID), logging.Err(err))
+	}
+	return &principal{
+		userID: creds.UserID,
+		// Права администратора ключ получает, только если выдан с правом admin
+		isAdmin:  creds.IsAdmin && slices.Contains(creds.Scopes, models.ScopeAdmin),
+		apiKeyID: creds.ID,
+		scopes:   creds.Scopes,
+	}, nil
+}
+
+func (p *principal) setContext(c *gin.Context) {
+	c.Set("userID", p.userID)
+	c.Set("isAdmin", p.isAdmin)
+	attrs := []slog.Attr{slog.String("user_id", p.userID)}
+	if p.apiKeyID != "" {
+		c.Set("apiKeyID", p.apiKeyID)
+		c.Set("apiKeyScopes", p.scopes)
+		attrs = append(attrs, slog.String("api_key_id", p.apiKeyID))
+	}
+	c.Request = c.Request.WithContext(logging.With(c.Request.Context(), attrs...))
+}
+
+// AuthMiddleware требует валидный токен или ключ API и проверяет, что учетная
+// запись не приостановлена и не заблокирована.
+func AuthMiddleware(s store.Store, keys *jwtkeys.KeySet) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(401, gin.H{"error": "Authorization header is missing"})
+		p, authErr := authenticate(c, s, keys)
+		if authErr != nil {
+			AbortWithError(c, authErr)
 			return
 		}
-		const bearerSchema = "Bearer "
-		if len(authHeader) < len(bearerSchema) || authHeader[:len(bearerSchema)] != bearerSchema {
-			c.AbortWithStatusJSON(401, gin.H{"error": "Invalid authorization header format"})
+		if p == nil {
+			AbortWithError(c, errMissingCredentials)
 			return
 		}
-		tokenString := authHeader[len(bearerSchema):]
-		claims := &models.Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return utils.GetJWTKey(), nil
-		})
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(401, gin.H{"error": "Invalid or expired token"})
-			return
-		}
-		c.Set("userID", claims.UserID)
-		c.Set("isAdmin", claims.IsAdmin)
+		p.setContext(c)
 		c.Next()
 	}
 }
 
-// MaybeAuthMiddleware извлекает информацию о пользователе из токена, если он предоставлен,
-// но не требует его наличия.
-func MaybeAuthMiddleware() gin.HandlerFunc {
+// MaybeAuthMiddleware извлекает информацию о пользователе из токена или ключа API,
+// если они предоставлены, но не требует их наличия.
+func MaybeAuthMiddleware(s store.Store, keys *jwtkeys.KeySet) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.Next()
-			return
+		// Если учетные данные валидны и учетная запись не ограничена, устанавливаем информацию о пользователе
+		if p, authErr := authenticate(c, s, keys); authErr == nil && p != nil {
+			p.setContext(c)
 		}
+		c.Next()
+	}
+}
 
-		const bearerSchema = "Bearer "
-		if len(authHeader) < len(bearerSchema) || authHeader[:len(bearerSchema)] != bearerSchema {
-			c.Next()
+// RequireScope пропускает запросы с ключом API, только если у ключа есть
+// право scope. Запросы с токеном сессии не ограничиваются.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scopes, ok := c.Get("apiKeyScopes"); ok && !slices.Contains(scopes.([]string), scope) {
+			AbortWithError(c, errInsufficientScope.With("scope", scope))
 			return
 		}
+		c.Next()
+	}
+}
 
-		tokenString := authHeader[len(bearerSchema):]
-		claims := &models.Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return utils.GetJWTKey(), nil
-		})
-
-		// Если токен валиден, устанавливаем информацию о пользователе
-		if err == nil && token.Valid {
-			c.Set("userID", claims.UserID)
-			c.Set("isAdmin", claims.IsAdmin)
+// RequireSession запрещает доступ с ключом API, например к управлению самими ключами.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("apiKeyID"); ok {
+			AbortWithError(c, errSessionRequired)
+			return
 		}
-
 		c.Next()
 	}
 }
@@ -81,7 +185,7 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		isAdmin, exists := c.Get("isAdmin")
 		if !exists || !isAdmin.(bool) {
-			c.AbortWithStatusJSON(403, gin.H{"error": "Forbidden: Admin access required"})
+			AbortWithError(c, errAdminRequired)
 			return
 		}
 		c.Next()
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/apperr"
+	"masterdom/api/utils"
+)
+
+// AbortWithError прерывает обработку запроса ответом с ошибкой err. Ошибки
+// предметной области отдаются со своим кодом, параметрами и сообщением на
+// языке из Accept-Language, остальные - как внутренняя ошибка без
+// подробностей. Сама err прикрепляется к контексту и попадает в журнал.
+func AbortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	appErr := apperr.Public(err)
+	locale := utils.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	c.AbortWithStatusJSON(appErr.Status(), appErr.Response(locale))
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/apperr"
+	"masterdom/api/logging"
+)
+
+// RequestIDHeader - заголовок с идентификатором запроса для сквозного поиска по логам
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength ограничивает ID, пришедший от клиента или прокси
+const maxRequestIDLength = 128
+
+// RequestID берет ID запроса из X-Request-ID или создает новый, возвращает
+// его в ответе и добавляет в контекст, чтобы он попал во все записи журнала.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set("requestID", id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.With(c.Request.Context(), slog.String("request_id", id)))
+		c.Next()
+	}
+}
+
+// validRequestID допускает только печатные ASCII-символы, чтобы ID нельзя
+// было использовать для подделки строк журнала.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// AccessLog записывает в журнал каждый запрос после его выполнения. Запросы
+// проверок готовности пишутся с уровнем DEBUG, чтобы не засорять журнал.
+func AccessLog(quietPaths ...string) gin.HandlerFunc {
+	quiet := make(map[string]bool, len(quietPaths))
+	for _, path := range quietPaths {
+		quiet[path] = true
+	}
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case quiet[c.Request.URL.Path]:
+			level = slog.LevelDebug
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+		// Контекст берется после обработки: в нем уже есть ID пользователя
+		slog.LogAttrs(c.Request.Context(), level, "HTTP request", attrs...)
+	}
+}
+
+// Recovery отвечает 500 на панику в обработчике и записывает ее в журнал
+// вместе с ID запроса.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		slog.ErrorContext(c.Request.Context(), "Handler panicked",
+			slog.Any("panic", recovered), slog.String("stack", string(debug.Stack())))
+		AbortWithError(c, apperr.ErrInternal)
+	})
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,6 +14,8 @@ type RegisterPayload struct {
 	FirstName   string  `json:"firstName" binding:"required"`
 	LastName    *string `json:"lastName"`
 	PhoneNumber *string `json:"phoneNumber"`
+	// Locale - язык писем; по умолчанию определяется по Accept-Language
+	Locale string `json:"locale" binding:"omitempty,oneof=ru en"`
 }
 
 type LoginPayload struct {
@@ -24,6 +28,26 @@ type CreateOfferPayload struct {
 	Description string `json:"description"`
 	CategoryID  *int   `json:"categoryId"`
 	OfferType   string `json:"offerType" binding:"required,oneof=request_for_service service_offer"`
+	// Attributes - значения полей, заданных схемой категории
+	Attributes map[string]interface{} `json:"attributes"`
+	// ExpiresAt - желаемый срок публикации, не больше срока по умолчанию для типа
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// OfferTTL задает срок публикации объявления по умолчанию для каждого типа
+type OfferTTL map[string]time.Duration
+
+// For возвращает срок публикации для типа объявления.
+func (t OfferTTL) For(offerType string) time.Duration {
+	return t[offerType]
+}
+
+// ExpiringOffer - объявление, автору которого отправляется напоминание об истечении срока
+type ExpiringOffer struct {
+	ID        string    `json:"id"`
+	AuthorID  string    `json:"authorId"`
+	Title     string    `json:"title"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 type UpdateOfferPayload struct {
@@ -33,6 +57,42 @@ type UpdateOfferPayload struct {
 type CategoryPayload struct {
 	Name        string  `json:"name" binding:"required"`
 	Description *string `json:"description"`
+	ParentID    *int    `json:"parentId"`
+	Slug        *string `json:"slug"`
+	SortOrder   *int    `json:"sortOrder"`
+	Icon        *string `json:"icon"`
+	// Translations задает перевод для каждого поддерживаемого языка (ru, en)
+	Translations map[string]CategoryTranslation `json:"translations" binding:"omitempty,dive,keys,oneof=ru en,endkeys"`
+}
+
+// UpdateCategoryPayload - частичное обновление категории: не переданные в
+// запросе поля сохраняют прежние значения.
+type UpdateCategoryPayload struct {
+	Name         *string                        `json:"name" binding:"omitempty,min=1"`
+	Description  *string                        `json:"description"`
+	ParentID     *int                           `json:"parentId"`
+	Slug         *string                        `json:"slug"`
+	SortOrder    *int                           `json:"sortOrder"`
+	Icon         *string                        `json:"icon"`
+	Translations map[string]CategoryTranslation `json:"translations" binding:"omitempty,dive,keys,oneof=ru en,endkeys"`
+	// Clear перечисляет поля, которые нужно сбросить в null
+	Clear []string `json:"clear,omitempty" binding:"omitempty,dive,oneof=description parentId icon"`
+}
+
+// Clears сообщает, запрошен ли сброс поля при обновлении категории
+func (p UpdateCategoryPayload) Clears(field string) bool {
+	for _, f := range p.Clear {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryTranslation - название и описание категории на одном языке
+type CategoryTranslation struct {
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description"`
 }
 
 type Offer struct {
@@ -76,46 +136,130 @@ type OfferResponse struct {
 	AuthorID        string    `json:"authorId"`
 	AuthorFirstName string    `json:"authorFirstName"`
 	HasResponded    bool      `json:"hasResponded"`
+	CategoryID      *int      `json:"categoryId"`
+	// Attributes - значения полей категории
+	Attributes map[string]interface{} `json:"attributes"`
+	ExpiresAt  *time.Time             `json:"expiresAt"`
+}
+
+// OfferFilter задает условия выборки объявлений
+type OfferFilter struct {
+	OfferType string
+	Search    string
+	// Category - ID или slug категории; подкатегории включаются
+	Category   string
+	Attributes []AttributeFilter
+	// ViewerID - текущий пользователь для вычисления HasResponded, может быть nil
+	ViewerID *string
+}
+
+// OfferDeactivationFilter задает условия массового снятия объявлений с публикации.
+// Пустые поля не ограничивают выборку.
+type OfferDeactivationFilter struct {
+	AuthorID      *string
+	CategoryID    *int
+	CreatedBefore *time.Time
+}
+
+// Операции фильтрации по полям категории
+const (
+	AttributeFilterEq  = "eq"
+	AttributeFilterMin = "min"
+	AttributeFilterMax = "max"
+)
+
+// AttributeFilter - условие на значение поля категории
+type AttributeFilter struct {
+	Key   string
+	Op    string
+	Value string
+}
+
+// Статусы работы. Работа создается в статусе assigned, когда автор
+// объявления принимает отклик, и дальше переходит по JobTransitions.
+const (
+	JobStatusAssigned   = "assigned"
+	JobStatusInProgress = "in_progress"
+	JobStatusCompleted  = "completed"
+	JobStatusCancelled  = "cancelled"
+)
+
+// JobTransitions перечисляет допустимые переходы из каждого статуса работы
+var JobTransitions = map[string][]string{
+	JobStatusAssigned:   {JobStatusInProgress, JobStatusCancelled},
+	JobStatusInProgress: {JobStatusCompleted, JobStatusCancelled},
 }
 
+// CanTransitionJob сообщает, может ли работа перейти из статуса from в to
+func CanTransitionJob(from, to string) bool {
+	return slices.Contains(JobTransitions[from], to)
+}
+
+// Job - работа между заказчиком и мастером по принятому отклику
 type Job struct {
-	ID          string    `json:"id"`
-	OfferID     string    `json:"offerId"`
-	ClientID    string    `json:"clientId"`
-	MasterID    string    `json:"masterId"`
-	Status      string    `json:"status"`
+	ID           string     `json:"id"`
+	OfferID      string     `json:"offerId"`
+	OfferTitle   string     `json:"offerTitle"`
+	ClientID     string     `json:"clientId"`
+	MasterID     string     `json:"masterId"`
+	Status       string     `json:"status"`
 	ScheduledFor *time.Time `json:"scheduledFor"`
-	StartedAt   *time.Time `json:"startedAt"`
-	CompletedAt *time.Time `json:"completedAt"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	StartedAt    *time.Time `json:"startedAt"`
+	CompletedAt  *time.Time `json:"completedAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+type UpdateJobStatusPayload struct {
+	Status string `json:"status" binding:"required,oneof=in_progress completed cancelled"`
+}
+
+// Review - отзыв участника завершенной работы о другом участнике
+type Review struct {
+	ID        string    `json:"id"`
+	JobID     string    `json:"jobId"`
+	AuthorID  string    `json:"authorId"`
+	SubjectID string    `json:"subjectId"`
+	Rating    int       `json:"rating"`
+	Comment   *string   `json:"comment"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateReviewPayload struct {
+	Rating  int     `json:"rating" binding:"required,min=1,max=5"`
+	Comment *string `json:"comment"`
 }
 
 type AdminOfferResponse struct {
-	ID              string    `json:"id"`
-	Title           string    `json:"title"`
-	Description     string    `json:"description"`
-	OfferType       string    `json:"offerType"`
-	IsActive        bool      `json:"isActive"`
-	CreatedAt       time.Time `json:"createdAt"`
-	UpdatedAt       time.Time `json:"updatedAt"`
-	AuthorID        string    `json:"authorId"`
-	AuthorFirstName string    `json:"authorFirstName"`
-	AuthorEmail     string    `json:"authorEmail"`
+	ID              string     `json:"id"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	OfferType       string     `json:"offerType"`
+	IsActive        bool       `json:"isActive"`
+	ExpiresAt       *time.Time `json:"expiresAt"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	AuthorID        string     `json:"authorId"`
+	AuthorFirstName string     `json:"authorFirstName"`
+	AuthorEmail     string     `json:"authorEmail"`
 }
 
 type UserDetail struct {
-	ID                string    `json:"id"`
-	Email             string    `json:"email"`
-	Role              string    `json:"role"`
-	CreatedAt         time.Time `json:"createdAt"`
-	UpdatedAt         time.Time `json:"updatedAt"`
-	FirstName         *string   `json:"firstName"`
-	LastName          *string   `json:"lastName"`
-	PhoneNumber       *string   `json:"phoneNumber"`
-	Bio               *string   `json:"bio"`
-	YearsOfExperience *int      `json:"yearsOfExperience"`
-	AverageRating     *float64  `json:"averageRating"`
+	ID                string     `json:"id"`
+	Email             string     `json:"email"`
+	Role              string     `json:"role"`
+	Status            string     `json:"status"`
+	SuspendedUntil    *time.Time `json:"suspendedUntil,omitempty"`
+	SanctionReason    *string    `json:"sanctionReason,omitempty"`
+	Locale            string     `json:"locale"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+	FirstName         *string    `json:"firstName"`
+	LastName          *string    `json:"lastName"`
+	PhoneNumber       *string    `json:"phoneNumber"`
+	Bio               *string    `json:"bio"`
+	YearsOfExperience *int       `json:"yearsOfExperience"`
+	AverageRating     *float64   `json:"averageRating"`
 }
 
 type UpdateUserPayload struct {
@@ -124,9 +268,24 @@ type UpdateUserPayload struct {
 	PhoneNumber       *string `json:"phoneNumber"`
 	Bio               *string `json:"bio"`
 	YearsOfExperience *int    `json:"yearsOfExperience"`
+	Locale            *string `json:"locale" binding:"omitempty,oneof=ru en"`
 	IsAdmin           *bool   `json:"isAdmin"` // This will now update the 'role' column
 }
 
+// UploadAvatarPayload - фотография профиля в JPEG или PNG; в JSON передается в base64
+type UploadAvatarPayload struct {
+	Image []byte `json:"image" binding:"required"`
+}
+
+// Avatar - фотография профиля пользователя
+type Avatar struct {
+	ContentType string
+	Image       []byte
+	// Thumbnail - миниатюра в JPEG; nil, пока фоновая задача ее не построила
+	Thumbnail []byte
+	UpdatedAt time.Time
+}
+
 type AdminStats struct {
 	TotalUsers           int `json:"totalUsers"`
 	TotalOffers          int `json:"totalOffers"`
@@ -140,12 +299,80 @@ type User struct {
 	Email        string
 	PasswordHash string
 	Role         string
+	AccountStatus
+	// Created - пользователь создан при этом входе через OpenID Connect
+	Created bool
+}
+
+// Статусы учетной записи пользователя
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusBanned    = "banned"
+)
+
+// AccountStatus описывает текущее ограничение доступа пользователя
+type AccountStatus struct {
+	Status         string     `json:"status"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+	Reason         *string    `json:"reason,omitempty"`
+}
+
+// IsBlocked сообщает, действует ли ограничение на момент now.
+// Приостановка с истекшим сроком считается снятой.
+func (s AccountStatus) IsBlocked(now time.Time) bool {
+	switch s.Status {
+	case UserStatusBanned:
+		return true
+	case UserStatusSuspended:
+		return s.SuspendedUntil == nil || s.SuspendedUntil.After(now)
+	}
+	return false
+}
+
+// UserSanction - запись в истории санкций пользователя
+type UserSanction struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"userId"`
+	Action    string     `json:"action"`
+	Reason    *string    `json:"reason"`
+	ActorID   *string    `json:"actorId"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// SuspendUserPayload - тело запроса на временную приостановку пользователя
+type SuspendUserPayload struct {
+	Reason string    `json:"reason" binding:"required"`
+	Until  time.Time `json:"until" binding:"required"`
+}
+
+// BanUserPayload - тело запроса на блокировку пользователя
+type BanUserPayload struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// LiftSanctionPayload - тело запроса на снятие ограничений
+type LiftSanctionPayload struct {
+	Reason string `json:"reason"`
 }
 
 type ServiceCategory struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID          int     `json:"id"`
+	ParentID    *int    `json:"parentId"`
+	Slug        string  `json:"slug"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	SortOrder   int     `json:"sortOrder"`
+	Icon        *string `json:"icon"`
+	// Translations заполняется только в ответах для администратора
+	Translations map[string]CategoryTranslation `json:"translations,omitempty"`
+}
+
+// CategoryNode - узел дерева категорий
+type CategoryNode struct {
+	ServiceCategory
+	Children []*CategoryNode `json:"children"`
 }
 
 type Claims struct {
@@ -216,3 +443,360 @@ type ConversationPreview struct {
 	LastMessageAt        time.Time `json:"lastMessageAt"`
 	OfferTitle           string    `json:"offerTitle"`
 }
+
+// --- Soft Delete Models ---
+
+// DeletedEntity описывает мягко удаленную запись, которую можно восстановить
+type DeletedEntity struct {
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// TrashResponse используется для отображения корзины в панели администратора
+type TrashResponse struct {
+	Users      []DeletedEntity `json:"users"`
+	Offers     []DeletedEntity `json:"offers"`
+	Categories []DeletedEntity `json:"categories"`
+}
+
+// PurgeResult содержит количество окончательно удаленных записей
+type PurgeResult struct {
+	Users      int64 `json:"users"`
+	Offers     int64 `json:"offers"`
+	Categories int64 `json:"categories"`
+}
+
+// --- Audit Models ---
+
+// AuditEntry - запись журнала аудита привилегированных действий
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	ActorID    *string         `json:"actorId"`
+	ActorEmail *string         `json:"actorEmail"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType"`
+	TargetID   string          `json:"targetId"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IP         *string         `json:"ip"`
+	UserAgent  *string         `json:"userAgent"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// AuditFilter задает условия выборки журнала аудита
+type AuditFilter struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// --- Category Attribute Models ---
+
+// Типы полей категории
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeInteger = "integer"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeEnum    = "enum"
+)
+
+// CategoryAttribute описывает дополнительное поле объявления в категории
+type CategoryAttribute struct {
+	ID         int      `json:"id"`
+	CategoryID int      `json:"categoryId"`
+	Key        string   `json:"key"`
+	Label      string   `json:"label"`
+	Type       string   `json:"type"`
+	Required   bool     `json:"required"`
+	Options    []string `json:"options,omitempty"`
+	SortOrder  int      `json:"sortOrder"`
+}
+
+// CategoryAttributePayload - тело запроса на создание или изменение поля категории
+type CategoryAttributePayload struct {
+	Key       string   `json:"key" binding:"required,max=50"`
+	Label     string   `json:"label" binding:"required,max=100"`
+	Type      string   `json:"type" binding:"required,oneof=string integer number boolean enum"`
+	Required  bool     `json:"required"`
+	Options   []string `json:"options" binding:"omitempty,dive,required"`
+	SortOrder int      `json:"sortOrder"`
+}
+
+// Типы уведомлений
+const (
+	NotificationApplicationCreated  = "application.created"
+	NotificationApplicationAccepted = "application.accepted"
+	NotificationApplicationRejected = "application.rejected"
+	NotificationMessageCreated      = "message.created"
+	NotificationJobStatusChanged    = "job.status_changed"
+	NotificationReviewReceived      = "review.received"
+	NotificationOfferExpiring       = "offer.expiring"
+)
+
+// NotificationTypes перечисляет все типы уведомлений, которые пользователь может отключить
+var NotificationTypes = []string{
+	NotificationApplicationCreated,
+	NotificationApplicationAccepted,
+	NotificationApplicationRejected,
+	NotificationMessageCreated,
+	NotificationJobStatusChanged,
+	NotificationReviewReceived,
+	NotificationOfferExpiring,
+}
+
+// NotificationEmailTemplates сопоставляет типы уведомлений, о которых
+// сообщается письмом сразу, с шаблонами писем. Новые сообщения в чатах
+// отправляются периодической сводкой (шаблон EmailTemplateMessageDigest).
+var NotificationEmailTemplates = map[string]string{
+	NotificationApplicationCreated:  "application_created",
+	NotificationApplicationAccepted: "application_accepted",
+	NotificationJobStatusChanged:    "job_status_changed",
+}
+
+// EmailTemplateMessageDigest - шаблон сводки непрочитанных сообщений
+const EmailTemplateMessageDigest = "message_digest"
+
+// OutboxEmail - письмо из email_outbox, готовое к отправке
+type OutboxEmail struct {
+	ID        int64
+	To        string
+	Locale    string
+	Template  string
+	Data      map[string]interface{}
+	CreatedAt time.Time
+}
+
+// Notification - уведомление пользователя о событии
+type Notification struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	ReadAt    *time.Time      `json:"readAt"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// NotificationList - страница уведомлений и общее число непрочитанных
+type NotificationList struct {
+	Items       []Notification `json:"items"`
+	UnreadCount int            `json:"unreadCount"`
+}
+
+// NotificationFilter задает условия выборки уведомлений
+type NotificationFilter struct {
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+// UpdateApplicationStatusPayload - решение автора объявления по отклику
+type UpdateApplicationStatusPayload struct {
+	Status string `json:"status" binding:"required,oneof=accepted rejected"`
+}
+
+// Статусы фоновых задач
+const (
+	TaskStatusPending   = "pending"
+	TaskStatusRunning   = "running"
+	TaskStatusSucceeded = "succeeded"
+	TaskStatusDead      = "dead"
+)
+
+// Виды фоновых задач
+const (
+	TaskKindWebhookDelivery   = "webhook.deliver"
+	TaskKindEmailSend         = "email.send"
+	TaskKindRatingRecalculate = "rating.recalculate"
+	TaskKindAvatarThumbnail   = "avatar.thumbnail"
+)
+
+// EmailTask - данные фоновой задачи отправки письма из email_outbox
+type EmailTask struct {
+	EmailID int64 `json:"emailId"`
+}
+
+// UserTask - данные фоновых задач, относящихся к одному пользователю:
+// пересчета рейтинга и построения миниатюры фотографии
+type UserTask struct {
+	UserID string `json:"userId"`
+}
+
+// Task - фоновая задача из очереди
+type Task struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	RunAt       time.Time       `json:"runAt"`
+	LockedBy    *string         `json:"lockedBy"`
+	LockedAt    *time.Time      `json:"lockedAt"`
+	LastError   *string         `json:"lastError"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+	FinishedAt  *time.Time      `json:"finishedAt"`
+}
+
+// NewTask описывает задачу для постановки в очередь
+type NewTask struct {
+	Kind    string
+	Payload interface{}
+	// RunAt - время первого запуска; nil означает "как можно скорее"
+	RunAt *time.Time
+	// MaxAttempts - число попыток до перевода в dead; 0 означает значение по умолчанию
+	MaxAttempts int
+}
+
+// TaskFilter задает условия выборки задач в админке
+type TaskFilter struct {
+	Status string
+	Kind   string
+	Limit  int
+	Offset int
+}
+
+// События платформы, на которые подписываются вебхуки
+const (
+	WebhookEventOfferCreated       = "offer.created"
+	WebhookEventApplicationCreated = "application.created"
+	WebhookEventJobStatusChanged   = "job.status_changed"
+	WebhookEventMessageCreated     = "message.created"
+	WebhookEventUserRegistered     = "user.registered"
+	// WebhookEventTest отправляется только по запросу администратора
+	WebhookEventTest = "webhook.test"
+)
+
+// Webhook - внешний получатель событий. Secret возвращается только при
+// создании и смене ключа.
+type Webhook struct {
+	ID          int       `json:"id"`
+	URL         string    `json:"url"`
+	Description *string   `json:"description"`
+	Secret      string    `json:"secret,omitempty"`
+	Events      []string  `json:"events"`
+	IsActive    bool      `json:"isActive"`
+	CreatedBy   *string   `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// WebhookPayload - тело запроса на создание или изменение вебхука
+type WebhookPayload struct {
+	URL         string   `json:"url" binding:"required,http_url"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events" binding:"required,min=1,dive,oneof=offer.created application.created job.status_changed message.created user.registered"`
+	IsActive    *bool    `json:"isActive"`
+}
+
+// WebhookEvent - тело запроса, отправляемого получателю
+type WebhookEvent struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	CreatedAt time.Time              `json:"createdAt"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// WebhookDeliveryTask - данные фоновой задачи доставки события одному получателю
+type WebhookDeliveryTask struct {
+	WebhookID int          `json:"webhookId"`
+	Event     WebhookEvent `json:"event"`
+}
+
+// WebhookDelivery - запись журнала доставки
+type WebhookDelivery struct {
+	ID           int64     `json:"id"`
+	WebhookID    int       `json:"webhookId"`
+	EventID      string    `json:"eventId"`
+	EventType    string    `json:"eventType"`
+	Attempt      int       `json:"attempt"`
+	Success      bool      `json:"success"`
+	StatusCode   *int      `json:"statusCode"`
+	ResponseBody *string   `json:"responseBody"`
+	Error        *string   `json:"error"`
+	DurationMs   int       `json:"durationMs"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// Права ключей API. Запросы с токеном сессии не ограничиваются правами.
+const (
+	ScopeOffersRead         = "offers:read"
+	ScopeOffersWrite        = "offers:write"
+	ScopeApplicationsRead   = "applications:read"
+	ScopeApplicationsWrite  = "applications:write"
+	ScopeChatsRead          = "chats:read"
+	ScopeChatsWrite         = "chats:write"
+	ScopeProfileRead        = "profile:read"
+	ScopeProfileWrite       = "profile:write"
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+	// ScopeAdmin дает доступ к админке; выдается только администраторам
+	ScopeAdmin = "admin"
+)
+
+// APIKey - ключ API без секрета
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"userId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	LastUsedIP *string    `json:"lastUsedIp"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateAPIKeyPayload - тело запроса на создание ключа API
+type CreateAPIKeyPayload struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=offers:read offers:write applications:read applications:write chats:read chats:write profile:read profile:write notifications:read notifications:write admin"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// APIKeyCredentials - данные ключа API для проверки запроса
+type APIKeyCredentials struct {
+	ID        string
+	UserID    string
+	KeyHash   string
+	Scopes    []string
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+	IsAdmin   bool
+}
+
+// --- OpenID Connect Models ---
+
+// OIDCLoginState - незавершенный вход через провайдера OIDC
+type OIDCLoginState struct {
+	State        string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+// OIDCIdentity - учетная запись у провайдера OIDC и данные пользователя из ID-токена
+type OIDCIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      *string
+	PhoneNumber   *string
+	Locale        string
+}
+
+// OIDCProvider - провайдер OIDC, доступный для входа
+type OIDCProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
@@ -0,0 +1,40 @@
+package oidc
+
+import (
+	"sort"
+	"strings"
+
+	"masterdom/api/config"
+	"masterdom/api/models"
+)
+
+// Providers - настроенные провайдеры по имени
+type Providers map[string]*Provider
+
+// NewProviders создает провайдеров из конфигурации. Callback каждого
+// провайдера - <apiBaseURL>/api/auth/oidc/<имя>/callback.
+func NewProviders(configs []config.OIDCProviderConfig, apiBaseURL string) Providers {
+	providers := make(Providers, len(configs))
+	for _, c := range configs {
+		providers[c.Name] = NewProvider(Config{
+			Name:         c.Name,
+			DisplayName:  c.DisplayName,
+			Issuer:       c.Issuer,
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			Scopes:       c.Scopes,
+			RedirectURL:  strings.TrimSuffix(apiBaseURL, "/") + "/api/auth/oidc/" + c.Name + "/callback",
+		})
+	}
+	return providers
+}
+
+// List возвращает провайдеров для страницы входа, отсортированных по имени.
+func (ps Providers) List() []models.OIDCProvider {
+	list := make([]models.OIDCProvider, 0, len(ps))
+	for _, p := range ps {
+		list = append(list, models.OIDCProvider{Name: p.Name, DisplayName: p.DisplayName})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
@@ -0,0 +1,243 @@
+// Package oidc реализует вход через провайдеров OpenID Connect по схеме
+// authorization code + PKCE: ссылку на провайдера, обмен кода на токены и
+// проверку подписи и утверждений ID-токена.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"masterdom/api/models"
+	"masterdom/api/utils"
+)
+
+const requestTimeout = 10 * time.Second
+
+// signingMethods - алгоритмы подписи ID-токена, которые принимает сервер.
+// HS256 не допускается: ключом был бы client secret.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Config - настройки провайдера
+type Config struct {
+	// Name - идентификатор провайдера в URL, например "google"
+	Name         string
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// RedirectURL - адрес callback API, зарегистрированный у провайдера
+	RedirectURL string
+}
+
+// discovery - нужные поля документа /.well-known/openid-configuration
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider выполняет вход через одного провайдера. Документ discovery
+// загружается при первом обращении, ключи подписи кешируются.
+type Provider struct {
+	Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      *keySet
+}
+
+func NewProvider(cfg Config) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if cfg.DisplayName == "" {
+		cfg.DisplayName = cfg.Name
+	}
+	return &Provider{Config: cfg, client: &http.Client{Timeout: requestTimeout}}
+}
+
+// discover возвращает документ discovery, загружая его при первом вызове.
+// Неудачная загрузка повторяется при следующем входе.
+func (p *Provider) discover(ctx context.Context) (*discovery, *keySet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, p.keys, nil
+	}
+
+	var d discovery
+	if err := getJSON(ctx, p.client, strings.TrimSuffix(p.Issuer, "/")+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, nil, fmt.Errorf("failed to load %s discovery document: %w", p.Name, err)
+	}
+	if d.Issuer != p.Issuer {
+		return nil, nil, fmt.Errorf("%s discovery document has issuer %q, expected %q", p.Name, d.Issuer, p.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, nil, fmt.Errorf("%s discovery document is missing endpoints", p.Name)
+	}
+	p.discovery = &d
+	p.keys = &keySet{url: d.JWKSURI, client: p.client}
+	return p.discovery, p.keys, nil
+}
+
+// AuthCodeURL возвращает адрес страницы входа провайдера.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	d, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.ClientID},
+		"redirect_uri":          {p.RedirectURL},
+		"scope":                 {strings.Join(p.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// idTokenClaims - утверждения ID-токена, которые использует сервер
+type idTokenClaims struct {
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+	GivenName     string   `json:"given_name"`
+	FamilyName    string   `json:"family_name"`
+	PhoneNumber   string   `json:"phone_number"`
+	Locale        string   `json:"locale"`
+	jwt.RegisteredClaims
+}
+
+// flexBool принимает и true, и "true": часть провайдеров передает
+// email_verified строкой
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case bool:
+		*b = flexBool(v)
+	case string:
+		*b = flexBool(v == "true")
+	}
+	return nil
+}
+
+// Exchange обменивает код авторизации на токены, проверяет ID-токен и
+// возвращает данные пользователя из него.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*models.OIDCIdentity, error) {
+	d, keys, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.RedirectURL},
+		"client_id":     {p.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to decode token response (status %d): %w", resp.StatusCode, err)
+	}
+	if tokens.Error != "" {
+		return nil, fmt.Errorf("token request rejected: %s %s", tokens.Error, tokens.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || tokens.IDToken == "" {
+		return nil, fmt.Errorf("token response without id_token (status %d)", resp.StatusCode)
+	}
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(tokens.IDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return keys.key(ctx, kid)
+	},
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id_token: missing subject")
+	}
+	return claims.identity(p.Name), nil
+}
+
+func (c *idTokenClaims) identity(provider string) *models.OIDCIdentity {
+	identity := &models.OIDCIdentity{
+		Provider:      provider,
+		Subject:       c.Subject,
+		Email:         strings.TrimSpace(c.Email),
+		EmailVerified: bool(c.EmailVerified),
+		FirstName:     c.GivenName,
+	}
+	// user_details.first_name обязателен, поэтому подбираем замену из других утверждений
+	if identity.FirstName == "" {
+		identity.FirstName = c.Name
+	}
+	if identity.FirstName == "" {
+		identity.FirstName, _, _ = strings.Cut(identity.Email, "@")
+	}
+	if c.FamilyName != "" {
+		identity.LastName = &c.FamilyName
+	}
+	if c.PhoneNumber != "" {
+		identity.PhoneNumber = &c.PhoneNumber
+	}
+	if c.Locale != "" {
+		identity.Locale = utils.ParseAcceptLanguage(strings.ReplaceAll(c.Locale, "_", "-"))
+	}
+	return identity
+}
@@ -0,0 +1,191 @@
+// Package openapi описывает API в формате OpenAPI 3.1. Операции перечислены
+// в таблице routes, схемы тел запросов и ответов строятся по структурам
+// пакета models, поэтому документ меняется вместе с моделями. Соответствие
+// таблицы маршрутам сервера проверяется тестами пакета main.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Version - версия OpenAPI, которой соответствует документ
+const Version = "3.1.0"
+
+// Схемы аутентификации
+const (
+	BearerAuth = "bearerAuth"
+	APIKeyAuth = "apiKeyAuth"
+)
+
+// pathParam находит параметры пути в синтаксисе gin, например :id
+var pathParam = regexp.MustCompile(`:([A-Za-z]+)`)
+
+// Build строит документ по таблице маршрутов
+func Build() *Document {
+	registry := newSchemaRegistry()
+	for _, rt := range routes {
+		if rt.body != nil {
+			registry.markRequest(reflect.TypeOf(rt.body))
+		}
+	}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   "MasterDom API",
+			Version: "1.0.0",
+			Description: "API of the MasterDom service marketplace. Errors are returned as ErrorResponse " +
+				"with a stable code; the message is translated according to Accept-Language.",
+		},
+		Tags:  tags,
+		Paths: make(map[string]*PathItem),
+		Components: Components{
+			Schemas: registry.schemas,
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "Error",
+					Content:     jsonContent(registry.schemaFor(reflect.TypeOf(errorResponse{}))),
+				},
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				BearerAuth: {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "Session token issued on login",
+				},
+				APIKeyAuth: {
+					Type:        "apiKey",
+					In:          "header",
+					Name:        "X-API-Key",
+					Description: "API key; the scopes it needs are listed in the operation security requirements",
+				},
+			},
+		},
+	}
+
+	for _, rt := range routes {
+		path := pathParam.ReplaceAllString(rt.path, "{$1}")
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		slot := item.slot(rt.method)
+		if *slot != nil {
+			panic("openapi: duplicate operation " + rt.method + " " + rt.path)
+		}
+		*slot = rt.operation(registry)
+	}
+	return doc
+}
+
+// operation строит описание операции
+func (rt route) operation(registry *schemaRegistry) *Operation {
+	op := &Operation{
+		OperationID: rt.id,
+		Summary:     rt.summary,
+		Description: rt.description,
+		Tags:        []string{rt.tag},
+		Security:    rt.access.security(rt.scope),
+		Responses: map[string]*Response{
+			"default": {Ref: "#/components/responses/Error"},
+		},
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(rt.path, -1) {
+		op.Parameters = append(op.Parameters, rt.pathParameter(match[1]))
+	}
+	op.Parameters = append(op.Parameters, rt.query...)
+
+	if rt.body != nil {
+		op.RequestBody = &RequestBody{
+			Required: !rt.optionalBody,
+			Content:  jsonContent(registry.schemaFor(reflect.TypeOf(rt.body))),
+		}
+	}
+
+	status := rt.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = rt.response(registry, status, rt.result)
+	for extra, result := range rt.also {
+		op.Responses[strconv.Itoa(extra)] = rt.response(registry, extra, result)
+	}
+	return op
+}
+
+func (rt route) response(registry *schemaRegistry, status int, result interface{}) *Response {
+	resp := &Response{Description: http.StatusText(status)}
+	switch {
+	case status == http.StatusFound:
+		resp.Headers = map[string]*Header{
+			"Location": {Description: "Redirect target", Schema: &Schema{Type: Types{"string"}, Format: "uri"}},
+		}
+	case rt.contentType != "":
+		resp.Content = map[string]*MediaType{rt.contentType: {Schema: &Schema{Type: Types{"string"}}}}
+	case result != nil:
+		resp.Content = jsonContent(registry.schemaFor(reflect.TypeOf(result)))
+	}
+	return resp
+}
+
+// pathParameter описывает параметр пути. Категории, поля категорий, задачи и
+// вебхуки нумеруются целыми числами, остальные записи - UUID.
+func (rt route) pathParameter(name string) *Parameter {
+	schema := &Schema{Type: Types{"string"}, Format: "uuid"}
+	switch {
+	case name == "provider":
+		schema = &Schema{Type: Types{"string"}}
+	case name == "attributeId" || rt.intID:
+		schema = &Schema{Type: Types{"integer"}, Format: "int64"}
+	}
+	return &Parameter{Name: name, In: "path", Required: true, Schema: schema}
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
+
+var (
+	jsonOnce sync.Once
+	jsonDoc  []byte
+)
+
+// JSON возвращает документ в JSON. Документ строится один раз.
+func JSON() []byte {
+	jsonOnce.Do(func() {
+		var err error
+		jsonDoc, err = json.MarshalIndent(Build(), "", "  ")
+		if err != nil {
+			panic("openapi: failed to encode document: " + err.Error())
+		}
+	})
+	return jsonDoc
+}
+
+// RouteKey возвращает ключ операции "МЕТОД путь" с параметрами пути в
+// синтаксисе OpenAPI, например "GET /api/offers/{id}".
+func RouteKey(method, ginPath string) string {
+	return strings.ToUpper(method) + " " + pathParam.ReplaceAllString(ginPath, "{$1}")
+}
+
+// Operations возвращает ключи всех операций документа в порядке сортировки
+func (d *Document) Operations() []string {
+	var keys []string
+	for path, item := range d.Paths {
+		for method := range item.Operations() {
+			keys = append(keys, method+" "+path)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package openapi
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// templateParam находит параметры пути в синтаксисе OpenAPI, например {id}
+var templateParam = regexp.MustCompile(`\{([A-Za-z]+)\}`)
+
+func TestDocumentIsValid(t *testing.T) {
+	doc := Build()
+	if doc.OpenAPI != Version {
+		t.Errorf("openapi = %q, want %q", doc.OpenAPI, Version)
+	}
+
+	ids := make(map[string]string)
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			key := method + " " + path
+			if op.OperationID == "" {
+				t.Errorf("%s: missing operationId", key)
+			} else if other, ok := ids[op.OperationID]; ok {
+				t.Errorf("%s: operationId %s is also used by %s", key, op.OperationID, other)
+			}
+			ids[op.OperationID] = key
+
+			declared := make(map[string]bool)
+			for _, p := range op.Parameters {
+				if p.In == "path" {
+					declared[p.Name] = true
+					if !strings.Contains(path, "{"+p.Name+"}") {
+						t.Errorf("%s: path parameter %s is not in the path", key, p.Name)
+					}
+				}
+			}
+			for _, match := range templateParam.FindAllStringSubmatch(path, -1) {
+				if !declared[match[1]] {
+					t.Errorf("%s: path parameter %s is not declared", key, match[1])
+				}
+			}
+
+			success := false
+			for code := range op.Responses {
+				if status, err := strconv.Atoi(code); err == nil && status < 400 {
+					success = true
+				}
+			}
+			if !success {
+				t.Errorf("%s: no success response", key)
+			}
+			if op.Responses["default"] == nil {
+				t.Errorf("%s: no default error response", key)
+			}
+		}
+	}
+}
+
+// Все ссылки $ref указывают на существующие компоненты
+func TestReferencesResolve(t *testing.T) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(JSON(), &doc); err != nil {
+		t.Fatalf("failed to decode document: %v", err)
+	}
+	components := doc["components"].(map[string]interface{})
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok {
+				parts := strings.Split(strings.TrimPrefix(ref, "#/components/"), "/")
+				section, _ := components[parts[0]].(map[string]interface{})
+				if len(parts) != 2 || !strings.HasPrefix(ref, "#/components/") || section[parts[1]] == nil {
+					t.Errorf("unresolved reference %s", ref)
+				}
+			}
+			for _, item := range v {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(doc)
+}
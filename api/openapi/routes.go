@@ -0,0 +1,465 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+
+	"masterdom/api/apperr"
+	"masterdom/api/jwtkeys"
+	"masterdom/api/models"
+)
+
+// access - кто может вызвать операцию
+type access int
+
+const (
+	// public - без аутентификации
+	public access = iota
+	// optionalAuth - без аутентификации, но с ней ответ учитывает пользователя;
+	// ключ API должен иметь право scope
+	optionalAuth
+	// user - токен сессии или ключ API с правом scope
+	user
+	// sessionOnly - только токен сессии
+	sessionOnly
+	// admin - администратор с токеном сессии или ключом API с правом admin
+	admin
+)
+
+func (a access) security(scope string) []SecurityRequirement {
+	switch a {
+	case optionalAuth:
+		scopes := []string{}
+		if scope != "" {
+			scopes = append(scopes, scope)
+		}
+		return []SecurityRequirement{{}, {BearerAuth: {}}, {APIKeyAuth: scopes}}
+	case user:
+		return []SecurityRequirement{{BearerAuth: {}}, {APIKeyAuth: {scope}}}
+	case sessionOnly:
+		return []SecurityRequirement{{BearerAuth: {}}}
+	case admin:
+		return []SecurityRequirement{{BearerAuth: {}}, {APIKeyAuth: {models.ScopeAdmin}}}
+	}
+	return nil
+}
+
+// route описывает операцию API
+type route struct {
+	method, path string
+	id, tag      string
+	summary      string
+	description  string
+	access       access
+	scope        string
+	// intID - параметр :id пути целочисленный
+	intID bool
+	query []*Parameter
+	// body - значение типа тела запроса
+	body         interface{}
+	optionalBody bool
+	// status - код успешного ответа, по умолчанию 200
+	status int
+	// result - значение типа тела успешного ответа; nil, если тела нет
+	result interface{}
+	// contentType - тип тела ответа, если это не JSON
+	contentType string
+	// also - другие ответы, кроме ошибок, по кодам
+	also map[int]interface{}
+}
+
+// schemaNames задает имена схем для типов, имена которых неоднозначны вне своего пакета
+var schemaNames = map[reflect.Type]string{
+	reflect.TypeOf(apperr.Response{}): "ErrorResponse",
+	reflect.TypeOf(apiKeyCreated{}):   "APIKeyCreated",
+}
+
+// errorResponse - тело ответа с ошибкой
+type errorResponse = apperr.Response
+
+// Тела ответов, которые обработчики формируют без отдельных моделей
+
+type healthStatus struct {
+	Status string `json:"status"`
+}
+
+type readiness struct {
+	Status   string        `json:"status"`
+	Database string        `json:"database,omitempty"`
+	Schema   *schemaStatus `json:"schema,omitempty"`
+}
+
+type schemaStatus struct {
+	Version  int  `json:"version"`
+	Expected int  `json:"expected"`
+	Dirty    bool `json:"dirty"`
+}
+
+type actionResult struct {
+	Message string `json:"message"`
+}
+
+type registered struct {
+	Message string `json:"message"`
+	UserID  string `json:"userId"`
+}
+
+type loginResult struct {
+	Token   string `json:"token"`
+	IsAdmin bool   `json:"isAdmin"`
+}
+
+type offerCreated struct {
+	OfferID string `json:"offerId"`
+}
+
+type applicationCreated struct {
+	ResponseID string `json:"responseId"`
+}
+
+type reviewCreated struct {
+	ReviewID string `json:"reviewId"`
+}
+
+type offerRenewed struct {
+	Message   string    `json:"message"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type apiKeyCreated struct {
+	APIKey models.APIKey `json:"apiKey"`
+	Key    string        `json:"key"`
+}
+
+type notificationsMarked struct {
+	Message string `json:"message"`
+	Updated int64  `json:"updated"`
+}
+
+type chatInitiated struct {
+	ConversationID string `json:"conversationId"`
+}
+
+type categoryCreated struct {
+	Message    string `json:"message"`
+	CategoryID int    `json:"categoryId"`
+}
+
+type attributeCreated struct {
+	Message     string `json:"message"`
+	AttributeID int    `json:"attributeId"`
+}
+
+type webhookCreated struct {
+	Message   string `json:"message"`
+	WebhookID int    `json:"webhookId"`
+	Secret    string `json:"secret"`
+}
+
+type secretRotated struct {
+	Message string `json:"message"`
+	Secret  string `json:"secret"`
+}
+
+type webhookTestQueued struct {
+	Message string `json:"message"`
+	EventID string `json:"eventId"`
+}
+
+// Параметры строки запроса
+
+func queryParam(name, description string, schema *Schema) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func stringSchema() *Schema {
+	return &Schema{Type: Types{"string"}}
+}
+
+func integerSchema() *Schema {
+	return &Schema{Type: Types{"integer"}, Format: "int64"}
+}
+
+func enumSchema(values ...string) *Schema {
+	return &Schema{Type: Types{"string"}, Enum: values}
+}
+
+func dateTimeSchema() *Schema {
+	return &Schema{Type: Types{"string"}, Format: "date-time"}
+}
+
+// attributeFilter описывает фильтр вида attr[key]=value
+func attributeFilter(name, description string, values *Schema) *Parameter {
+	explode := true
+	return &Parameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Style:       "deepObject",
+		Explode:     &explode,
+		Schema:      &Schema{Type: Types{"object"}, AdditionalProperties: values},
+	}
+}
+
+var (
+	limitParam  = queryParam("limit", "Page size", integerSchema())
+	offsetParam = queryParam("offset", "Number of items to skip", integerSchema())
+	langParam   = queryParam("lang", "Response language; defaults to Accept-Language", enumSchema("ru", "en"))
+
+	auditFilterParams = []*Parameter{
+		queryParam("actorId", "Only actions of this user", stringSchema()),
+		queryParam("action", "Only this action, e.g. user.ban", stringSchema()),
+		queryParam("targetType", "Only this target type, e.g. user", stringSchema()),
+		queryParam("targetId", "Only this target", stringSchema()),
+		queryParam("from", "Only entries created at or after this time", dateTimeSchema()),
+		queryParam("to", "Only entries created before this time", dateTimeSchema()),
+	}
+)
+
+var tags = []Tag{
+	{Name: "health", Description: "Probes and public keys"},
+	{Name: "auth", Description: "Registration and sign-in"},
+	{Name: "offers", Description: "Offers and applications"},
+	{Name: "categories", Description: "Service categories"},
+	{Name: "profile", Description: "Current user profile"},
+	{Name: "api-keys", Description: "API keys of the current user"},
+	{Name: "jobs", Description: "Jobs started from accepted applications"},
+	{Name: "notifications", Description: "Notifications and their settings"},
+	{Name: "chats", Description: "Conversations between users"},
+	{Name: "admin", Description: "Administration"},
+}
+
+// routes перечисляет все маршруты сервера
+var routes = []route{
+	// Health
+	{method: "GET", path: "/livez", id: "livez", tag: "health", summary: "Liveness probe",
+		result: healthStatus{}},
+	{method: "GET", path: "/readyz", id: "readyz", tag: "health", summary: "Readiness probe",
+		description: "Fails while the database is unreachable, the schema is behind this build or the server is shutting down.",
+		result:      readiness{}, also: map[int]interface{}{http.StatusServiceUnavailable: readiness{}}},
+	{method: "GET", path: "/.well-known/jwks.json", id: "getJWKS", tag: "health", summary: "Public keys for verifying session tokens",
+		result: jwtkeys.JWKSet{}},
+	{method: "GET", path: "/api/health", id: "health", tag: "health", summary: "Readiness probe under the API prefix",
+		result: readiness{}, also: map[int]interface{}{http.StatusServiceUnavailable: readiness{}}},
+	{method: "GET", path: "/api/openapi.json", id: "getOpenAPI", tag: "health", summary: "This OpenAPI document",
+		result: map[string]interface{}{}},
+
+	// Auth
+	{method: "POST", path: "/api/auth/register", id: "register", tag: "auth", summary: "Register a user",
+		body: models.RegisterPayload{}, status: http.StatusCreated, result: registered{}},
+	{method: "POST", path: "/api/auth/login", id: "login", tag: "auth", summary: "Sign in with email and password",
+		body: models.LoginPayload{}, result: loginResult{}},
+	{method: "GET", path: "/api/auth/oidc/providers", id: "getOIDCProviders", tag: "auth", summary: "List OpenID Connect providers",
+		result: []models.OIDCProvider{}},
+	{method: "GET", path: "/api/auth/oidc/:provider/login", id: "oidcLogin", tag: "auth", summary: "Start sign-in with an OpenID Connect provider",
+		description: "Redirects the browser to the provider's login page.",
+		status:      http.StatusFound},
+	{method: "GET", path: "/api/auth/oidc/:provider/callback", id: "oidcCallback", tag: "auth", summary: "Complete sign-in with an OpenID Connect provider",
+		description: "Redirects the browser to the web app with the session token or an error code in the URL fragment.",
+		query: []*Parameter{
+			queryParam("state", "State issued by oidcLogin", stringSchema()),
+			queryParam("code", "Authorization code", stringSchema()),
+			queryParam("error", "Error reported by the provider", stringSchema()),
+		},
+		status: http.StatusFound},
+
+	// Public catalog
+	{method: "GET", path: "/api/offers", id: "getOffers", tag: "offers", summary: "List active offers",
+		description: "Signed-in users also see whether they have responded to each offer.",
+		access:      optionalAuth, scope: models.ScopeOffersRead,
+		query: []*Parameter{
+			queryParam("type", "Offer type", enumSchema("request_for_service", "service_offer")),
+			queryParam("search", "Text to search in titles and descriptions", stringSchema()),
+			queryParam("category", "Category ID or slug; subcategories are included", stringSchema()),
+			attributeFilter("attr", "Attribute equals the value, e.g. attr[material]=brick", stringSchema()),
+			attributeFilter("attrMin", "Numeric attribute is at least the value", stringSchema()),
+			attributeFilter("attrMax", "Numeric attribute is at most the value", stringSchema()),
+		},
+		result: []models.OfferResponse{}},
+	{method: "GET", path: "/api/categories", id: "getAllCategories", tag: "categories", summary: "List categories",
+		query: []*Parameter{langParam}, result: []models.ServiceCategory{}},
+	{method: "GET", path: "/api/categories/tree", id: "getCategoryTree", tag: "categories", summary: "Category tree",
+		query: []*Parameter{langParam}, result: []models.CategoryNode{}},
+	{method: "GET", path: "/api/categories/:id/attributes", id: "getCategoryAttributes", tag: "categories", summary: "List attributes of a category",
+		intID: true, result: []models.CategoryAttribute{}},
+
+	// Profile and offers of the current user
+	{method: "GET", path: "/api/profile", id: "getMyProfile", tag: "profile", summary: "Get the current user profile",
+		access: user, scope: models.ScopeProfileRead, result: models.UserDetail{}},
+	{method: "PATCH", path: "/api/profile", id: "updateMyProfile", tag: "profile", summary: "Update the current user profile",
+		description: "isAdmin is ignored.",
+		access:      user, scope: models.ScopeProfileWrite, body: models.UpdateUserPayload{}, result: actionResult{}},
+	{method: "PUT", path: "/api/profile/avatar", id: "uploadMyAvatar", tag: "profile", summary: "Upload the current user avatar",
+		description: "The image is a base64-encoded JPEG or PNG of up to 5 MiB. The thumbnail is built in the background.",
+		access:      user, scope: models.ScopeProfileWrite, body: models.UploadAvatarPayload{}, result: actionResult{}},
+	{method: "GET", path: "/api/users/:id/avatar", id: "getUserAvatar", tag: "profile", summary: "Get a user avatar",
+		description: "With size=thumbnail returns the JPEG thumbnail, or the full image until the thumbnail is built.",
+		access:      public,
+		query:       []*Parameter{queryParam("size", "Image size", enumSchema("full", "thumbnail"))},
+		contentType: "image/*"},
+	{method: "POST", path: "/api/offers", id: "createOffer", tag: "offers", summary: "Create an offer",
+		access: user, scope: models.ScopeOffersWrite, body: models.CreateOfferPayload{},
+		status: http.StatusCreated, result: offerCreated{}},
+	{method: "POST", path: "/api/offers/:id/respond", id: "respondToOffer", tag: "offers", summary: "Respond to an offer",
+		access: user, scope: models.ScopeApplicationsWrite, body: models.RespondToOfferPayload{},
+		status: http.StatusCreated, result: applicationCreated{}},
+	{method: "POST", path: "/api/offers/:id/renew", id: "renewOffer", tag: "offers", summary: "Extend the publication of an own offer",
+		access: user, scope: models.ScopeOffersWrite, result: offerRenewed{}},
+	{method: "GET", path: "/api/offers/:id/applications", id: "getOfferApplications", tag: "offers", summary: "List applications to an own offer",
+		access: user, scope: models.ScopeApplicationsRead, result: []models.OfferApplication{}},
+	{method: "PATCH", path: "/api/offers/:id/applications/:applicationId", id: "updateApplicationStatus", tag: "offers", summary: "Accept or reject an application",
+		access: user, scope: models.ScopeApplicationsWrite, body: models.UpdateApplicationStatusPayload{}, result: actionResult{}},
+
+	// Jobs
+	{method: "GET", path: "/api/jobs", id: "getMyJobs", tag: "jobs", summary: "List jobs of the current user",
+		description: "Jobs where the user is the client or the master, newest first.",
+		access:      user, scope: models.ScopeApplicationsRead, result: []models.Job{}},
+	{method: "PATCH", path: "/api/jobs/:id", id: "updateJobStatus", tag: "jobs", summary: "Change the status of a job",
+		description: "A job starts as assigned when an application is accepted, then moves to in_progress and completed; it can be cancelled until completed. The other participant is notified.",
+		access:      user, scope: models.ScopeApplicationsWrite, body: models.UpdateJobStatusPayload{}, result: actionResult{}},
+	{method: "POST", path: "/api/jobs/:id/review", id: "createJobReview", tag: "jobs", summary: "Review the other participant of a completed job",
+		description: "Each participant can review a job once. The rating updates the average rating of the reviewed user.",
+		access:      user, scope: models.ScopeApplicationsWrite, body: models.CreateReviewPayload{},
+		status: http.StatusCreated, result: reviewCreated{}},
+
+	// API keys
+	{method: "GET", path: "/api/api-keys", id: "getMyAPIKeys", tag: "api-keys", summary: "List own API keys",
+		access: sessionOnly, result: []models.APIKey{}},
+	{method: "POST", path: "/api/api-keys", id: "createAPIKey", tag: "api-keys", summary: "Create an API key",
+		description: "The key is returned only in this response.",
+		access:      sessionOnly, body: models.CreateAPIKeyPayload{}, status: http.StatusCreated, result: apiKeyCreated{}},
+	{method: "DELETE", path: "/api/api-keys/:id", id: "revokeMyAPIKey", tag: "api-keys", summary: "Revoke an own API key",
+		access: sessionOnly, result: actionResult{}},
+
+	// Notifications
+	{method: "GET", path: "/api/notifications", id: "getNotifications", tag: "notifications", summary: "List notifications",
+		access: user, scope: models.ScopeNotificationsRead,
+		query: []*Parameter{
+			queryParam("unread", "Only unread notifications", &Schema{Type: Types{"boolean"}}),
+			limitParam, offsetParam,
+		},
+		result: models.NotificationList{}},
+	{method: "POST", path: "/api/notifications/read-all", id: "markAllNotificationsRead", tag: "notifications", summary: "Mark all notifications as read",
+		access: user, scope: models.ScopeNotificationsWrite, result: notificationsMarked{}},
+	{method: "POST", path: "/api/notifications/:id/read", id: "markNotificationRead", tag: "notifications", summary: "Mark a notification as read",
+		access: user, scope: models.ScopeNotificationsWrite, result: actionResult{}},
+	{method: "GET", path: "/api/notifications/preferences", id: "getNotificationPreferences", tag: "notifications", summary: "Get notification settings",
+		description: "Maps each notification type to whether it is enabled.",
+		access:      user, scope: models.ScopeNotificationsRead, result: map[string]bool{}},
+	{method: "PUT", path: "/api/notifications/preferences", id: "updateNotificationPreferences", tag: "notifications", summary: "Update notification settings",
+		description: "Types that are not listed keep their current setting.",
+		access:      user, scope: models.ScopeNotificationsWrite, body: map[string]bool{}, result: map[string]bool{}},
+
+	// Chats
+	{method: "GET", path: "/api/chats", id: "getConversations", tag: "chats", summary: "List conversations",
+		access: user, scope: models.ScopeChatsRead, result: []models.ConversationPreview{}},
+	{method: "POST", path: "/api/chats/initiate", id: "initiateChat", tag: "chats", summary: "Start or reopen a conversation about an offer",
+		access: user, scope: models.ScopeChatsWrite, body: models.InitiateChatPayload{}, result: chatInitiated{}},
+	{method: "GET", path: "/api/chats/:id", id: "getChatDetails", tag: "chats", summary: "Get a conversation",
+		access: user, scope: models.ScopeChatsRead, result: models.ChatDetailsResponse{}},
+	{method: "GET", path: "/api/chats/:id/messages", id: "getMessages", tag: "chats", summary: "List messages of a conversation",
+		access: user, scope: models.ScopeChatsRead, result: []models.MessageResponse{}},
+	{method: "POST", path: "/api/chats/:id/messages", id: "postMessage", tag: "chats", summary: "Send a message",
+		access: user, scope: models.ScopeChatsWrite, body: models.SendMessagePayload{},
+		status: http.StatusCreated, result: models.MessageResponse{}},
+
+	// Admin: users
+	{method: "GET", path: "/api/admin/users", id: "getUsers", tag: "admin", summary: "List users",
+		access: admin, result: []models.UserDetail{}},
+	{method: "GET", path: "/api/admin/users/:id", id: "getUserByID", tag: "admin", summary: "Get a user",
+		access: admin, result: models.UserDetail{}},
+	{method: "PATCH", path: "/api/admin/users/:id", id: "updateUser", tag: "admin", summary: "Update a user",
+		access: admin, body: models.UpdateUserPayload{}, result: actionResult{}},
+	{method: "DELETE", path: "/api/admin/users/:id", id: "deleteUser", tag: "admin", summary: "Delete a user",
+		access: admin, result: actionResult{}},
+	{method: "POST", path: "/api/admin/users/:id/suspend", id: "suspendUser", tag: "admin", summary: "Suspend a user until a date",
+		access: admin, body: models.SuspendUserPayload{}, result: actionResult{}},
+	{method: "POST", path: "/api/admin/users/:id/ban", id: "banUser", tag: "admin", summary: "Ban a user",
+		access: admin, body: models.BanUserPayload{}, result: actionResult{}},
+	{method: "POST", path: "/api/admin/users/:id/lift-sanction", id: "liftUserSanction", tag: "admin", summary: "Lift a suspension or ban",
+		access: admin, body: models.LiftSanctionPayload{}, optionalBody: true, result: actionResult{}},
+	{method: "GET", path: "/api/admin/users/:id/sanctions", id: "getUserSanctions", tag: "admin", summary: "List sanctions of a user",
+		access: admin, result: []models.UserSanction{}},
+	{method: "POST", path: "/api/admin/users/:id/restore", id: "restoreUser", tag: "admin", summary: "Restore a deleted user",
+		access: admin, result: actionResult{}},
+
+	// Admin: offers
+	{method: "GET", path: "/api/admin/offers", id: "getAdminAllOffers", tag: "admin", summary: "List all offers",
+		access: admin, result: []models.AdminOfferResponse{}},
+	{method: "PATCH", path: "/api/admin/offers/:id", id: "updateOfferStatus", tag: "admin", summary: "Publish or unpublish an offer",
+		access: admin, body: models.UpdateOfferPayload{}, result: actionResult{}},
+	{method: "DELETE", path: "/api/admin/offers/:id", id: "deleteOffer", tag: "admin", summary: "Delete an offer",
+		access: admin, result: actionResult{}},
+	{method: "POST", path: "/api/admin/offers/:id/restore", id: "restoreOffer", tag: "admin", summary: "Restore a deleted offer",
+		access: admin, result: actionResult{}},
+
+	// Admin: categories
+	{method: "GET", path: "/api/admin/categories", id: "getAdminCategories", tag: "admin", summary: "List categories with all translations",
+		access: admin, result: []models.ServiceCategory{}},
+	{method: "POST", path: "/api/admin/categories", id: "createCategory", tag: "admin", summary: "Create a category",
+		access: admin, body: models.CategoryPayload{}, status: http.StatusCreated, result: categoryCreated{}},
+	{method: "PATCH", path: "/api/admin/categories/:id", id: "updateCategory", tag: "admin", summary: "Update a category",
+		access: admin, intID: true, body: models.UpdateCategoryPayload{}, result: actionResult{}},
+	{method: "DELETE", path: "/api/admin/categories/:id", id: "deleteCategory", tag: "admin", summary: "Delete a category",
+		description: "A category that still has subcategories or offers can only be deleted when reassignTo names the category that receives them.",
+		access:      admin, intID: true,
+		query:  []*Parameter{queryParam("reassignTo", "Category that receives the offers", integerSchema())},
+		result: actionResult{}},
+	{method: "POST", path: "/api/admin/categories/:id/restore", id: "restoreCategory", tag: "admin", summary: "Restore a deleted category",
+		access: admin, intID: true, result: actionResult{}},
+	{method: "POST", path: "/api/admin/categories/:id/attributes", id: "createCategoryAttribute", tag: "admin", summary: "Add an attribute to a category",
+		access: admin, intID: true, body: models.CategoryAttributePayload{}, status: http.StatusCreated, result: attributeCreated{}},
+	{method: "PATCH", path: "/api/admin/categories/:id/attributes/:attributeId", id: "updateCategoryAttribute", tag: "admin", summary: "Update a category attribute",
+		access: admin, intID: true, body: models.CategoryAttributePayload{}, result: actionResult{}},
+	{method: "DELETE", path: "/api/admin/categories/:id/attributes/:attributeId", id: "deleteCategoryAttribute", tag: "admin", summary: "Delete a category attribute",
+		access: admin, intID: true, result: actionResult{}},
+
+	// Admin: operations
+	{method: "GET", path: "/api/admin/trash", id: "getTrash", tag: "admin", summary: "List deleted records that can be restored",
+		access: admin, result: models.TrashResponse{}},
+	{method: "GET", path: "/api/admin/audit", id: "getAuditLog", tag: "admin", summary: "Search the audit log",
+		access: admin, query: append(append([]*Parameter{}, auditFilterParams...), limitParam, offsetParam),
+		result: []models.AuditEntry{}},
+	{method: "GET", path: "/api/admin/audit/export", id: "exportAuditLog", tag: "admin", summary: "Export the audit log as CSV",
+		access: admin, query: auditFilterParams, contentType: "text/csv"},
+	{method: "GET", path: "/api/admin/tasks", id: "getTasks", tag: "admin", summary: "List background tasks",
+		access: admin,
+		query: []*Parameter{
+			queryParam("status", "Task status", enumSchema(models.TaskStatusPending, models.TaskStatusRunning, models.TaskStatusSucceeded, models.TaskStatusDead)),
+			queryParam("kind", "Task kind", stringSchema()),
+			limitParam, offsetParam,
+		},
+		result: []models.Task{}},
+	{method: "GET", path: "/api/admin/tasks/:id", id: "getTask", tag: "admin", summary: "Get a background task",
+		access: admin, intID: true, result: models.Task{}},
+	{method: "POST", path: "/api/admin/tasks/:id/retry", id: "retryTask", tag: "admin", summary: "Requeue a dead task",
+		access: admin, intID: true, result: actionResult{}},
+
+	// Admin: webhooks
+	{method: "GET", path: "/api/admin/webhooks", id: "getWebhooks", tag: "admin", summary: "List webhooks",
+		access: admin, result: []models.Webhook{}},
+	{method: "POST", path: "/api/admin/webhooks", id: "createWebhook", tag: "admin", summary: "Create a webhook",
+		description: "The signing secret is returned only in this response and after rotation.",
+		access:      admin, body: models.WebhookPayload{}, status: http.StatusCreated, result: webhookCreated{}},
+	{method: "GET", path: "/api/admin/webhooks/:id", id: "getWebhook", tag: "admin", summary: "Get a webhook",
+		access: admin, intID: true, result: models.Webhook{}},
+	{method: "PATCH", path: "/api/admin/webhooks/:id", id: "updateWebhook", tag: "admin", summary: "Update a webhook",
+		access: admin, intID: true, body: models.WebhookPayload{}, result: actionResult{}},
+	{method: "DELETE", path: "/api/admin/webhooks/:id", id: "deleteWebhook", tag: "admin", summary: "Delete a webhook",
+		access: admin, intID: true, result: actionResult{}},
+	{method: "POST", path: "/api/admin/webhooks/:id/rotate-secret", id: "rotateWebhookSecret", tag: "admin", summary: "Generate a new signing secret",
+		access: admin, intID: true, result: secretRotated{}},
+	{method: "POST", path: "/api/admin/webhooks/:id/test", id: "testWebhook", tag: "admin", summary: "Queue a test event",
+		access: admin, intID: true, status: http.StatusAccepted, result: webhookTestQueued{}},
+	{method: "GET", path: "/api/admin/webhooks/:id/deliveries", id: "getWebhookDeliveries", tag: "admin", summary: "List delivery attempts",
+		access: admin, intID: true, query: []*Parameter{limitParam, offsetParam}, result: []models.WebhookDelivery{}},
+
+	// Admin: API keys and stats
+	{method: "GET", path: "/api/admin/api-keys", id: "getAllAPIKeys", tag: "admin", summary: "List API keys of all users",
+		access: admin, result: []models.APIKey{}},
+	{method: "DELETE", path: "/api/admin/api-keys/:id", id: "revokeAPIKey", tag: "admin", summary: "Revoke any API key",
+		access: admin, result: actionResult{}},
+	{method: "GET", path: "/api/admin/stats", id: "getAdminStats", tag: "admin", summary: "Platform totals",
+		access: admin, result: models.AdminStats{}},
+}
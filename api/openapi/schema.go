@@ -0,0 +1,238 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaRegistry строит схемы по типам Go и собирает схемы структур в
+// components/schemas. Имя схемы - имя типа Go с заглавной буквы.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	types   map[string]reflect.Type
+	// requests - структуры, которые приходят в телах запросов. В них
+	// обязательность поля задает правило required в теге binding, в остальных
+	// структурах обязательны поля без omitempty.
+	requests map[reflect.Type]bool
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas:  make(map[string]*Schema),
+		types:    make(map[string]reflect.Type),
+		requests: make(map[reflect.Type]bool),
+	}
+}
+
+// markRequest отмечает t и вложенные в него структуры как типы тел запросов
+func (r *schemaRegistry) markRequest(t reflect.Type) {
+	t = indirect(t)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		r.markRequest(t.Elem())
+	case reflect.Struct:
+		if t == timeType || r.requests[t] {
+			return
+		}
+		r.requests[t] = true
+		for _, f := range fields(t) {
+			r.markRequest(f.Type)
+		}
+	}
+}
+
+// schemaFor возвращает схему значения типа t. Для структур возвращается
+// ссылка на схему в components/schemas.
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	nullable := false
+	if t.Kind() == reflect.Pointer {
+		nullable = true
+		t = indirect(t)
+	}
+
+	var s *Schema
+	switch {
+	case t == timeType:
+		s = &Schema{Type: Types{"string"}, Format: "date-time"}
+	case t == rawMessageType, t.Kind() == reflect.Interface:
+		// Произвольное значение JSON
+		return &Schema{}
+	case t.Kind() == reflect.Struct:
+		// Ссылка на структуру не помечается как null: указатели на структуры
+		// в моделях означают необязательность, а не значение null
+		return &Schema{Ref: r.component(t)}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		// encoding/json передает []byte строкой base64
+		s = &Schema{Type: Types{"string"}, Format: "byte"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		s = &Schema{Type: Types{"array"}, Items: r.schemaFor(t.Elem())}
+	case t.Kind() == reflect.Map:
+		s = &Schema{Type: Types{"object"}, AdditionalProperties: r.schemaFor(t.Elem())}
+	default:
+		s = primitive(t)
+	}
+	if nullable {
+		s.Type = append(s.Type, "null")
+	}
+	return s
+}
+
+// component регистрирует схему структуры и возвращает ссылку на нее
+func (r *schemaRegistry) component(t reflect.Type) string {
+	name := schemaName(t)
+	ref := "#/components/schemas/" + name
+	if known, ok := r.types[name]; ok {
+		if known != t {
+			panic(fmt.Sprintf("openapi: schema name %s is used by %s and %s", name, known, t))
+		}
+		return ref
+	}
+	r.types[name] = t
+
+	s := &Schema{Type: Types{"object"}, Properties: make(map[string]*Schema)}
+	r.schemas[name] = s
+	for _, f := range fields(t) {
+		name, omitempty := jsonName(f)
+		prop := r.schemaFor(f.Type)
+		required := applyBinding(prop, f.Tag.Get("binding"))
+		if !r.requests[t] {
+			required = !omitempty
+		}
+		s.Properties[name] = prop
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return ref
+}
+
+// fields возвращает поля структуры так, как их видит encoding/json: поля
+// встроенных структур поднимаются на уровень родителя.
+func fields(t reflect.Type) []reflect.StructField {
+	var result []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" && indirect(f.Type).Kind() == reflect.Struct {
+			result = append(result, fields(indirect(f.Type))...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// jsonName возвращает имя поля в JSON и признак omitempty
+func jsonName(f reflect.StructField) (string, bool) {
+	name, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, strings.Contains(","+options+",", ",omitempty,")
+}
+
+// applyBinding переносит в схему правила проверки из тега binding и
+// сообщает, обязательно ли поле. Правила после dive относятся к элементам
+// списка, между keys и endkeys - к ключам словаря.
+func applyBinding(s *Schema, binding string) bool {
+	if binding == "" {
+		return false
+	}
+	required := false
+	target := s
+	for _, rule := range strings.Split(binding, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if target == s {
+				required = true
+			}
+		case "dive":
+			if target.Items != nil {
+				target = target.Items
+			} else if target.AdditionalProperties != nil {
+				target = target.AdditionalProperties
+			}
+		case "keys":
+			s.PropertyNames = &Schema{Type: Types{"string"}}
+			target = s.PropertyNames
+		case "endkeys":
+			target = s.AdditionalProperties
+		case "oneof":
+			target.Enum = strings.Fields(param)
+		case "email":
+			target.Format = "email"
+		case "http_url":
+			target.Format = "uri"
+		case "min", "max":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			isArray := len(target.Type) > 0 && target.Type[0] == "array"
+			switch {
+			case name == "min" && isArray:
+				target.MinItems = &n
+			case name == "max" && isArray:
+				target.MaxItems = &n
+			case name == "min":
+				target.MinLength = &n
+			default:
+				target.MaxLength = &n
+			}
+		}
+	}
+	return required
+}
+
+// primitive возвращает схему скалярного типа
+func primitive(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}, Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: Types{"integer"}, Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: Types{"number"}, Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: Types{"number"}, Format: "double"}
+	}
+	panic("openapi: unsupported type " + t.String())
+}
+
+// schemaName возвращает имя схемы для структуры
+func schemaName(t reflect.Type) string {
+	if name, ok := schemaNames[t]; ok {
+		return name
+	}
+	name := []rune(t.Name())
+	name[0] = unicode.ToUpper(name[0])
+	return string(name)
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
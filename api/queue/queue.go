@@ -0,0 +1,173 @@
+// Package queue выполняет фоновые задачи из таблицы tasks пулом воркеров с
+// повторами, задержкой между ними и переводом в dead. Через очередь
+// отправляются письма, доставляются вебхуки, строятся миниатюры фотографий
+// профиля и пересчитываются рейтинги (виды models.TaskKind*). Обработчики
+// видов задач регистрируются в main.go.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"masterdom/api/logging"
+	"masterdom/api/models"
+	"masterdom/api/store"
+)
+
+// Handler выполняет задачу. Возвращенная ошибка приводит к повторной попытке
+// с задержкой, если только она не обернута в Permanent.
+type Handler func(ctx context.Context, task models.Task) error
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку как неустранимую: задача сразу переводится в dead без повторов.
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+const (
+	pollInterval = time.Second
+	// lease - максимальное время выполнения задачи; задача, захваченная
+	// воркером дольше lease назад, считается брошенной и выполняется заново
+	lease      = 10 * time.Minute
+	minBackoff = 10 * time.Second
+	maxBackoff = time.Hour
+)
+
+// Queue - пул воркеров, выполняющих задачи из таблицы tasks.
+type Queue struct {
+	store    store.Store
+	workers  int
+	handlers map[string]Handler
+	kinds    []string
+	hostname string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(s store.Store, workers int) *Queue {
+	hostname, _ := os.Hostname()
+	return &Queue{
+		store:    s,
+		workers:  max(workers, 1),
+		handlers: make(map[string]Handler),
+		hostname: hostname,
+	}
+}
+
+// Register задает обработчик для вида задач. Вызывается до Start.
+func (q *Queue) Register(kind string, handler Handler) {
+	if _, exists := q.handlers[kind]; exists {
+		panic("queue: handler already registered for " + kind)
+	}
+	q.handlers[kind] = handler
+	q.kinds = append(q.kinds, kind)
+}
+
+// Start запускает воркеры. Воркеры забирают только задачи зарегистрированных видов.
+func (q *Queue) Start(ctx context.Context) {
+	if len(q.kinds) == 0 {
+		slog.Info("Task queue has no registered handlers, workers not started")
+		return
+	}
+	ctx, q.cancel = context.WithCancel(ctx)
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx, fmt.Sprintf("%s/%d/%d", q.hostname, os.Getpid(), i))
+	}
+	slog.Info("Task queue started", slog.Int("workers", q.workers), slog.Any("kinds", q.kinds))
+}
+
+// Stop прекращает захват новых задач и ждет завершения выполняемых.
+func (q *Queue) Stop() {
+	if q.cancel == nil {
+		return
+	}
+	q.cancel()
+	q.wg.Wait()
+}
+
+func (q *Queue) work(ctx context.Context, workerID string) {
+	defer q.wg.Done()
+	for {
+		task, err := q.store.ClaimTask(ctx, q.kinds, workerID, lease)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("Failed to claim task", slog.String("worker", workerID), logging.Err(err))
+		}
+		if task == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+		q.process(ctx, *task)
+	}
+}
+
+// process выполняет задачу и записывает результат. Остановка очереди не
+// прерывает задачу: она выполняется до конца или до истечения lease.
+func (q *Queue) process(ctx context.Context, task models.Task) {
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lease)
+	defer cancel()
+
+	err := q.run(runCtx, task)
+	if err == nil {
+		if err := q.store.CompleteTask(runCtx, task.ID); err != nil {
+			slog.Error("Task succeeded but was not marked as completed", taskAttrs(task), logging.Err(err))
+		}
+		return
+	}
+
+	var retryAt *time.Time
+	var permanent permanentError
+	if !errors.As(err, &permanent) && task.Attempts < task.MaxAttempts {
+		next := time.Now().Add(backoff(task.Attempts))
+		retryAt = &next
+	}
+	if retryAt == nil {
+		slog.Error("Task is dead", taskAttrs(task), slog.Int("attempts", task.Attempts), logging.Err(err))
+	} else {
+		slog.Warn("Task failed", taskAttrs(task),
+			slog.Int("attempts", task.Attempts), slog.Int("max_attempts", task.MaxAttempts), logging.Err(err))
+	}
+	if err := q.store.FailTask(runCtx, task.ID, err.Error(), retryAt); err != nil {
+		slog.Error("Failed to record task failure", taskAttrs(task), logging.Err(err))
+	}
+}
+
+func (q *Queue) run(ctx context.Context, task models.Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if task.Attempts > task.MaxAttempts {
+		// Задачу несколько раз бросали упавшие воркеры
+		return Permanent(errors.New("attempts exhausted"))
+	}
+	return q.handlers[task.Kind](ctx, task)
+}
+
+// taskAttrs - атрибуты задачи для журнала
+func taskAttrs(task models.Task) slog.Attr {
+	return slog.Group("task", slog.Int64("id", task.ID), slog.String("kind", task.Kind))
+}
+
+// backoff возвращает задержку перед повтором: 10с, 20с, 40с... но не больше часа.
+func backoff(attempts int) time.Duration {
+	if attempts > 20 {
+		return maxBackoff
+	}
+	return min(minBackoff<<(attempts-1), maxBackoff)
+}
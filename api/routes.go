@@ -0,0 +1,144 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/handlers"
+	"masterdom/api/jwtkeys"
+	"masterdom/api/middleware"
+	"masterdom/api/models"
+	"masterdom/api/store"
+)
+
+// registerRoutes регистрирует маршруты API. Каждый маршрут должен быть описан
+// в документе OpenAPI (пакет openapi), это проверяет routes_test.go.
+func registerRoutes(r *gin.Engine, appHandlers *handlers.Handler, appStore store.Store, jwtKeys *jwtkeys.KeySet) {
+	// Проверки для оркестратора: /livez - процесс жив, /readyz - готов принимать запросы
+	r.GET("/livez", appHandlers.Livez)
+	r.GET("/readyz", appHandlers.Readyz)
+
+	// Открытые ключи для проверки токенов другими сервисами
+	r.GET("/.well-known/jwks.json", appHandlers.GetJWKS)
+
+	api := r.Group("/api")
+	api.Use(middleware.MaybeAuthMiddleware(appStore, jwtKeys))
+	{
+		api.GET("/health", appHandlers.Readyz)
+		api.GET("/openapi.json", appHandlers.GetOpenAPI)
+
+		auth := api.Group("/auth")
+		{
+			auth.POST("/register", appHandlers.Register)
+			auth.POST("/login", appHandlers.Login)
+			auth.GET("/oidc/providers", appHandlers.GetOIDCProviders)
+			auth.GET("/oidc/:provider/login", appHandlers.OIDCLogin)
+			auth.GET("/oidc/:provider/callback", appHandlers.OIDCCallback)
+		}
+
+		// Каталог открыт, но ключ API без права offers:read его не читает
+		api.GET("/offers", middleware.RequireScope(models.ScopeOffersRead), appHandlers.GetOffers)
+		api.GET("/categories", appHandlers.GetAllCategories)
+		api.GET("/categories/tree", appHandlers.GetCategoryTree)
+		api.GET("/categories/:id/attributes", appHandlers.GetCategoryAttributes)
+		api.GET("/users/:id/avatar", appHandlers.GetUserAvatar)
+
+		protected := api.Group("/")
+		protected.Use(middleware.AuthMiddleware(appStore, jwtKeys))
+		{
+			// Запросы с ключом API ограничены правами ключа; для токенов сессии RequireScope ничего не проверяет
+			protected.GET("/profile", middleware.RequireScope(models.ScopeProfileRead), appHandlers.GetMyProfile)
+			protected.PATCH("/profile", middleware.RequireScope(models.ScopeProfileWrite), appHandlers.UpdateMyProfile)
+			protected.PUT("/profile/avatar", middleware.RequireScope(models.ScopeProfileWrite), appHandlers.UploadMyAvatar)
+			protected.POST("/offers", middleware.RequireScope(models.ScopeOffersWrite), appHandlers.CreateOffer)
+			protected.POST("/offers/:id/respond", middleware.RequireScope(models.ScopeApplicationsWrite), appHandlers.RespondToOffer)
+			protected.POST("/offers/:id/renew", middleware.RequireScope(models.ScopeOffersWrite), appHandlers.RenewOffer)
+			protected.GET("/offers/:id/applications", middleware.RequireScope(models.ScopeApplicationsRead), appHandlers.GetOfferApplications)
+			protected.PATCH("/offers/:id/applications/:applicationId", middleware.RequireScope(models.ScopeApplicationsWrite), appHandlers.UpdateApplicationStatus)
+			// Работы возникают из принятых откликов, поэтому доступ к ним дают права на отклики
+			protected.GET("/jobs", middleware.RequireScope(models.ScopeApplicationsRead), appHandlers.GetMyJobs)
+			protected.PATCH("/jobs/:id", middleware.RequireScope(models.ScopeApplicationsWrite), appHandlers.UpdateJobStatus)
+			protected.POST("/jobs/:id/review", middleware.RequireScope(models.ScopeApplicationsWrite), appHandlers.CreateJobReview)
+
+			// API key routes: ключами управляют только из сессии, а не с помощью другого ключа
+			apiKeys := protected.Group("/api-keys")
+			apiKeys.Use(middleware.RequireSession())
+			{
+				apiKeys.GET("", appHandlers.GetMyAPIKeys)
+				apiKeys.POST("", appHandlers.CreateAPIKey)
+				apiKeys.DELETE("/:id", appHandlers.RevokeMyAPIKey)
+			}
+
+			// Notification routes
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", middleware.RequireScope(models.ScopeNotificationsRead), appHandlers.GetNotifications)
+				notifications.POST("/read-all", middleware.RequireScope(models.ScopeNotificationsWrite), appHandlers.MarkAllNotificationsRead)
+				notifications.POST("/:id/read", middleware.RequireScope(models.ScopeNotificationsWrite), appHandlers.MarkNotificationRead)
+				notifications.GET("/preferences", middleware.RequireScope(models.ScopeNotificationsRead), appHandlers.GetNotificationPreferences)
+				notifications.PUT("/preferences", middleware.RequireScope(models.ScopeNotificationsWrite), appHandlers.UpdateNotificationPreferences)
+			}
+
+			// Chat routes
+			chatGroup := protected.Group("/chats")
+			{
+				chatGroup.GET("", middleware.RequireScope(models.ScopeChatsRead), appHandlers.GetConversations)
+				chatGroup.POST("/initiate", middleware.RequireScope(models.ScopeChatsWrite), appHandlers.InitiateChat)
+				chatGroup.GET("/:id", middleware.RequireScope(models.ScopeChatsRead), appHandlers.GetChatDetails)
+				chatGroup.GET("/:id/messages", middleware.RequireScope(models.ScopeChatsRead), appHandlers.GetMessages)
+				chatGroup.POST("/:id/messages", middleware.RequireScope(models.ScopeChatsWrite), appHandlers.PostMessage)
+			}
+
+			// Admin routes
+			admin := protected.Group("/admin")
+			admin.Use(middleware.AdminAuthMiddleware(), middleware.RequireScope(models.ScopeAdmin), middleware.AuditActorMiddleware())
+			{
+				admin.GET("/users", appHandlers.GetUsers)
+				admin.GET("/users/:id", appHandlers.GetUserByID)
+				admin.PATCH("/users/:id", appHandlers.UpdateUser)
+				admin.DELETE("/users/:id", appHandlers.DeleteUser)
+				admin.POST("/users/:id/suspend", appHandlers.SuspendUser)
+				admin.POST("/users/:id/ban", appHandlers.BanUser)
+				admin.POST("/users/:id/lift-sanction", appHandlers.LiftUserSanction)
+				admin.GET("/users/:id/sanctions", appHandlers.GetUserSanctions)
+				admin.POST("/users/:id/restore", appHandlers.RestoreUser)
+
+				admin.GET("/offers", appHandlers.GetAdminAllOffers)
+				admin.PATCH("/offers/:id", appHandlers.UpdateOfferStatus)
+				admin.DELETE("/offers/:id", appHandlers.DeleteOffer)
+				admin.POST("/offers/:id/restore", appHandlers.RestoreOffer)
+
+				admin.GET("/categories", appHandlers.GetAdminCategories)
+				admin.POST("/categories", appHandlers.CreateCategory)
+				admin.PATCH("/categories/:id", appHandlers.UpdateCategory)
+				admin.DELETE("/categories/:id", appHandlers.DeleteCategory)
+				admin.POST("/categories/:id/restore", appHandlers.RestoreCategory)
+				admin.POST("/categories/:id/attributes", appHandlers.CreateCategoryAttribute)
+				admin.PATCH("/categories/:id/attributes/:attributeId", appHandlers.UpdateCategoryAttribute)
+				admin.DELETE("/categories/:id/attributes/:attributeId", appHandlers.DeleteCategoryAttribute)
+
+				admin.GET("/trash", appHandlers.GetTrash)
+
+				admin.GET("/audit", appHandlers.GetAuditLog)
+				admin.GET("/audit/export", appHandlers.ExportAuditLog)
+
+				admin.GET("/tasks", appHandlers.GetTasks)
+				admin.GET("/tasks/:id", appHandlers.GetTask)
+				admin.POST("/tasks/:id/retry", appHandlers.RetryTask)
+
+				admin.GET("/webhooks", appHandlers.GetWebhooks)
+				admin.POST("/webhooks", appHandlers.CreateWebhook)
+				admin.GET("/webhooks/:id", appHandlers.GetWebhook)
+				admin.PATCH("/webhooks/:id", appHandlers.UpdateWebhook)
+				admin.DELETE("/webhooks/:id", appHandlers.DeleteWebhook)
+				admin.POST("/webhooks/:id/rotate-secret", appHandlers.RotateWebhookSecret)
+				admin.POST("/webhooks/:id/test", appHandlers.TestWebhook)
+				admin.GET("/webhooks/:id/deliveries", appHandlers.GetWebhookDeliveries)
+
+				admin.GET("/api-keys", appHandlers.GetAllAPIKeys)
+				admin.DELETE("/api-keys/:id", appHandlers.RevokeAPIKey)
+
+				admin.GET("/stats", appHandlers.GetAdminStats)
+			}
+		}
+	}
+}
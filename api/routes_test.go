@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"masterdom/api/handlers"
+	"masterdom/api/openapi"
+)
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	registerRoutes(r, &handlers.Handler{}, nil, nil)
+	return r
+}
+
+// Каждый маршрут сервера описан в документе OpenAPI, и каждая операция
+// документа обслуживается сервером
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	served := make(map[string]bool)
+	for _, route := range newTestRouter().Routes() {
+		served[openapi.RouteKey(route.Method, route.Path)] = true
+	}
+
+	documented := make(map[string]bool)
+	for _, key := range openapi.Build().Operations() {
+		documented[key] = true
+		if !served[key] {
+			t.Errorf("operation %s is documented but not served", key)
+		}
+	}
+	for key := range served {
+		if !documented[key] {
+			t.Errorf("route %s is served but not documented", key)
+		}
+	}
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	newTestRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to decode document: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("openapi = %q, want %q", doc.OpenAPI, openapi.Version)
+	}
+	if got, want := len(doc.Operations()), len(openapi.Build().Operations()); got != want {
+		t.Errorf("document has %d operations, want %d", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"masterdom/api/logging"
+)
+
+// Task - периодическая задача. Ошибка записывается в лог, следующий запуск
+// выполняется по расписанию.
+type Task func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	task     Task
+}
+
+// Scheduler выполняет зарегистрированные задачи с заданным интервалом внутри
+// процесса API. Каждая задача запускается в своей горутине, поэтому запуски
+// одной задачи не пересекаются, а медленная задача не задерживает остальные.
+type Scheduler struct {
+	mu      sync.Mutex
+	jobs    []job
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register добавляет задачу. Задачи нужно регистрировать до вызова Start.
+func (s *Scheduler) Register(name string, interval time.Duration, task Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		panic("scheduler: Register called after Start")
+	}
+	s.jobs = append(s.jobs, job{name: name, interval: interval, task: task})
+}
+
+// Start запускает все задачи. Первый запуск выполняется сразу, затем раз в interval.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.run(ctx, j)
+	}
+	slog.Info("Scheduler started", slog.Int("tasks", len(s.jobs)))
+}
+
+// Stop отменяет контекст задач и ждет завершения текущих запусков.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.execute(ctx, j)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) execute(ctx context.Context, j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Scheduled task panicked", slog.String("task", j.name), slog.Any("panic", r))
+		}
+	}()
+
+	start := time.Now()
+	if err := j.task(ctx); err != nil {
+		slog.Error("Scheduled task failed", slog.String("task", j.name),
+			slog.Duration("duration", time.Since(start).Round(time.Millisecond)), logging.Err(err))
+	}
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/models"
+)
+
+const apiKeyColumns = "id, user_id, name, prefix, scopes, expires_at, last_used_at, last_used_ip, revoked_at, created_at"
+
+// --- API Key Implementations ---
+
+func (s *PostgresStore) CreateAPIKey(ctx context.Context, userID string, payload models.CreateAPIKeyPayload, prefix, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := s.dbpool.QueryRow(ctx, `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+apiKeyColumns,
+		userID, payload.Name, prefix, keyHash, payload.Scopes, payload.ExpiresAt).Scan(
+		&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Scopes, &key.ExpiresAt,
+		&key.LastUsedAt, &key.LastUsedIP, &key.RevokedAt, &key.CreatedAt)
+	if err != nil {
+		return nil, uniqueViolation(fmt.Errorf("failed to create api key: %w", err), ErrAPIKeyPrefixTaken)
+	}
+	return &key, nil
+}
+
+// GetAPIKeys returns the keys of one user, or of all users when userID is nil.
+func (s *PostgresStore) GetAPIKeys(ctx context.Context, userID *string) ([]models.APIKey, error) {
+	rows, err := s.dbpool.Query(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE $1::uuid IS NULL OR user_id = $1
+		ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]models.APIKey, 0)
+	for rows.Next() {
+		var key models.APIKey
+		if err := rows.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Scopes, &key.ExpiresAt,
+			&key.LastUsedAt, &key.LastUsedIP, &key.RevokedAt, &key.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey revokes the key. With a non-nil ownerID only that user's key matches.
+func (s *PostgresStore) RevokeAPIKey(ctx context.Context, keyID string, ownerID *string) error {
+	return s.withAudit(ctx, "api_key.revoke", auditTargetAPIKey, &keyID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE api_keys SET revoked_at = COALESCE(revoked_at, NOW())
+			WHERE id = $1 AND ($2::uuid IS NULL OR user_id = $2)`, keyID, ownerID)
+		if err != nil {
+			return notFound(fmt.Errorf("failed to revoke api key: %w", err), ErrAPIKeyNotFound)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrAPIKeyNotFound
+		}
+		return nil
+	})
+}
+
+// GetAPIKeyCredentials looks up a key by its prefix for authentication.
+func (s *PostgresStore) GetAPIKeyCredentials(ctx context.Context, prefix string) (*models.APIKeyCredentials, error) {
+	var creds models.APIKeyCredentials
+	err := s.dbpool.QueryRow(ctx, `
+		SELECT k.id, k.user_id, k.key_hash, k.scopes, k.expires_at, k.revoked_at, u.role = 'admin'
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id AND u.deleted_at IS NULL
+		WHERE k.prefix = $1`, prefix).Scan(
+		&creds.ID, &creds.UserID, &creds.KeyHash, &creds.Scopes, &creds.ExpiresAt, &creds.RevokedAt, &creds.IsAdmin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return &creds, nil
+}
+
+// TouchAPIKey records the key usage. Updates are throttled to once a minute
+// so busy integrations do not write on every request.
+func (s *PostgresStore) TouchAPIKey(ctx context.Context, keyID, ip string) error {
+	_, err := s.dbpool.Exec(ctx, `
+		UPDATE api_keys SET last_used_at = NOW(), last_used_ip = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute' OR last_used_ip IS DISTINCT FROM $2)`,
+		keyID, ip)
+	if err != nil {
+		return fmt.Errorf("failed to update api key usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/models"
+)
+
+// --- Category Attribute Implementations ---
+
+// GetCategoryAttributes returns the attribute schema for offers in a category:
+// its own attributes plus those inherited from ancestors. When a key is
+// defined on several levels, the definition closest to the category wins.
+// A missing or deleted category is reported as ErrCategoryNotFound.
+func (s *PostgresStore) GetCategoryAttributes(ctx context.Context, categoryID int) ([]models.CategoryAttribute, error) {
+	var exists bool
+	err := s.dbpool.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM service_categories WHERE id = $1 AND deleted_at IS NULL)", categoryID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check category: %w", err)
+	}
+	if !exists {
+		return nil, ErrCategoryNotFound
+	}
+
+	rows, err := s.dbpool.Query(ctx, `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth
+			FROM service_categories
+			WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id, c.parent_id, a.depth + 1
+			FROM service_categories c
+			JOIN ancestors a ON c.id = a.parent_id
+			WHERE c.deleted_at IS NULL
+		)
+		SELECT id, category_id, key, label, type, required, options, sort_order
+		FROM (
+			SELECT DISTINCT ON (ca.key) ca.*
+			FROM category_attributes ca
+			JOIN ancestors a ON ca.category_id = a.id
+			ORDER BY ca.key, a.depth
+		) attrs
+		ORDER BY sort_order, key
+	`, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch category attributes: %w", err)
+	}
+	defer rows.Close()
+
+	attributes := make([]models.CategoryAttribute, 0)
+	for rows.Next() {
+		var a models.CategoryAttribute
+		if err := rows.Scan(&a.ID, &a.CategoryID, &a.Key, &a.Label, &a.Type, &a.Required, &a.Options, &a.SortOrder); err != nil {
+			return nil, fmt.Errorf("failed to scan category attribute: %w", err)
+		}
+		attributes = append(attributes, a)
+	}
+	return attributes, nil
+}
+
+func (s *PostgresStore) CreateCategoryAttribute(ctx context.Context, categoryID int, payload models.CategoryAttributePayload) (int, error) {
+	var attributeID int
+	var targetID string
+	err := s.withAudit(ctx, "category_attribute.create", auditTargetAttribute, &targetID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO category_attributes (category_id, key, label, type, required, options, sort_order)
+			SELECT id, $2, $3, $4, $5, $6, $7 FROM service_categories WHERE id = $1 AND deleted_at IS NULL
+			RETURNING id`,
+			categoryID, payload.Key, payload.Label, payload.Type, payload.Required, payload.Options, payload.SortOrder).Scan(&attributeID)
+		if err != nil {
+			err = notFound(fmt.Errorf("failed to create category attribute: %w", err), ErrCategoryNotFound)
+			return uniqueViolation(err, ErrAttributeKeyTaken)
+		}
+		targetID = strconv.Itoa(attributeID)
+		return nil
+	})
+	return attributeID, err
+}
+
+func (s *PostgresStore) UpdateCategoryAttribute(ctx context.Context, categoryID, attributeID int, payload models.CategoryAttributePayload) error {
+	targetID := strconv.Itoa(attributeID)
+	return s.withAudit(ctx, "category_attribute.update", auditTargetAttribute, &targetID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE category_attributes
+			SET key = $3, label = $4, type = $5, required = $6, options = $7, sort_order = $8
+			WHERE id = $1 AND category_id = $2`,
+			attributeID, categoryID, payload.Key, payload.Label, payload.Type, payload.Required, payload.Options, payload.SortOrder)
+		if err != nil {
+			return uniqueViolation(fmt.Errorf("failed to update category attribute: %w", err), ErrAttributeKeyTaken)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrAttributeNotFound
+		}
+		return nil
+	})
+}
+
+func (s *PostgresStore) DeleteCategoryAttribute(ctx context.Context, categoryID, attributeID int) error {
+	targetID := strconv.Itoa(attributeID)
+	return s.withAudit(ctx, "category_attribute.delete", auditTargetAttribute, &targetID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "DELETE FROM category_attributes WHERE id = $1 AND category_id = $2", attributeID, categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to delete category attribute: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrAttributeNotFound
+		}
+		return nil
+	})
+}
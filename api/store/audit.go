@@ -0,0 +1,207 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/apperr"
+	"masterdom/api/audit"
+	"masterdom/api/models"
+)
+
+// Audit target types
+const (
+	auditTargetUser      = "user"
+	auditTargetOffer     = "offer"
+	auditTargetCategory  = "category"
+	auditTargetAttribute = "category_attribute"
+	auditTargetTask      = "task"
+	auditTargetWebhook   = "webhook"
+	auditTargetAPIKey    = "api_key"
+)
+
+// auditSnapshotQueries return the current state of an audited target as a
+// flat JSON object. Secrets and bookkeeping columns are left out.
+var auditSnapshotQueries = map[string]string{
+	auditTargetUser: `
+		SELECT (to_jsonb(u) - 'password_hash')
+			|| COALESCE(to_jsonb(d) - 'user_id' - 'created_at' - 'updated_at', '{}'::jsonb)
+		FROM users u
+		LEFT JOIN user_details d ON d.user_id = u.id
+		WHERE u.id = $1::uuid`,
+	auditTargetOffer: "SELECT to_jsonb(o) FROM offers o WHERE o.id = $1::uuid",
+	auditTargetCategory: `
+		SELECT to_jsonb(c) || jsonb_build_object('translations', (
+			SELECT jsonb_object_agg(t.locale, jsonb_build_object('name', t.name, 'description', t.description))
+			FROM service_category_translations t WHERE t.category_id = c.id
+		))
+		FROM service_categories c
+		WHERE c.id = $1::int`,
+	auditTargetAttribute: "SELECT to_jsonb(a) FROM category_attributes a WHERE a.id = $1::int",
+	auditTargetTask:      "SELECT to_jsonb(t) - 'payload' FROM tasks t WHERE t.id = $1::bigint",
+	auditTargetWebhook:   "SELECT to_jsonb(w) - 'secret' FROM webhooks w WHERE w.id = $1::int",
+	auditTargetAPIKey:    "SELECT to_jsonb(k) - 'key_hash' - 'last_used_at' - 'last_used_ip' FROM api_keys k WHERE k.id = $1::uuid",
+}
+
+// auditNotFound is returned when an existing target is expected but missing.
+var auditNotFound = map[string]*apperr.Error{
+	auditTargetUser:      ErrUserNotFound,
+	auditTargetOffer:     ErrOfferNotFound,
+	auditTargetCategory:  ErrCategoryNotFound,
+	auditTargetAttribute: ErrAttributeNotFound,
+	auditTargetTask:      ErrTaskNotFound,
+	auditTargetWebhook:   ErrWebhookNotFound,
+	auditTargetAPIKey:    ErrAPIKeyNotFound,
+}
+
+// auditIgnoredKeys change on almost every write and would only add noise to the diff.
+var auditIgnoredKeys = map[string]bool{"updated_at": true}
+
+// withAudit runs fn in a transaction. When ctx carries an audit actor, the
+// target is snapshotted before and after fn and the changed fields are written
+// to audit_log in the same transaction. fn may set *targetID for creations;
+// any other target must exist, otherwise its NotFound error is returned.
+func (s *PostgresStore) withAudit(ctx context.Context, action, targetType string, targetID *string, fn func(tx pgx.Tx) error) error {
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	actor, audited := audit.ActorFromContext(ctx)
+
+	var before map[string]interface{}
+	if audited && *targetID != "" {
+		if before, err = auditSnapshot(ctx, tx, targetType, *targetID); err != nil {
+			return err
+		}
+		if before == nil {
+			return auditNotFound[targetType]
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if audited {
+		after, err := auditSnapshot(ctx, tx, targetType, *targetID)
+		if err != nil {
+			return err
+		}
+		beforeDiff, afterDiff := auditDiff(before, after)
+		_, err = tx.Exec(ctx, `
+			INSERT INTO audit_log (actor_id, action, target_type, target_id, before, after, ip, user_agent)
+			VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5, $6, $7, $8)`,
+			actor.UserID, action, targetType, *targetID, beforeDiff, afterDiff, actor.IP, actor.UserAgent)
+		if err != nil {
+			return fmt.Errorf("failed to write audit log: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func auditSnapshot(ctx context.Context, tx pgx.Tx, targetType, targetID string) (map[string]interface{}, error) {
+	var snapshot map[string]interface{}
+	err := tx.QueryRow(ctx, auditSnapshotQueries[targetType], targetID).Scan(&snapshot)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		// A malformed ID (22P02) cannot match any row
+		return nil, notFound(fmt.Errorf("failed to snapshot %s for audit: %w", targetType, err), auditNotFound[targetType])
+	}
+	return snapshot, nil
+}
+
+// auditDiff keeps only the keys whose values differ between the snapshots.
+// A missing snapshot (creation or missing row) is recorded as NULL.
+func auditDiff(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	if before == nil || after == nil {
+		return before, after
+	}
+	beforeDiff := map[string]interface{}{}
+	afterDiff := map[string]interface{}{}
+	for key, value := range after {
+		if auditIgnoredKeys[key] {
+			continue
+		}
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			beforeDiff[key] = before[key]
+			afterDiff[key] = value
+		}
+	}
+	return beforeDiff, afterDiff
+}
+
+// auditFilterQuery builds the shared FROM/WHERE part of the audit log queries.
+func auditFilterQuery(filter models.AuditFilter) (string, []interface{}) {
+	query := " FROM audit_log a LEFT JOIN users u ON a.actor_id = u.id"
+	var clauses []string
+	var args []interface{}
+
+	add := func(clause string, value interface{}) {
+		args = append(args, value)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if filter.ActorID != "" {
+		add("a.actor_id = $%d::uuid", filter.ActorID)
+	}
+	if filter.Action != "" {
+		add("a.action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		add("a.target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		add("a.target_id = $%d", filter.TargetID)
+	}
+	if filter.From != nil {
+		add("a.created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("a.created_at < $%d", *filter.To)
+	}
+
+	if len(clauses) > 0 {
+		query += " WHERE " + strings.Join(clauses, " AND ")
+	}
+	return query, args
+}
+
+func (s *PostgresStore) GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	where, args := auditFilterQuery(filter)
+	query := `SELECT a.id, a.actor_id, u.email, a.action, a.target_type, a.target_id,
+				a.before, a.after, a.ip, a.user_agent, a.created_at` + where + " ORDER BY a.created_at DESC, a.id DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	rows, err := s.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var e models.AuditEntry
+		var before, after []byte
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.ActorEmail, &e.Action, &e.TargetType, &e.TargetID,
+			&before, &after, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		e.Before = json.RawMessage(before)
+		e.After = json.RawMessage(after)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"masterdom/api/models"
+)
+
+// --- Avatar Implementations ---
+
+// SetUserAvatar replaces the avatar of the user and queues a task to build
+// its thumbnail; until then the avatar has no thumbnail.
+func (s *PostgresStore) SetUserAvatar(ctx context.Context, userID, contentType string, image []byte) error {
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO user_avatars (user_id, content_type, image)
+		SELECT id, $2, $3 FROM users WHERE id = $1 AND deleted_at IS NULL
+		ON CONFLICT (user_id) DO UPDATE
+		SET content_type = EXCLUDED.content_type, image = EXCLUDED.image, thumbnail = NULL, updated_at = NOW()`,
+		userID, contentType, image)
+	if err != nil {
+		return notFound(fmt.Errorf("failed to save avatar: %w", err), ErrUserNotFound)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	_, err = enqueueTask(ctx, tx, models.NewTask{Kind: models.TaskKindAvatarThumbnail, Payload: models.UserTask{UserID: userID}})
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *PostgresStore) GetUserAvatar(ctx context.Context, userID string) (*models.Avatar, error) {
+	var a models.Avatar
+	err := s.dbpool.QueryRow(ctx, `
+		SELECT a.content_type, a.image, a.thumbnail, a.updated_at
+		FROM user_avatars a
+		JOIN users u ON u.id = a.user_id AND u.deleted_at IS NULL
+		WHERE a.user_id = $1`, userID).Scan(&a.ContentType, &a.Image, &a.Thumbnail, &a.UpdatedAt)
+	if err != nil {
+		return nil, notFound(fmt.Errorf("failed to get avatar: %w", err), ErrAvatarNotFound)
+	}
+	return &a, nil
+}
+
+// SetAvatarThumbnail stores the thumbnail of the avatar uploaded at updatedAt.
+// If the avatar has been replaced since, the thumbnail is dropped: the task
+// queued for the new avatar builds its own.
+func (s *PostgresStore) SetAvatarThumbnail(ctx context.Context, userID string, updatedAt time.Time, thumbnail []byte) error {
+	_, err := s.dbpool.Exec(ctx,
+		"UPDATE user_avatars SET thumbnail = $3 WHERE user_id = $1 AND updated_at = $2",
+		userID, updatedAt, thumbnail)
+	if err != nil {
+		return fmt.Errorf("failed to save avatar thumbnail: %w", err)
+	}
+	return nil
+}
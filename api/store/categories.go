@@ -0,0 +1,306 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/apperr"
+	"masterdom/api/models"
+	"masterdom/api/utils"
+)
+
+var (
+	// ErrCategoryNotFound is returned when the category itself does not exist.
+	ErrCategoryNotFound = apperr.NotFound("category_not_found", "Category not found")
+	// ErrCategoryNotEmpty is returned when deleting a category that still has
+	// subcategories or offers and no reassignment target was given.
+	ErrCategoryNotEmpty = apperr.Conflict("category_not_empty", "Category has subcategories or offers")
+	// ErrCategoryCycle is returned when a category would become its own ancestor.
+	ErrCategoryCycle = apperr.Validation("category_cycle", "Category cannot be moved under itself or its descendant")
+	// ErrRelatedCategoryNotFound is returned when a referenced parent or reassignment category does not exist.
+	ErrRelatedCategoryNotFound = apperr.Validation("related_category_not_found", "Parent or target category not found")
+	// ErrSlugTaken is returned when an explicitly requested category slug is already used.
+	ErrSlugTaken = apperr.Conflict("slug_taken", "Slug already in use")
+	// ErrCategoryNameTaken is returned when a live sibling already has the same name.
+	ErrCategoryNameTaken = apperr.Conflict("category_name_taken", "Category with this name already exists under the same parent")
+)
+
+// categoryDescendantsQuery selects the IDs of a live category and all its live
+// descendants. The root is matched by ID or slug via the $%[1]d placeholder.
+const categoryDescendantsQuery = `
+	WITH RECURSIVE category_tree AS (
+		SELECT id FROM service_categories
+		WHERE (id::text = $%[1]d OR slug = $%[1]d) AND deleted_at IS NULL
+		UNION ALL
+		SELECT c.id FROM service_categories c
+		JOIN category_tree t ON c.parent_id = t.id
+		WHERE c.deleted_at IS NULL
+	)
+	SELECT id FROM category_tree`
+
+// --- Category Implementations ---
+
+// GetAllCategories returns live categories with names and descriptions in the
+// requested locale, falling back to the default locale and then to the base columns.
+func (s *PostgresStore) GetAllCategories(ctx context.Context, locale string) ([]models.ServiceCategory, error) {
+	rows, err := s.dbpool.Query(ctx, `
+		SELECT c.id, c.parent_id, c.slug,
+			   COALESCE(t.name, d.name, c.name) AS name,
+			   COALESCE(t.description, d.description, c.description, '') AS description,
+			   c.sort_order, c.icon
+		FROM service_categories c
+		LEFT JOIN service_category_translations t ON t.category_id = c.id AND t.locale = $1
+		LEFT JOIN service_category_translations d ON d.category_id = c.id AND d.locale = $2
+		WHERE c.deleted_at IS NULL
+		ORDER BY c.sort_order, name`, locale, utils.DefaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := make([]models.ServiceCategory, 0)
+	for rows.Next() {
+		var cat models.ServiceCategory
+		if err := rows.Scan(&cat.ID, &cat.ParentID, &cat.Slug, &cat.Name, &cat.Description, &cat.SortOrder, &cat.Icon); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, cat)
+	}
+	return categories, nil
+}
+
+func (s *PostgresStore) CreateCategory(ctx context.Context, payload models.CategoryPayload) (int, error) {
+	var categoryID int
+	var targetID string
+	err := s.withAudit(ctx, "category.create", auditTargetCategory, &targetID, func(tx pgx.Tx) error {
+		if err := checkCategoryParent(ctx, tx, 0, payload.ParentID); err != nil {
+			return err
+		}
+
+		slug, err := categorySlug(ctx, tx, 0, payload.Name, payload.Slug)
+		if err != nil {
+			return err
+		}
+
+		sortOrder := 0
+		if payload.SortOrder != nil {
+			sortOrder = *payload.SortOrder
+		}
+
+		err = tx.QueryRow(ctx, `
+			INSERT INTO service_categories (name, description, parent_id, slug, sort_order, icon)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			payload.Name, payload.Description, payload.ParentID, slug, sortOrder, payload.Icon).Scan(&categoryID)
+		targetID = strconv.Itoa(categoryID)
+		if err != nil {
+			return uniqueViolation(err, ErrCategoryNameTaken)
+		}
+		return saveCategoryTranslations(ctx, tx, categoryID, payload.Translations)
+	})
+	return categoryID, err
+}
+
+// UpdateCategory applies a partial update: nil fields keep their current
+// values, and fields listed in payload.Clear are reset to NULL.
+func (s *PostgresStore) UpdateCategory(ctx context.Context, categoryID int, payload models.UpdateCategoryPayload) error {
+	targetID := strconv.Itoa(categoryID)
+	return s.withAudit(ctx, "category.update", auditTargetCategory, &targetID, func(tx pgx.Tx) error {
+		if err := checkCategoryParent(ctx, tx, categoryID, payload.ParentID); err != nil {
+			return err
+		}
+
+		var slug *string
+		if payload.Slug != nil {
+			var name string
+			if payload.Name != nil {
+				name = *payload.Name
+			} else if err := tx.QueryRow(ctx,
+				"SELECT name FROM service_categories WHERE id = $1 AND deleted_at IS NULL", categoryID).Scan(&name); err != nil {
+				return notFound(err, ErrCategoryNotFound)
+			}
+			generated, err := categorySlug(ctx, tx, categoryID, name, payload.Slug)
+			if err != nil {
+				return err
+			}
+			slug = &generated
+		}
+
+		tag, err := tx.Exec(ctx, `
+			UPDATE service_categories
+			SET name = COALESCE($1, name),
+				description = CASE WHEN $8 THEN NULL ELSE COALESCE($2, description) END,
+				parent_id = CASE WHEN $9 THEN NULL ELSE COALESCE($3, parent_id) END,
+				slug = COALESCE($4, slug), sort_order = COALESCE($5, sort_order),
+				icon = CASE WHEN $10 THEN NULL ELSE COALESCE($6, icon) END
+			WHERE id = $7 AND deleted_at IS NULL`,
+			payload.Name, payload.Description, payload.ParentID, slug, payload.SortOrder, payload.Icon, categoryID,
+			payload.Clears("description"), payload.Clears("parentId"), payload.Clears("icon"))
+		if err != nil {
+			return uniqueViolation(err, ErrCategoryNameTaken)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrCategoryNotFound
+		}
+		return saveCategoryTranslations(ctx, tx, categoryID, payload.Translations)
+	})
+}
+
+// DeleteCategory soft-deletes a category. Subcategories and offers must be
+// moved to reassignTo explicitly, otherwise ErrCategoryNotEmpty is returned.
+func (s *PostgresStore) DeleteCategory(ctx context.Context, categoryID int, reassignTo *int) error {
+	targetID := strconv.Itoa(categoryID)
+	return s.withAudit(ctx, "category.delete", auditTargetCategory, &targetID, func(tx pgx.Tx) error {
+		var children, offers int
+		err := tx.QueryRow(ctx, `
+			SELECT
+				(SELECT COUNT(*) FROM service_categories WHERE parent_id = $1 AND deleted_at IS NULL),
+				(SELECT COUNT(*) FROM offers WHERE category_id = $1 AND deleted_at IS NULL)`,
+			categoryID).Scan(&children, &offers)
+		if err != nil {
+			return fmt.Errorf("failed to check category usage: %w", err)
+		}
+
+		if children+offers > 0 {
+			if reassignTo == nil {
+				return ErrCategoryNotEmpty
+			}
+			// The target must not be inside the subtree being removed
+			if err := checkCategoryParent(ctx, tx, categoryID, reassignTo); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, "UPDATE service_categories SET parent_id = $1 WHERE parent_id = $2", *reassignTo, categoryID); err != nil {
+				return fmt.Errorf("failed to reassign subcategories: %w", err)
+			}
+			if _, err := tx.Exec(ctx, "UPDATE offers SET category_id = $1 WHERE category_id = $2", *reassignTo, categoryID); err != nil {
+				return fmt.Errorf("failed to reassign offers: %w", err)
+			}
+		}
+
+		tag, err := tx.Exec(ctx, "UPDATE service_categories SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", categoryID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrCategoryNotFound
+		}
+		return nil
+	})
+}
+
+// checkCategoryParent verifies that parentID refers to a live category that is
+// neither categoryID itself nor one of its descendants.
+func checkCategoryParent(ctx context.Context, tx pgx.Tx, categoryID int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == categoryID {
+		return ErrCategoryCycle
+	}
+
+	var exists, isDescendant bool
+	err := tx.QueryRow(ctx, fmt.Sprintf(`
+		SELECT
+			EXISTS(SELECT 1 FROM service_categories WHERE id = $2 AND deleted_at IS NULL),
+			$2 IN (%s)`, fmt.Sprintf(categoryDescendantsQuery, 1)),
+		strconv.Itoa(categoryID), *parentID).Scan(&exists, &isDescendant)
+	if err != nil {
+		return fmt.Errorf("failed to check parent category: %w", err)
+	}
+	if !exists {
+		return ErrRelatedCategoryNotFound
+	}
+	if isDescendant {
+		return ErrCategoryCycle
+	}
+	return nil
+}
+
+// categorySlug returns the requested slug or derives a unique one from the
+// name, appending a numeric suffix on collisions with other categories.
+func categorySlug(ctx context.Context, tx pgx.Tx, categoryID int, name string, requested *string) (string, error) {
+	base := utils.Slugify(name)
+	if requested != nil && *requested != "" {
+		base = *requested
+	}
+	if base == "" {
+		base = "category"
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		var taken bool
+		err := tx.QueryRow(ctx,
+			"SELECT EXISTS(SELECT 1 FROM service_categories WHERE slug = $1 AND id <> $2 AND deleted_at IS NULL)",
+			slug, categoryID).Scan(&taken)
+		if err != nil {
+			return "", fmt.Errorf("failed to check category slug: %w", err)
+		}
+		if !taken {
+			return slug, nil
+		}
+		// An explicitly requested slug must be unique as given
+		if requested != nil && *requested != "" {
+			return "", ErrSlugTaken
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// saveCategoryTranslations replaces the category translations with the given
+// set. A nil map keeps other translations but refreshes the default-locale one
+// from the base columns, which would otherwise shadow edits of name and description.
+func saveCategoryTranslations(ctx context.Context, tx pgx.Tx, categoryID int, translations map[string]models.CategoryTranslation) error {
+	if translations == nil {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO service_category_translations (category_id, locale, name, description)
+			SELECT id, $2, name, description FROM service_categories WHERE id = $1
+			ON CONFLICT (category_id, locale) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description`,
+			categoryID, utils.DefaultLocale)
+		if err != nil {
+			return fmt.Errorf("failed to save %s category translation: %w", utils.DefaultLocale, err)
+		}
+		return nil
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM service_category_translations WHERE category_id = $1", categoryID); err != nil {
+		return fmt.Errorf("failed to clear category translations: %w", err)
+	}
+	for locale, t := range translations {
+		_, err := tx.Exec(ctx,
+			"INSERT INTO service_category_translations (category_id, locale, name, description) VALUES ($1, $2, $3, $4)",
+			categoryID, locale, t.Name, t.Description)
+		if err != nil {
+			return fmt.Errorf("failed to save %s category translation: %w", locale, err)
+		}
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetCategoryTranslations(ctx context.Context) (map[int]map[string]models.CategoryTranslation, error) {
+	rows, err := s.dbpool.Query(ctx, `
+		SELECT t.category_id, t.locale, t.name, t.description
+		FROM service_category_translations t
+		JOIN service_categories c ON c.id = t.category_id
+		WHERE c.deleted_at IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch category translations: %w", err)
+	}
+	defer rows.Close()
+
+	translations := make(map[int]map[string]models.CategoryTranslation)
+	for rows.Next() {
+		var categoryID int
+		var locale string
+		var t models.CategoryTranslation
+		if err := rows.Scan(&categoryID, &locale, &t.Name, &t.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan category translation: %w", err)
+		}
+		if translations[categoryID] == nil {
+			translations[categoryID] = make(map[string]models.CategoryTranslation)
+		}
+		translations[categoryID][locale] = t
+	}
+	return translations, nil
+}
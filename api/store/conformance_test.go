@@ -0,0 +1,1087 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"masterdom/api/audit"
+	"masterdom/api/db"
+	"masterdom/api/models"
+	"masterdom/api/utils"
+)
+
+// The conformance suite runs against every Store implementation. Tests share
+// one store and may run against a database with other data in it, so they
+// use unique names and check that results contain their own records instead
+// of comparing global counts.
+
+func TestMemoryStoreConformance(t *testing.T) {
+	runStoreConformance(t, func(t *testing.T) Store { return NewMemoryStore() })
+}
+
+// TestPostgresStoreConformance runs the suite against the database from
+// TEST_DATABASE_URL after applying the migrations.
+func TestPostgresStoreConformance(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	runStoreConformance(t, func(t *testing.T) Store {
+		ctx := context.Background()
+		pool, err := pgxpool.New(ctx, url)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		t.Cleanup(pool.Close)
+
+		migrator, err := db.NewMigrator(pool)
+		if err != nil {
+			t.Fatalf("failed to create migrator: %v", err)
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			t.Fatalf("failed to migrate: %v", err)
+		}
+		return NewPostgresStore(pool)
+	})
+}
+
+func runStoreConformance(t *testing.T, newStore func(t *testing.T) Store) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s Store)
+	}{
+		{"Users", testUsers},
+		{"Sanctions", testSanctions},
+		{"OffersAndApplications", testOffersAndApplications},
+		{"Jobs", testJobs},
+		{"Chat", testChat},
+		{"Categories", testCategories},
+		{"Notifications", testNotifications},
+		{"Outbox", testOutbox},
+		{"Avatars", testAvatars},
+		{"Tasks", testTasks},
+		{"Webhooks", testWebhooks},
+		{"APIKeys", testAPIKeys},
+		{"OIDC", testOIDC},
+		{"AuditLog", testAuditLog},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStore(t))
+		})
+	}
+}
+
+var uniqueCounter atomic.Int64
+
+// unique returns a name that does not clash with data from other tests or runs
+func unique(prefix string) string {
+	return fmt.Sprintf("%s-%d-%d", prefix, time.Now().UnixNano(), uniqueCounter.Add(1))
+}
+
+func mustCreateUser(t *testing.T, s Store, firstName string) (string, string) {
+	t.Helper()
+	email := unique("user") + "@example.com"
+	id, err := s.CreateUser(context.Background(), models.RegisterPayload{Email: email, FirstName: firstName}, "hash")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	return id, email
+}
+
+func mustCreateOffer(t *testing.T, s Store, authorID string, categoryID *int) (string, string) {
+	t.Helper()
+	title := unique("offer")
+	id, err := s.CreateOffer(context.Background(), authorID, models.CreateOfferPayload{
+		Title:      title,
+		OfferType:  "service_offer",
+		CategoryID: categoryID,
+	})
+	if err != nil {
+		t.Fatalf("CreateOffer: %v", err)
+	}
+	return id, title
+}
+
+func expectError(t *testing.T, op string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Errorf("%s: error = %v, want %v", op, err, want)
+	}
+}
+
+func hasNotification(t *testing.T, s Store, userID, eventType string) bool {
+	t.Helper()
+	list, err := s.GetNotifications(context.Background(), userID, models.NotificationFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetNotifications: %v", err)
+	}
+	for _, n := range list.Items {
+		if n.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTask reports whether a task of the kind with payload[key] equal to value is queued
+func hasTask(t *testing.T, s Store, kind, key string, value interface{}) bool {
+	t.Helper()
+	tasks, err := s.GetTasks(context.Background(), models.TaskFilter{Kind: kind, Limit: 1000})
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	want := fmt.Sprint(value)
+	for _, task := range tasks {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(task.Payload, &payload); err != nil {
+			t.Fatalf("task %d payload: %v", task.ID, err)
+		}
+		if fmt.Sprint(payload[key]) == want {
+			return true
+		}
+	}
+	return false
+}
+
+func testUsers(t *testing.T, s Store) {
+	ctx := context.Background()
+	userID, email := mustCreateUser(t, s, "Anna")
+
+	_, err := s.CreateUser(ctx, models.RegisterPayload{Email: email, FirstName: "Other"}, "hash")
+	expectError(t, "CreateUser with taken email", err, ErrEmailTaken)
+
+	user, err := s.GetUserByEmail(ctx, email)
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	if user.ID != userID || user.PasswordHash != "hash" || user.Status != models.UserStatusActive {
+		t.Errorf("GetUserByEmail = %+v", user)
+	}
+
+	isAdmin := true
+	bio := "Plumber"
+	if err := s.UpdateUserDetail(ctx, userID, models.UpdateUserPayload{IsAdmin: &isAdmin, Bio: &bio}); err != nil {
+		t.Fatalf("UpdateUserDetail: %v", err)
+	}
+	if admin, _ := s.IsUserAdmin(ctx, userID); !admin {
+		t.Error("IsUserAdmin = false after granting admin")
+	}
+	detail, err := s.GetUserDetailByID(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetUserDetailByID: %v", err)
+	}
+	if detail.Bio == nil || *detail.Bio != bio || detail.Role != "admin" || detail.Locale != "ru" {
+		t.Errorf("GetUserDetailByID = %+v", detail)
+	}
+
+	if err := s.DeleteUser(ctx, userID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	_, err = s.GetUserByEmail(ctx, email)
+	expectError(t, "GetUserByEmail after delete", err, ErrUserNotFound)
+	expectError(t, "second DeleteUser", s.DeleteUser(ctx, userID), ErrUserNotFound)
+	expectError(t, "UpdateUserDetail of a deleted user", s.UpdateUserDetail(ctx, userID, models.UpdateUserPayload{Bio: &bio}), ErrUserNotFound)
+	trash, err := s.GetTrash(ctx)
+	if err != nil {
+		t.Fatalf("GetTrash: %v", err)
+	}
+	found := false
+	for _, u := range trash.Users {
+		found = found || u.ID == userID
+	}
+	if !found {
+		t.Error("deleted user is not in the trash")
+	}
+
+	if err := s.RestoreUser(ctx, userID); err != nil {
+		t.Fatalf("RestoreUser: %v", err)
+	}
+	if _, err := s.GetUserByEmail(ctx, email); err != nil {
+		t.Errorf("GetUserByEmail after restore: %v", err)
+	}
+	expectError(t, "RestoreUser of a live user", s.RestoreUser(ctx, userID), ErrDeletedRecordNotFound)
+}
+
+func testSanctions(t *testing.T, s Store) {
+	ctx := context.Background()
+	adminID, _ := mustCreateUser(t, s, "Admin")
+	userID, _ := mustCreateUser(t, s, "Boris")
+
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	if err := s.SuspendUser(ctx, userID, adminID, models.SuspendUserPayload{Reason: "spam", Until: until}); err != nil {
+		t.Fatalf("SuspendUser: %v", err)
+	}
+	status, err := s.GetAccountStatus(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetAccountStatus: %v", err)
+	}
+	if status.Status != models.UserStatusSuspended || status.SuspendedUntil == nil || !status.SuspendedUntil.Equal(until) ||
+		status.Reason == nil || *status.Reason != "spam" {
+		t.Errorf("status after suspend = %+v", status)
+	}
+
+	if err := s.LiftUserSanction(ctx, userID, adminID, models.LiftSanctionPayload{}); err != nil {
+		t.Fatalf("LiftUserSanction: %v", err)
+	}
+	status, err = s.GetAccountStatus(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetAccountStatus: %v", err)
+	}
+	if status.Status != models.UserStatusActive || status.SuspendedUntil != nil || status.Reason != nil {
+		t.Errorf("status after lift = %+v", status)
+	}
+
+	sanctions, err := s.GetUserSanctions(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetUserSanctions: %v", err)
+	}
+	if len(sanctions) != 2 || sanctions[0].Action != "lift" || sanctions[1].Action != "suspend" {
+		t.Errorf("GetUserSanctions = %+v, want lift then suspend", sanctions)
+	}
+
+	err = s.BanUser(ctx, uuid.NewString(), adminID, models.BanUserPayload{Reason: "fraud"})
+	expectError(t, "BanUser of unknown user", err, ErrUserNotFound)
+	_, err = s.GetAccountStatus(ctx, uuid.NewString())
+	expectError(t, "GetAccountStatus of unknown user", err, ErrUserNotFound)
+}
+
+func testOffersAndApplications(t *testing.T, s Store) {
+	ctx := context.Background()
+	authorID, _ := mustCreateUser(t, s, "Author")
+	applicantID, _ := mustCreateUser(t, s, "Applicant")
+	offerID, title := mustCreateOffer(t, s, authorID, nil)
+
+	offers, err := s.GetOffers(ctx, models.OfferFilter{Search: title, ViewerID: &applicantID})
+	if err != nil {
+		t.Fatalf("GetOffers: %v", err)
+	}
+	if len(offers) != 1 || offers[0].ID != offerID || offers[0].AuthorFirstName != "Author" || offers[0].HasResponded {
+		t.Fatalf("GetOffers = %+v", offers)
+	}
+
+	applicationID, err := s.CreateOfferResponse(ctx, &models.OfferApplication{OfferID: offerID, ApplicantID: applicantID, Message: "Hi"})
+	if err != nil {
+		t.Fatalf("CreateOfferResponse: %v", err)
+	}
+	_, err = s.CreateOfferResponse(ctx, &models.OfferApplication{OfferID: offerID, ApplicantID: applicantID, Message: "Again"})
+	expectError(t, "second CreateOfferResponse", err, ErrApplicationExists)
+	for _, missing := range []string{uuid.NewString(), "not-a-uuid"} {
+		_, err = s.CreateOfferResponse(ctx, &models.OfferApplication{OfferID: missing, ApplicantID: applicantID, Message: "Hi"})
+		expectError(t, "CreateOfferResponse for offer "+missing, err, ErrOfferNotFound)
+	}
+	if !hasNotification(t, s, authorID, models.NotificationApplicationCreated) {
+		t.Error("author was not notified about the application")
+	}
+
+	// Applications are only accepted for offers that GetOffers would list
+	hiddenID, _ := mustCreateOffer(t, s, authorID, nil)
+	inactive := false
+	if err := s.UpdateOfferStatus(ctx, hiddenID, models.UpdateOfferPayload{IsActive: &inactive}); err != nil {
+		t.Fatalf("UpdateOfferStatus: %v", err)
+	}
+	_, err = s.CreateOfferResponse(ctx, &models.OfferApplication{OfferID: hiddenID, ApplicantID: applicantID, Message: "Hi"})
+	expectError(t, "CreateOfferResponse for an inactive offer", err, ErrOfferNotFound)
+	bannedID, _ := mustCreateUser(t, s, "Banned")
+	bannedOfferID, _ := mustCreateOffer(t, s, bannedID, nil)
+	if err := s.BanUser(ctx, bannedID, authorID, models.BanUserPayload{Reason: "fraud"}); err != nil {
+		t.Fatalf("BanUser: %v", err)
+	}
+	_, err = s.CreateOfferResponse(ctx, &models.OfferApplication{OfferID: bannedOfferID, ApplicantID: applicantID, Message: "Hi"})
+	expectError(t, "CreateOfferResponse for an offer of a banned author", err, ErrOfferNotFound)
+
+	offers, err = s.GetOffers(ctx, models.OfferFilter{Search: title, ViewerID: &applicantID})
+	if err != nil {
+		t.Fatalf("GetOffers: %v", err)
+	}
+	if len(offers) != 1 || !offers[0].HasResponded {
+		t.Errorf("HasResponded is not set after responding: %+v", offers)
+	}
+
+	applications, err := s.GetOfferApplications(ctx, offerID)
+	if err != nil {
+		t.Fatalf("GetOfferApplications: %v", err)
+	}
+	if len(applications) != 1 || applications[0].ID != applicationID || applications[0].Status != "pending" {
+		t.Errorf("GetOfferApplications = %+v", applications)
+	}
+
+	otherOfferID, _ := mustCreateOffer(t, s, authorID, nil)
+	err = s.UpdateApplicationStatus(ctx, otherOfferID, applicationID, "accepted")
+	expectError(t, "UpdateApplicationStatus with another offer", err, ErrApplicationNotFound)
+	if err := s.UpdateApplicationStatus(ctx, offerID, applicationID, "accepted"); err != nil {
+		t.Fatalf("UpdateApplicationStatus: %v", err)
+	}
+	if !hasNotification(t, s, applicantID, models.NotificationApplicationAccepted) {
+		t.Error("applicant was not notified about the decision")
+	}
+
+	if author, err := s.GetOfferAuthor(ctx, offerID); err != nil || author != authorID {
+		t.Errorf("GetOfferAuthor = %q, %v", author, err)
+	}
+	if err := s.DeleteOffer(ctx, offerID); err != nil {
+		t.Fatalf("DeleteOffer: %v", err)
+	}
+	_, err = s.GetOfferAuthor(ctx, offerID)
+	expectError(t, "GetOfferAuthor after delete", err, ErrOfferNotFound)
+	expectError(t, "second DeleteOffer", s.DeleteOffer(ctx, offerID), ErrOfferNotFound)
+	active := true
+	err = s.UpdateOfferStatus(ctx, offerID, models.UpdateOfferPayload{IsActive: &active})
+	expectError(t, "UpdateOfferStatus of a deleted offer", err, ErrOfferNotFound)
+	if err := s.RestoreOffer(ctx, offerID); err != nil {
+		t.Fatalf("RestoreOffer: %v", err)
+	}
+	if _, err := s.GetOfferAuthor(ctx, offerID); err != nil {
+		t.Errorf("GetOfferAuthor after restore: %v", err)
+	}
+}
+
+func testJobs(t *testing.T, s Store) {
+	ctx := context.Background()
+	masterID, _ := mustCreateUser(t, s, "Master")
+	clientID, _ := mustCreateUser(t, s, "Client")
+	offerID, title := mustCreateOffer(t, s, masterID, nil)
+	webhookID, err := s.CreateWebhook(ctx, models.WebhookPayload{
+		URL:    "https://example.com/" + unique("hook"),
+		Events: []string{models.WebhookEventJobStatusChanged},
+	}, "secret", masterID)
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	applicationID, err := s.CreateOfferResponse(ctx, &models.OfferApplication{OfferID: offerID, ApplicantID: clientID})
+	if err != nil {
+		t.Fatalf("CreateOfferResponse: %v", err)
+	}
+	for _, status := range []string{"accepted", "rejected", "accepted"} {
+		if err := s.UpdateApplicationStatus(ctx, offerID, applicationID, status); err != nil {
+			t.Fatalf("UpdateApplicationStatus(%s): %v", status, err)
+		}
+	}
+
+	jobs, err := s.GetUserJobs(ctx, masterID)
+	if err != nil {
+		t.Fatalf("GetUserJobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ClientID != clientID || jobs[0].MasterID != masterID || jobs[0].OfferTitle != title ||
+		jobs[0].Status != models.JobStatusAssigned {
+		t.Fatalf("GetUserJobs = %+v, want one assigned job", jobs)
+	}
+	jobID := jobs[0].ID
+	if !hasNotification(t, s, clientID, models.NotificationJobStatusChanged) {
+		t.Error("client was not notified about the assigned job")
+	}
+	_, err = s.CreateJobReview(ctx, jobID, clientID, models.CreateReviewPayload{Rating: 5})
+	expectError(t, "CreateJobReview of an unfinished job", err, ErrJobNotCompleted)
+
+	outsiderID, _ := mustCreateUser(t, s, "Outsider")
+	_, err = s.UpdateJobStatus(ctx, jobID, outsiderID, models.JobStatusInProgress)
+	expectError(t, "UpdateJobStatus by an outsider", err, ErrJobNotFound)
+	_, err = s.UpdateJobStatus(ctx, "not-a-uuid", masterID, models.JobStatusInProgress)
+	expectError(t, "UpdateJobStatus of unknown job", err, ErrJobNotFound)
+	_, err = s.UpdateJobStatus(ctx, jobID, masterID, models.JobStatusCompleted)
+	expectError(t, "UpdateJobStatus skipping in_progress", err, ErrJobTransition)
+	if _, err := s.UpdateJobStatus(ctx, jobID, clientID, models.JobStatusInProgress); err != nil {
+		t.Fatalf("UpdateJobStatus(in_progress): %v", err)
+	}
+	if !hasNotification(t, s, masterID, models.NotificationJobStatusChanged) {
+		t.Error("master was not notified about the status change")
+	}
+	if changed, err := s.UpdateJobStatus(ctx, jobID, masterID, models.JobStatusCompleted); err != nil || !changed {
+		t.Fatalf("UpdateJobStatus(completed) = %v, %v", changed, err)
+	}
+	if changed, err := s.UpdateJobStatus(ctx, jobID, clientID, models.JobStatusCompleted); err != nil || changed {
+		t.Errorf("second UpdateJobStatus(completed) = %v, %v, want no change", changed, err)
+	}
+	_, err = s.UpdateJobStatus(ctx, jobID, masterID, models.JobStatusCancelled)
+	expectError(t, "UpdateJobStatus of a completed job", err, ErrJobTransition)
+
+	if _, err := s.CreateJobReview(ctx, jobID, clientID, models.CreateReviewPayload{Rating: 4}); err != nil {
+		t.Fatalf("CreateJobReview: %v", err)
+	}
+	_, err = s.CreateJobReview(ctx, jobID, clientID, models.CreateReviewPayload{Rating: 5})
+	expectError(t, "second CreateJobReview", err, ErrReviewExists)
+	_, err = s.CreateJobReview(ctx, jobID, outsiderID, models.CreateReviewPayload{Rating: 1})
+	expectError(t, "CreateJobReview by an outsider", err, ErrJobNotFound)
+	if !hasNotification(t, s, masterID, models.NotificationReviewReceived) {
+		t.Error("master was not notified about the review")
+	}
+	if !hasTask(t, s, models.TaskKindRatingRecalculate, "userId", masterID) {
+		t.Error("rating recalculation of the master was not queued")
+	}
+	if err := s.RecalculateUserRating(ctx, masterID); err != nil {
+		t.Fatalf("RecalculateUserRating: %v", err)
+	}
+	master, err := s.GetUserDetailByID(ctx, masterID)
+	if err != nil {
+		t.Fatalf("GetUserDetailByID: %v", err)
+	}
+	if master.AverageRating == nil || *master.AverageRating != 4 {
+		t.Errorf("master rating after review = %v, want 4", master.AverageRating)
+	}
+	if _, err := s.RecomputeRatings(ctx); err != nil {
+		t.Fatalf("RecomputeRatings: %v", err)
+	}
+	if master, err := s.GetUserDetailByID(ctx, masterID); err != nil || master.AverageRating == nil || *master.AverageRating != 4 {
+		t.Errorf("master rating after RecomputeRatings = %+v, %v", master, err)
+	}
+
+	jobs, err = s.GetUserJobs(ctx, clientID)
+	if err != nil {
+		t.Fatalf("GetUserJobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Status != models.JobStatusCompleted || jobs[0].StartedAt == nil || jobs[0].CompletedAt == nil {
+		t.Errorf("GetUserJobs after completion = %+v", jobs)
+	}
+	if stats, err := s.GetAdminStats(ctx); err != nil || stats.TotalJobs < 1 {
+		t.Errorf("GetAdminStats = %+v, %v", stats, err)
+	}
+
+	// Every status change, including the assignment, is published to webhooks
+	tasks, err := s.GetTasks(ctx, models.TaskFilter{Kind: models.TaskKindWebhookDelivery, Limit: 100})
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	var statuses []string
+	for _, task := range tasks {
+		var delivery models.WebhookDeliveryTask
+		if err := json.Unmarshal(task.Payload, &delivery); err != nil {
+			t.Fatalf("failed to decode task payload: %v", err)
+		}
+		if delivery.WebhookID == webhookID && delivery.Event.Type == models.WebhookEventJobStatusChanged && delivery.Event.Data["jobId"] == jobID {
+			statuses = append(statuses, delivery.Event.Data["status"].(string))
+		}
+	}
+	sort.Strings(statuses)
+	if want := []string{models.JobStatusAssigned, models.JobStatusCompleted, models.JobStatusInProgress}; !slices.Equal(statuses, want) {
+		t.Errorf("job.status_changed deliveries = %v, want %v", statuses, want)
+	}
+}
+
+func testChat(t *testing.T, s Store) {
+	ctx := context.Background()
+	authorID, _ := mustCreateUser(t, s, "Author")
+	clientID, _ := mustCreateUser(t, s, "Client")
+	outsiderID, _ := mustCreateUser(t, s, "Outsider")
+	offerID, title := mustCreateOffer(t, s, authorID, nil)
+
+	conversationID, err := s.InitiateChat(ctx, offerID, clientID, authorID)
+	if err != nil {
+		t.Fatalf("InitiateChat: %v", err)
+	}
+	again, err := s.InitiateChat(ctx, offerID, authorID, clientID)
+	if err != nil || again != conversationID {
+		t.Errorf("repeated InitiateChat = %q, %v; want the existing conversation %q", again, err, conversationID)
+	}
+
+	_, err = s.PostMessage(ctx, conversationID, outsiderID, "Let me in")
+	expectError(t, "PostMessage by outsider", err, ErrNotParticipant)
+	_, err = s.GetMessages(ctx, conversationID, outsiderID)
+	expectError(t, "GetMessages by outsider", err, ErrNotParticipant)
+	_, err = s.GetChatDetails(ctx, conversationID, outsiderID)
+	expectError(t, "GetChatDetails by outsider", err, ErrNotParticipant)
+
+	msg, err := s.PostMessage(ctx, conversationID, clientID, "Hello")
+	if err != nil {
+		t.Fatalf("PostMessage: %v", err)
+	}
+	if msg.SenderFirstName != "Client" || msg.Content != "Hello" || msg.IsRead {
+		t.Errorf("PostMessage = %+v", msg)
+	}
+	if !hasNotification(t, s, authorID, models.NotificationMessageCreated) {
+		t.Error("recipient was not notified about the message")
+	}
+	if hasNotification(t, s, clientID, models.NotificationMessageCreated) {
+		t.Error("sender was notified about their own message")
+	}
+
+	messages, err := s.GetMessages(ctx, conversationID, authorID)
+	if err != nil {
+		t.Fatalf("GetMessages: %v", err)
+	}
+	if len(messages) != 1 || messages[0].ID != msg.ID {
+		t.Errorf("GetMessages = %+v", messages)
+	}
+
+	details, err := s.GetChatDetails(ctx, conversationID, authorID)
+	if err != nil {
+		t.Fatalf("GetChatDetails: %v", err)
+	}
+	if details.OfferID != offerID || details.OfferTitle != title || len(details.Participants) != 2 {
+		t.Errorf("GetChatDetails = %+v", details)
+	}
+
+	previews, err := s.GetConversations(ctx, authorID)
+	if err != nil {
+		t.Fatalf("GetConversations: %v", err)
+	}
+	if len(previews) != 1 || previews[0].OtherParticipantID != clientID || previews[0].LastMessageContent != "Hello" {
+		t.Errorf("GetConversations = %+v", previews)
+	}
+}
+
+func findCategory(t *testing.T, s Store, categoryID int) models.ServiceCategory {
+	t.Helper()
+	categories, err := s.GetAllCategories(context.Background(), "ru")
+	if err != nil {
+		t.Fatalf("GetAllCategories: %v", err)
+	}
+	for _, c := range categories {
+		if c.ID == categoryID {
+			return c
+		}
+	}
+	t.Fatalf("category %d not found", categoryID)
+	return models.ServiceCategory{}
+}
+
+func testCategories(t *testing.T, s Store) {
+	ctx := context.Background()
+	parentSlug := unique("parent")
+	parentID, err := s.CreateCategory(ctx, models.CategoryPayload{Name: unique("Parent"), Slug: &parentSlug})
+	if err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	_, err = s.CreateCategory(ctx, models.CategoryPayload{Name: unique("Other"), Slug: &parentSlug})
+	expectError(t, "CreateCategory with taken slug", err, ErrSlugTaken)
+
+	childName := unique("Child")
+	childID, err := s.CreateCategory(ctx, models.CategoryPayload{
+		Name:         childName,
+		ParentID:     &parentID,
+		Translations: map[string]models.CategoryTranslation{"en": {Name: childName + " (en)"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateCategory for child: %v", err)
+	}
+	_, err = s.CreateCategory(ctx, models.CategoryPayload{Name: childName, ParentID: &parentID})
+	expectError(t, "CreateCategory with a sibling's name", err, ErrCategoryNameTaken)
+
+	categories, err := s.GetAllCategories(ctx, "en")
+	if err != nil {
+		t.Fatalf("GetAllCategories: %v", err)
+	}
+	var child *models.ServiceCategory
+	for i := range categories {
+		if categories[i].ID == childID {
+			child = &categories[i]
+		}
+	}
+	if child == nil || child.Name != childName+" (en)" || child.ParentID == nil || *child.ParentID != parentID {
+		t.Errorf("child category in GetAllCategories(en) = %+v", child)
+	}
+
+	err = s.UpdateCategory(ctx, parentID, models.UpdateCategoryPayload{ParentID: &childID})
+	expectError(t, "UpdateCategory under its descendant", err, ErrCategoryCycle)
+
+	icon := "plug"
+	leafID, err := s.CreateCategory(ctx, models.CategoryPayload{Name: unique("Leaf"), ParentID: &parentID, Icon: &icon})
+	if err != nil {
+		t.Fatalf("CreateCategory for leaf: %v", err)
+	}
+	err = s.UpdateCategory(ctx, leafID, models.UpdateCategoryPayload{Name: &childName})
+	expectError(t, "UpdateCategory to a sibling's name", err, ErrCategoryNameTaken)
+	leafName := unique("Leaf")
+	if err := s.UpdateCategory(ctx, leafID, models.UpdateCategoryPayload{Name: &leafName}); err != nil {
+		t.Fatalf("UpdateCategory with name only: %v", err)
+	}
+	sortOrder := 5
+	if err := s.UpdateCategory(ctx, leafID, models.UpdateCategoryPayload{SortOrder: &sortOrder}); err != nil {
+		t.Fatalf("UpdateCategory without name: %v", err)
+	}
+	leaf := findCategory(t, s, leafID)
+	if leaf.Name != leafName || leaf.SortOrder != sortOrder || leaf.ParentID == nil || *leaf.ParentID != parentID || leaf.Icon == nil || *leaf.Icon != icon {
+		t.Errorf("category after partial update = %+v", leaf)
+	}
+	if err := s.UpdateCategory(ctx, leafID, models.UpdateCategoryPayload{Clear: []string{"parentId", "icon"}}); err != nil {
+		t.Fatalf("UpdateCategory clearing fields: %v", err)
+	}
+	if leaf := findCategory(t, s, leafID); leaf.ParentID != nil || leaf.Icon != nil {
+		t.Errorf("category after clearing parent and icon = %+v", leaf)
+	}
+
+	// Seeded categories carry a default-locale translation that must follow base edits
+	translatedID, err := s.CreateCategory(ctx, models.CategoryPayload{
+		Name:         unique("Translated"),
+		Translations: map[string]models.CategoryTranslation{utils.DefaultLocale: {Name: "old"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateCategory with translation: %v", err)
+	}
+	translatedName := unique("Translated")
+	if err := s.UpdateCategory(ctx, translatedID, models.UpdateCategoryPayload{Name: &translatedName}); err != nil {
+		t.Fatalf("UpdateCategory without translations: %v", err)
+	}
+	if c := findCategory(t, s, translatedID); c.Name != translatedName {
+		t.Errorf("category name after base edit = %q, want %q", c.Name, translatedName)
+	}
+
+	attribute := models.CategoryAttributePayload{Key: "area", Label: "Area", Type: models.AttributeTypeNumber}
+	attributeID, err := s.CreateCategoryAttribute(ctx, childID, attribute)
+	if err != nil {
+		t.Fatalf("CreateCategoryAttribute: %v", err)
+	}
+	_, err = s.CreateCategoryAttribute(ctx, childID, attribute)
+	expectError(t, "CreateCategoryAttribute with taken key", err, ErrAttributeKeyTaken)
+	attributes, err := s.GetCategoryAttributes(ctx, childID)
+	if err != nil {
+		t.Fatalf("GetCategoryAttributes: %v", err)
+	}
+	if len(attributes) != 1 || attributes[0].ID != attributeID {
+		t.Errorf("GetCategoryAttributes = %+v", attributes)
+	}
+	_, err = s.GetCategoryAttributes(ctx, -1)
+	expectError(t, "GetCategoryAttributes of unknown category", err, ErrCategoryNotFound)
+	expectError(t, "DeleteCategoryAttribute of unknown attribute",
+		s.DeleteCategoryAttribute(ctx, parentID, attributeID), ErrAttributeNotFound)
+
+	authorID, _ := mustCreateUser(t, s, "Author")
+	offerID, _ := mustCreateOffer(t, s, authorID, &childID)
+	offers, err := s.GetOffers(ctx, models.OfferFilter{Category: parentSlug})
+	if err != nil {
+		t.Fatalf("GetOffers: %v", err)
+	}
+	if len(offers) != 1 || offers[0].ID != offerID {
+		t.Errorf("GetOffers by parent category = %+v, want the offer from the subcategory", offers)
+	}
+
+	expectError(t, "DeleteCategory with children", s.DeleteCategory(ctx, parentID, nil), ErrCategoryNotEmpty)
+	expectError(t, "DeleteCategory of unknown category", s.DeleteCategory(ctx, -1, nil), ErrCategoryNotFound)
+}
+
+func testNotifications(t *testing.T, s Store) {
+	ctx := context.Background()
+	userID, _ := mustCreateUser(t, s, "Reader")
+
+	for i := 0; i < 3; i++ {
+		err := s.CreateNotification(ctx, userID, models.NotificationReviewReceived, map[string]interface{}{"n": i})
+		if err != nil {
+			t.Fatalf("CreateNotification: %v", err)
+		}
+	}
+	list, err := s.GetNotifications(ctx, userID, models.NotificationFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetNotifications: %v", err)
+	}
+	if len(list.Items) != 2 || list.UnreadCount != 3 {
+		t.Fatalf("GetNotifications = %d items, %d unread; want 2 items, 3 unread", len(list.Items), list.UnreadCount)
+	}
+	var payload map[string]int
+	if err := json.Unmarshal(list.Items[0].Payload, &payload); err != nil || payload["n"] != 2 {
+		t.Errorf("newest notification payload = %s, want n = 2", list.Items[0].Payload)
+	}
+
+	if err := s.MarkNotificationRead(ctx, userID, list.Items[0].ID); err != nil {
+		t.Fatalf("MarkNotificationRead: %v", err)
+	}
+	otherID, _ := mustCreateUser(t, s, "Other")
+	expectError(t, "MarkNotificationRead by another user",
+		s.MarkNotificationRead(ctx, otherID, list.Items[1].ID), ErrNotificationNotFound)
+	if updated, err := s.MarkAllNotificationsRead(ctx, userID); err != nil || updated != 2 {
+		t.Errorf("MarkAllNotificationsRead = %d, %v; want 2", updated, err)
+	}
+
+	err = s.UpdateNotificationPreferences(ctx, userID, map[string]bool{models.NotificationReviewReceived: false})
+	if err != nil {
+		t.Fatalf("UpdateNotificationPreferences: %v", err)
+	}
+	preferences, err := s.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetNotificationPreferences: %v", err)
+	}
+	if preferences[models.NotificationReviewReceived] || !preferences[models.NotificationMessageCreated] {
+		t.Errorf("GetNotificationPreferences = %v", preferences)
+	}
+	if err := s.CreateNotification(ctx, userID, models.NotificationReviewReceived, nil); err != nil {
+		t.Fatalf("CreateNotification: %v", err)
+	}
+	list, err = s.GetNotifications(ctx, userID, models.NotificationFilter{UnreadOnly: true, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetNotifications: %v", err)
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("disabled notification type was stored: %+v", list.Items)
+	}
+}
+
+func testOutbox(t *testing.T, s Store) {
+	ctx := context.Background()
+	userID, email := mustCreateUser(t, s, "Mailed")
+	err := s.CreateNotification(ctx, userID, models.NotificationApplicationAccepted, map[string]interface{}{"offerTitle": "Roof"})
+	if err != nil {
+		t.Fatalf("CreateNotification: %v", err)
+	}
+
+	tasks, err := s.GetTasks(ctx, models.TaskFilter{Kind: models.TaskKindEmailSend, Limit: 1000})
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	var mail *models.OutboxEmail
+	for _, task := range tasks {
+		var payload models.EmailTask
+		if err := json.Unmarshal(task.Payload, &payload); err != nil {
+			t.Fatalf("task %d payload: %v", task.ID, err)
+		}
+		e, err := s.GetOutboxEmail(ctx, payload.EmailID)
+		if err == nil && e.To == email {
+			mail = e
+			if task.MaxAttempts != emailSendAttempts {
+				t.Errorf("email task max attempts = %d, want %d", task.MaxAttempts, emailSendAttempts)
+			}
+		}
+	}
+	if mail == nil {
+		t.Fatal("no send task was queued for the notification email")
+	}
+	if mail.Template != "application_accepted" || mail.Data["offerTitle"] != "Roof" {
+		t.Errorf("outbox email = %+v", mail)
+	}
+
+	// A failed send leaves the email for the next attempt of the task
+	if err := s.MarkEmailFailed(ctx, mail.ID, "smtp down"); err != nil {
+		t.Fatalf("MarkEmailFailed: %v", err)
+	}
+	if _, err := s.GetOutboxEmail(ctx, mail.ID); err != nil {
+		t.Errorf("GetOutboxEmail after a failure: %v", err)
+	}
+	if err := s.MarkEmailSent(ctx, mail.ID); err != nil {
+		t.Fatalf("MarkEmailSent: %v", err)
+	}
+	_, err = s.GetOutboxEmail(ctx, mail.ID)
+	expectError(t, "GetOutboxEmail of a sent email", err, ErrEmailNotFound)
+	_, err = s.GetOutboxEmail(ctx, -1)
+	expectError(t, "GetOutboxEmail of unknown email", err, ErrEmailNotFound)
+}
+
+func testAvatars(t *testing.T, s Store) {
+	ctx := context.Background()
+	userID, _ := mustCreateUser(t, s, "Pictured")
+
+	_, err := s.GetUserAvatar(ctx, userID)
+	expectError(t, "GetUserAvatar before upload", err, ErrAvatarNotFound)
+	expectError(t, "SetUserAvatar of unknown user", s.SetUserAvatar(ctx, uuid.NewString(), "image/png", []byte("png")), ErrUserNotFound)
+
+	if err := s.SetUserAvatar(ctx, userID, "image/png", []byte("first")); err != nil {
+		t.Fatalf("SetUserAvatar: %v", err)
+	}
+	if !hasTask(t, s, models.TaskKindAvatarThumbnail, "userId", userID) {
+		t.Error("thumbnail task was not queued")
+	}
+	first, err := s.GetUserAvatar(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetUserAvatar: %v", err)
+	}
+	if first.ContentType != "image/png" || string(first.Image) != "first" || first.Thumbnail != nil {
+		t.Errorf("GetUserAvatar = %+v", first)
+	}
+
+	if err := s.SetAvatarThumbnail(ctx, userID, first.UpdatedAt, []byte("thumb")); err != nil {
+		t.Fatalf("SetAvatarThumbnail: %v", err)
+	}
+	if a, err := s.GetUserAvatar(ctx, userID); err != nil || string(a.Thumbnail) != "thumb" {
+		t.Errorf("GetUserAvatar after SetAvatarThumbnail = %+v, %v", a, err)
+	}
+
+	// A new upload drops the old thumbnail, and a late thumbnail of the old image is ignored
+	if err := s.SetUserAvatar(ctx, userID, "image/jpeg", []byte("second")); err != nil {
+		t.Fatalf("SetUserAvatar: %v", err)
+	}
+	if err := s.SetAvatarThumbnail(ctx, userID, first.UpdatedAt.Add(-time.Second), []byte("stale")); err != nil {
+		t.Fatalf("SetAvatarThumbnail: %v", err)
+	}
+	second, err := s.GetUserAvatar(ctx, userID)
+	if err != nil || second.ContentType != "image/jpeg" || string(second.Image) != "second" || second.Thumbnail != nil {
+		t.Errorf("GetUserAvatar after a new upload = %+v, %v", second, err)
+	}
+
+	if err := s.DeleteUser(ctx, userID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	_, err = s.GetUserAvatar(ctx, userID)
+	expectError(t, "GetUserAvatar of a deleted user", err, ErrAvatarNotFound)
+}
+
+func testTasks(t *testing.T, s Store) {
+	ctx := context.Background()
+	kind := unique("test.task")
+
+	taskID, err := s.EnqueueTask(ctx, models.NewTask{Kind: kind, Payload: map[string]string{"key": "value"}, MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("EnqueueTask: %v", err)
+	}
+	task, err := s.ClaimTask(ctx, []string{kind}, "worker-1", time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimTask: %v", err)
+	}
+	if task == nil || task.ID != taskID || task.Status != models.TaskStatusRunning || task.Attempts != 1 ||
+		task.LockedBy == nil || *task.LockedBy != "worker-1" {
+		t.Fatalf("ClaimTask = %+v", task)
+	}
+	if next, err := s.ClaimTask(ctx, []string{kind}, "worker-2", time.Minute); err != nil || next != nil {
+		t.Errorf("ClaimTask with a leased task = %+v, %v; want nil", next, err)
+	}
+
+	expectError(t, "RetryTask of a running task", s.RetryTask(ctx, taskID), ErrDeadTaskNotFound)
+	if err := s.FailTask(ctx, taskID, "boom", nil); err != nil {
+		t.Fatalf("FailTask: %v", err)
+	}
+	task, err = s.GetTask(ctx, taskID)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if task.Status != models.TaskStatusDead || task.LastError == nil || *task.LastError != "boom" {
+		t.Errorf("task after failure = %+v", task)
+	}
+
+	if err := s.RetryTask(ctx, taskID); err != nil {
+		t.Fatalf("RetryTask: %v", err)
+	}
+	task, err = s.ClaimTask(ctx, []string{kind}, "worker-1", time.Minute)
+	if err != nil || task == nil || task.ID != taskID {
+		t.Fatalf("ClaimTask after retry = %+v, %v", task, err)
+	}
+	if err := s.CompleteTask(ctx, taskID); err != nil {
+		t.Fatalf("CompleteTask: %v", err)
+	}
+
+	tasks, err := s.GetTasks(ctx, models.TaskFilter{Kind: kind, Status: models.TaskStatusSucceeded, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != taskID || tasks[0].FinishedAt == nil {
+		t.Errorf("GetTasks = %+v", tasks)
+	}
+	_, err = s.GetTask(ctx, -1)
+	expectError(t, "GetTask of unknown task", err, ErrTaskNotFound)
+}
+
+func testWebhooks(t *testing.T, s Store) {
+	ctx := context.Background()
+	adminID, _ := mustCreateUser(t, s, "Admin")
+
+	webhookID, err := s.CreateWebhook(ctx, models.WebhookPayload{
+		URL:    "https://example.com/" + unique("hook"),
+		Events: []string{models.WebhookEventOfferCreated},
+	}, "secret-1", adminID)
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	webhook, err := s.GetWebhook(ctx, webhookID)
+	if err != nil {
+		t.Fatalf("GetWebhook: %v", err)
+	}
+	if !webhook.IsActive || webhook.Secret != "secret-1" {
+		t.Errorf("GetWebhook = %+v", webhook)
+	}
+	webhooks, err := s.GetWebhooks(ctx)
+	if err != nil {
+		t.Fatalf("GetWebhooks: %v", err)
+	}
+	for _, w := range webhooks {
+		if w.Secret != "" {
+			t.Errorf("GetWebhooks exposes the secret of webhook %d", w.ID)
+		}
+	}
+
+	// Creating an offer queues a delivery for the subscribed webhook
+	offerID, _ := mustCreateOffer(t, s, adminID, nil)
+	tasks, err := s.GetTasks(ctx, models.TaskFilter{Kind: models.TaskKindWebhookDelivery, Limit: 100})
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	queued := false
+	for _, task := range tasks {
+		var delivery models.WebhookDeliveryTask
+		if err := json.Unmarshal(task.Payload, &delivery); err != nil {
+			t.Fatalf("failed to decode task payload: %v", err)
+		}
+		if delivery.WebhookID == webhookID {
+			queued = delivery.Event.Type == models.WebhookEventOfferCreated && delivery.Event.Data["offerId"] == offerID
+		}
+	}
+	if !queued {
+		t.Error("offer.created delivery was not queued")
+	}
+
+	if _, err := s.EnqueueWebhookTest(ctx, webhookID); err != nil {
+		t.Fatalf("EnqueueWebhookTest: %v", err)
+	}
+	if err := s.RotateWebhookSecret(ctx, webhookID, "secret-2"); err != nil {
+		t.Fatalf("RotateWebhookSecret: %v", err)
+	}
+	err = s.RecordWebhookDelivery(ctx, models.WebhookDelivery{
+		WebhookID: webhookID, EventID: uuid.NewString(), EventType: models.WebhookEventTest, Attempt: 1, Success: true,
+	})
+	if err != nil {
+		t.Fatalf("RecordWebhookDelivery: %v", err)
+	}
+	deliveries, err := s.GetWebhookDeliveries(ctx, webhookID, 10, 0)
+	if err != nil {
+		t.Fatalf("GetWebhookDeliveries: %v", err)
+	}
+	if len(deliveries) != 1 || !deliveries[0].Success {
+		t.Errorf("GetWebhookDeliveries = %+v", deliveries)
+	}
+
+	if err := s.DeleteWebhook(ctx, webhookID); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+	_, err = s.GetWebhook(ctx, webhookID)
+	expectError(t, "GetWebhook after delete", err, ErrWebhookNotFound)
+	expectError(t, "DeleteWebhook of deleted webhook", s.DeleteWebhook(ctx, webhookID), ErrWebhookNotFound)
+	_, err = s.EnqueueWebhookTest(ctx, webhookID)
+	expectError(t, "EnqueueWebhookTest of deleted webhook", err, ErrWebhookNotFound)
+	deliveries, err = s.GetWebhookDeliveries(ctx, webhookID, 10, 0)
+	if err != nil || len(deliveries) != 0 {
+		t.Errorf("deliveries of deleted webhook = %+v, %v", deliveries, err)
+	}
+}
+
+func testAPIKeys(t *testing.T, s Store) {
+	ctx := context.Background()
+	ownerID, _ := mustCreateUser(t, s, "Owner")
+	otherID, _ := mustCreateUser(t, s, "Other")
+	prefix := strconv.FormatInt(time.Now().UnixNano(), 36) + strconv.FormatInt(uniqueCounter.Add(1), 36)
+
+	key, err := s.CreateAPIKey(ctx, ownerID, models.CreateAPIKeyPayload{Name: "CI", Scopes: []string{"offers:read"}}, prefix, "key-hash")
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	_, err = s.CreateAPIKey(ctx, otherID, models.CreateAPIKeyPayload{Name: "Copy", Scopes: []string{"offers:read"}}, prefix, "other-hash")
+	expectError(t, "CreateAPIKey with a taken prefix", err, ErrAPIKeyPrefixTaken)
+
+	keys, err := s.GetAPIKeys(ctx, &ownerID)
+	if err != nil {
+		t.Fatalf("GetAPIKeys: %v", err)
+	}
+	if len(keys) != 1 || keys[0].ID != key.ID || keys[0].Prefix != prefix {
+		t.Errorf("GetAPIKeys = %+v", keys)
+	}
+
+	creds, err := s.GetAPIKeyCredentials(ctx, prefix)
+	if err != nil {
+		t.Fatalf("GetAPIKeyCredentials: %v", err)
+	}
+	if creds.ID != key.ID || creds.UserID != ownerID || creds.KeyHash != "key-hash" || creds.IsAdmin || creds.RevokedAt != nil {
+		t.Errorf("GetAPIKeyCredentials = %+v", creds)
+	}
+	if err := s.TouchAPIKey(ctx, key.ID, "192.0.2.1"); err != nil {
+		t.Fatalf("TouchAPIKey: %v", err)
+	}
+
+	expectError(t, "RevokeAPIKey by another user", s.RevokeAPIKey(ctx, key.ID, &otherID), ErrAPIKeyNotFound)
+	if err := s.RevokeAPIKey(ctx, key.ID, &ownerID); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	creds, err = s.GetAPIKeyCredentials(ctx, prefix)
+	if err != nil {
+		t.Fatalf("GetAPIKeyCredentials: %v", err)
+	}
+	if creds.RevokedAt == nil {
+		t.Error("revoked key has no revocation time")
+	}
+
+	// Keys of deleted users cannot authenticate
+	if err := s.DeleteUser(ctx, ownerID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err := s.GetAPIKeyCredentials(ctx, prefix); err == nil {
+		t.Error("GetAPIKeyCredentials returned the key of a deleted user")
+	}
+}
+
+func testOIDC(t *testing.T, s Store) {
+	ctx := context.Background()
+	state := models.OIDCLoginState{
+		State: unique("state"), Provider: "test", Nonce: "nonce", CodeVerifier: "verifier",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	if err := s.SaveOIDCLoginState(ctx, state); err != nil {
+		t.Fatalf("SaveOIDCLoginState: %v", err)
+	}
+	consumed, err := s.ConsumeOIDCLoginState(ctx, state.State)
+	if err != nil {
+		t.Fatalf("ConsumeOIDCLoginState: %v", err)
+	}
+	if consumed.Nonce != state.Nonce || consumed.CodeVerifier != state.CodeVerifier {
+		t.Errorf("ConsumeOIDCLoginState = %+v", consumed)
+	}
+	_, err = s.ConsumeOIDCLoginState(ctx, state.State)
+	expectError(t, "second ConsumeOIDCLoginState", err, ErrLoginStateNotFound)
+
+	email := unique("oidc") + "@example.com"
+	identity := models.OIDCIdentity{Provider: "test", Subject: unique("subject"), Email: email, EmailVerified: true, FirstName: "Olga"}
+	user, err := s.ResolveOIDCUser(ctx, identity)
+	if err != nil {
+		t.Fatalf("ResolveOIDCUser: %v", err)
+	}
+	if user.Email != email || user.PasswordHash != "" {
+		t.Errorf("ResolveOIDCUser created %+v", user)
+	}
+	again, err := s.ResolveOIDCUser(ctx, identity)
+	if err != nil || again.ID != user.ID {
+		t.Errorf("ResolveOIDCUser for a linked identity = %+v, %v; want user %s", again, err, user.ID)
+	}
+
+	existingID, existingEmail := mustCreateUser(t, s, "Existing")
+	_, err = s.ResolveOIDCUser(ctx, models.OIDCIdentity{Provider: "test", Subject: unique("subject"), Email: existingEmail})
+	expectError(t, "ResolveOIDCUser with unverified email", err, ErrIdentityEmailUnverified)
+	_, err = s.ResolveOIDCUser(ctx, models.OIDCIdentity{Provider: "test", Subject: unique("subject"), Email: unique("new") + "@example.com"})
+	expectError(t, "ResolveOIDCUser with unverified new email", err, ErrIdentityEmailUnverified)
+	linked, err := s.ResolveOIDCUser(ctx, models.OIDCIdentity{
+		Provider: "test", Subject: unique("subject"), Email: strings.ToUpper(existingEmail), EmailVerified: true,
+	})
+	if err != nil || linked.ID != existingID {
+		t.Errorf("ResolveOIDCUser with upper-case email = %+v, %v; want user %s", linked, err, existingID)
+	}
+	_, err = s.ResolveOIDCUser(ctx, models.OIDCIdentity{Provider: "test", Subject: unique("subject")})
+	expectError(t, "ResolveOIDCUser without email", err, ErrIdentityEmailRequired)
+}
+
+func testAuditLog(t *testing.T, s Store) {
+	adminID, _ := mustCreateUser(t, s, "Admin")
+	userID, _ := mustCreateUser(t, s, "Target")
+	ctx := audit.WithActor(context.Background(), audit.Actor{UserID: adminID, IP: "192.0.2.1", UserAgent: "test"})
+
+	if err := s.BanUser(ctx, userID, adminID, models.BanUserPayload{Reason: "fraud"}); err != nil {
+		t.Fatalf("BanUser: %v", err)
+	}
+	entries, err := s.GetAuditLog(ctx, models.AuditFilter{TargetID: userID, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetAuditLog: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("GetAuditLog = %d entries, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Action != "user.ban" || entry.ActorID == nil || *entry.ActorID != adminID || entry.TargetType != auditTargetUser {
+		t.Errorf("audit entry = %+v", entry)
+	}
+
+	// Failed actions leave no audit entry
+	_ = s.BanUser(ctx, uuid.NewString(), adminID, models.BanUserPayload{Reason: "fraud"})
+	name := "Ghost"
+	for _, id := range []string{uuid.NewString(), "not-a-uuid"} {
+		expectError(t, "audited UpdateUserDetail of "+id, s.UpdateUserDetail(ctx, id, models.UpdateUserPayload{FirstName: &name}), ErrUserNotFound)
+		expectError(t, "UpdateUserDetail of "+id, s.UpdateUserDetail(context.Background(), id, models.UpdateUserPayload{FirstName: &name}), ErrUserNotFound)
+	}
+	entries, err = s.GetAuditLog(ctx, models.AuditFilter{ActorID: adminID, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetAuditLog: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("GetAuditLog by actor = %d entries, want 1", len(entries))
+	}
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"masterdom/api/apperr"
+)
+
+// Domain errors returned by the store. Handlers match them with errors.Is and
+// the HTTP layer maps their kind to a status code.
+var (
+	ErrUserNotFound          = apperr.NotFound("user_not_found", "User not found")
+	ErrEmailTaken            = apperr.Conflict("email_taken", "Email is already registered")
+	ErrOfferNotFound         = apperr.NotFound("offer_not_found", "Offer not found")
+	ErrApplicationNotFound   = apperr.NotFound("application_not_found", "Application not found")
+	ErrApplicationExists     = apperr.Conflict("application_exists", "You have already responded to this offer")
+	ErrNotParticipant        = apperr.Forbidden("not_participant", "You are not a participant in this conversation")
+	ErrJobNotFound           = apperr.NotFound("job_not_found", "Job not found")
+	ErrJobTransition         = apperr.Conflict("job_transition", "Job cannot move to this status")
+	ErrJobNotCompleted       = apperr.Conflict("job_not_completed", "Only a completed job can be reviewed")
+	ErrReviewExists          = apperr.Conflict("review_exists", "You have already reviewed this job")
+	ErrAttributeNotFound     = apperr.NotFound("attribute_not_found", "Category attribute not found")
+	ErrAttributeKeyTaken     = apperr.Conflict("attribute_key_taken", "Attribute with this key already exists in the category")
+	ErrNotificationNotFound  = apperr.NotFound("notification_not_found", "Notification not found")
+	ErrEmailNotFound         = apperr.NotFound("email_not_found", "Email not found or already sent")
+	ErrAvatarNotFound        = apperr.NotFound("avatar_not_found", "Avatar not found")
+	ErrTaskNotFound          = apperr.NotFound("task_not_found", "Task not found")
+	ErrDeadTaskNotFound      = apperr.NotFound("dead_task_not_found", "Dead task not found")
+	ErrAPIKeyNotFound        = apperr.NotFound("api_key_not_found", "API key not found")
+	ErrAPIKeyPrefixTaken     = apperr.Conflict("api_key_prefix_taken", "API key prefix already in use")
+	ErrWebhookNotFound       = apperr.NotFound("webhook_not_found", "Webhook not found")
+	ErrLoginStateNotFound    = apperr.NotFound("login_state_not_found", "Login state not found or expired")
+	ErrDeletedRecordNotFound = apperr.NotFound("deleted_record_not_found", "Deleted record not found")
+	ErrRestoreConflict       = apperr.Conflict("restore_conflict", "Restored record conflicts with an existing one")
+	ErrAccountBanned         = apperr.Forbidden("account_banned", "Account is banned")
+	ErrAccountSuspended      = apperr.Forbidden("account_suspended", "Account is suspended")
+)
+
+// notFound replaces a missing row with the domain error e. An ID that is not a
+// valid UUID cannot match any row either, so it is reported the same way.
+// Other errors are returned unchanged.
+func notFound(err error, e *apperr.Error) error {
+	var pgErr *pgconn.PgError
+	if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == "22P02") {
+		return e
+	}
+	return err
+}
+
+// uniqueViolation replaces a violated unique constraint with the domain error e.
+func uniqueViolation(err error, e *apperr.Error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return e.Wrap(err)
+	}
+	return err
+}
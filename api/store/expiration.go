@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/models"
+)
+
+// --- Offer Expiration Implementations ---
+
+// RenewOffer extends the offer by the default TTL of its type, counted from
+// now, and republishes it if it was taken down because it expired. Offers
+// deactivated by an administrator before their expiry stay inactive.
+func (s *PostgresStore) RenewOffer(ctx context.Context, offerID string, ttl models.OfferTTL) (time.Time, error) {
+	var expiresAt time.Time
+	err := s.withAudit(ctx, "offer.renew", auditTargetOffer, &offerID, func(tx pgx.Tx) error {
+		var offerType string
+		err := tx.QueryRow(ctx,
+			"SELECT offer_type FROM offers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE",
+			offerID).Scan(&offerType)
+		if err != nil {
+			return notFound(fmt.Errorf("failed to get offer: %w", err), ErrOfferNotFound)
+		}
+
+		expiresAt = time.Now().Add(ttl.For(offerType))
+		_, err = tx.Exec(ctx, `
+			UPDATE offers
+			SET is_active = is_active OR (expires_at IS NOT NULL AND expires_at <= NOW()),
+				expires_at = $1, expiry_reminded_at = NULL
+			WHERE id = $2`,
+			expiresAt, offerID)
+		if err != nil {
+			return fmt.Errorf("failed to renew offer: %w", err)
+		}
+		return nil
+	})
+	return expiresAt, err
+}
+
+// ExpireOffers deactivates published offers whose expiry time has passed and
+// returns how many were taken down.
+func (s *PostgresStore) ExpireOffers(ctx context.Context) (int64, error) {
+	tag, err := s.dbpool.Exec(ctx, `
+		UPDATE offers SET is_active = FALSE
+		WHERE is_active = TRUE AND deleted_at IS NULL AND expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire offers: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// MarkExpiringOffers returns published offers expiring before expiresBefore
+// whose authors have not been reminded yet, marking them as reminded so each
+// offer is returned at most once per expiry period.
+func (s *PostgresStore) MarkExpiringOffers(ctx context.Context, expiresBefore time.Time) ([]models.ExpiringOffer, error) {
+	rows, err := s.dbpool.Query(ctx, `
+		UPDATE offers SET expiry_reminded_at = NOW()
+		WHERE is_active = TRUE AND deleted_at IS NULL AND expiry_reminded_at IS NULL
+		  AND expires_at > NOW() AND expires_at <= $1
+		RETURNING id, author_id, title, expires_at`, expiresBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark expiring offers: %w", err)
+	}
+	defer rows.Close()
+
+	offers := make([]models.ExpiringOffer, 0)
+	for rows.Next() {
+		var offer models.ExpiringOffer
+		if err := rows.Scan(&offer.ID, &offer.AuthorID, &offer.Title, &offer.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan expiring offer: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+	return offers, rows.Err()
+}
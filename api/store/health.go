@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	return s.dbpool.Ping(ctx)
+}
+
+// GetSchemaVersion returns the migration version recorded by migrate and
+// whether the last migration failed half-way.
+func (s *PostgresStore) GetSchemaVersion(ctx context.Context) (int, bool, error) {
+	var version int
+	var dirty bool
+	err := s.dbpool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, dirty, nil
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/apperr"
+	"masterdom/api/models"
+)
+
+var (
+	// ErrIdentityEmailRequired is returned when the provider did not share an email address.
+	ErrIdentityEmailRequired = apperr.Validation("identity_email_required", "Identity provider did not return an email")
+	// ErrIdentityEmailUnverified is returned for a new identity whose email the
+	// provider has not verified, so it can neither be linked nor claim the address.
+	ErrIdentityEmailUnverified = apperr.Conflict("identity_email_unverified", "Email is not verified by the identity provider")
+)
+
+// --- OpenID Connect Implementations ---
+
+// SaveOIDCLoginState stores a pending login. Abandoned logins are cleaned up
+// on the way so the table does not need a separate purge job.
+func (s *PostgresStore) SaveOIDCLoginState(ctx context.Context, state models.OIDCLoginState) error {
+	if _, err := s.dbpool.Exec(ctx, "DELETE FROM oidc_login_states WHERE expires_at < NOW()"); err != nil {
+		return fmt.Errorf("failed to purge expired login states: %w", err)
+	}
+	_, err := s.dbpool.Exec(ctx, `
+		INSERT INTO oidc_login_states (state, provider, nonce, code_verifier, expires_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		state.State, state.Provider, state.Nonce, state.CodeVerifier, state.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to save login state: %w", err)
+	}
+	return nil
+}
+
+// ConsumeOIDCLoginState deletes and returns a pending login, so each state can
+// be used only once. Expired states are reported as ErrLoginStateNotFound.
+func (s *PostgresStore) ConsumeOIDCLoginState(ctx context.Context, state string) (*models.OIDCLoginState, error) {
+	var st models.OIDCLoginState
+	err := s.dbpool.QueryRow(ctx, `
+		DELETE FROM oidc_login_states WHERE state = $1 AND expires_at > NOW()
+		RETURNING state, provider, nonce, code_verifier, expires_at`, state).Scan(
+		&st.State, &st.Provider, &st.Nonce, &st.CodeVerifier, &st.ExpiresAt)
+	if err != nil {
+		return nil, notFound(fmt.Errorf("failed to get login state: %w", err), ErrLoginStateNotFound)
+	}
+	return &st, nil
+}
+
+// ResolveOIDCUser returns the user linked to the identity. An unknown identity
+// needs an email verified by the provider: it is linked to the existing account
+// with that email (compared case-insensitively), otherwise a new passwordless
+// user is created from the claims. Creating accounts for unverified emails
+// would let anyone reserve an address before its owner signs up.
+func (s *PostgresStore) ResolveOIDCUser(ctx context.Context, identity models.OIDCIdentity) (*models.User, error) {
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	const userColumns = "u.id, u.email, COALESCE(u.password_hash, ''), u.role, u.status, u.suspended_until, u.sanction_reason"
+	scanUser := func(row pgx.Row) (*models.User, error) {
+		var user models.User
+		err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role, &user.Status, &user.SuspendedUntil, &user.Reason)
+		return &user, err
+	}
+
+	user, err := scanUser(tx.QueryRow(ctx, `
+		UPDATE user_identities i SET last_login_at = NOW(), email = COALESCE(NULLIF($3, ''), i.email)
+		FROM users u
+		WHERE u.id = i.user_id AND u.deleted_at IS NULL AND i.provider = $1 AND i.subject = $2
+		RETURNING `+userColumns, identity.Provider, identity.Subject, identity.Email))
+	if err == nil {
+		return user, tx.Commit(ctx)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to find linked user: %w", err)
+	}
+
+	if identity.Email == "" {
+		return nil, ErrIdentityEmailRequired
+	}
+	if !identity.EmailVerified {
+		return nil, ErrIdentityEmailUnverified
+	}
+	user, err = scanUser(tx.QueryRow(ctx,
+		"SELECT "+userColumns+" FROM users u WHERE lower(u.email) = lower($1) AND u.deleted_at IS NULL", identity.Email))
+	switch {
+	case err == nil:
+	case errors.Is(err, pgx.ErrNoRows):
+		userID, err := insertUser(ctx, tx, models.RegisterPayload{
+			Email:       identity.Email,
+			FirstName:   identity.FirstName,
+			LastName:    identity.LastName,
+			PhoneNumber: identity.PhoneNumber,
+			Locale:      identity.Locale,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		user = &models.User{ID: userID, Email: identity.Email, Role: "user",
+			AccountStatus: models.AccountStatus{Status: models.UserStatusActive}, Created: true}
+	default:
+		return nil, fmt.Errorf("failed to find user by email: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO user_identities (user_id, provider, subject, email) VALUES ($1, $2, $3, NULLIF($4, ''))",
+		user.ID, identity.Provider, identity.Subject, identity.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+	return user, tx.Commit(ctx)
+}
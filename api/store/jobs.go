@@ -0,0 +1,237 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/models"
+)
+
+// --- Job Implementations ---
+
+// jobParticipants returns the client and the master of a job on the offer:
+// the author of a request for service is the client, the author of a service
+// offer is the master.
+func jobParticipants(offerType, authorID, applicantID string) (clientID, masterID string) {
+	if offerType == "service_offer" {
+		return applicantID, authorID
+	}
+	return authorID, applicantID
+}
+
+// createJob starts a job for an accepted application unless the same client
+// and master already have an unfinished one on the offer. The applicant is
+// notified that the job is assigned.
+func createJob(ctx context.Context, tx pgx.Tx, offerID, offerType, offerTitle, authorID, applicantID string) error {
+	clientID, masterID := jobParticipants(offerType, authorID, applicantID)
+	var jobID string
+	err := tx.QueryRow(ctx, `
+		INSERT INTO jobs (offer_id, client_id, master_id, status)
+		SELECT $1, $2, $3, 'assigned'
+		WHERE NOT EXISTS (
+			SELECT 1 FROM jobs
+			WHERE offer_id = $1 AND client_id = $2 AND master_id = $3
+			  AND status IN ('assigned', 'in_progress')
+		)
+		RETURNING id`, offerID, clientID, masterID).Scan(&jobID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+	err = notify(ctx, tx, applicantID, models.NotificationJobStatusChanged, jobStatusPayload(jobID, offerID, offerTitle, models.JobStatusAssigned))
+	if err != nil {
+		return err
+	}
+	return publishEvent(ctx, tx, models.WebhookEventJobStatusChanged, jobStatusEvent(jobID, offerID, clientID, masterID, nil, models.JobStatusAssigned))
+}
+
+func jobStatusPayload(jobID, offerID, offerTitle, status string) map[string]interface{} {
+	return map[string]interface{}{
+		"jobId":      jobID,
+		"offerId":    offerID,
+		"offerTitle": offerTitle,
+		"status":     status,
+	}
+}
+
+// jobStatusEvent builds the job.status_changed webhook data; previousStatus
+// is nil for a job that has just been assigned.
+func jobStatusEvent(jobID, offerID, clientID, masterID string, previousStatus *string, status string) map[string]interface{} {
+	return map[string]interface{}{
+		"jobId":          jobID,
+		"offerId":        offerID,
+		"clientId":       clientID,
+		"masterId":       masterID,
+		"previousStatus": previousStatus,
+		"status":         status,
+	}
+}
+
+func (s *PostgresStore) GetUserJobs(ctx context.Context, userID string) ([]models.Job, error) {
+	rows, err := s.dbpool.Query(ctx, `
+		SELECT j.id, j.offer_id, o.title, j.client_id, COALESCE(j.master_id::text, ''), j.status,
+			   j.scheduled_for, j.started_at, j.completed_at, j.created_at, j.updated_at
+		FROM jobs j
+		JOIN offers o ON o.id = j.offer_id
+		WHERE j.client_id = $1 OR j.master_id = $1
+		ORDER BY j.created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]models.Job, 0)
+	for rows.Next() {
+		var j models.Job
+		if err := rows.Scan(&j.ID, &j.OfferID, &j.OfferTitle, &j.ClientID, &j.MasterID, &j.Status,
+			&j.ScheduledFor, &j.StartedAt, &j.CompletedAt, &j.CreatedAt, &j.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// UpdateJobStatus moves a job of the user along models.JobTransitions and
+// notifies the other participant. It reports whether the status changed: a job
+// already in the requested status is left as is. Jobs of other users are
+// reported as ErrJobNotFound.
+func (s *PostgresStore) UpdateJobStatus(ctx context.Context, jobID, userID, status string) (bool, error) {
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var offerID, offerTitle, clientID, masterID, previousStatus string
+	err = tx.QueryRow(ctx, `
+		SELECT j.offer_id, o.title, j.client_id, COALESCE(j.master_id::text, ''), j.status
+		FROM jobs j
+		JOIN offers o ON o.id = j.offer_id
+		WHERE j.id = $1 AND (j.client_id = $2 OR j.master_id = $2)
+		FOR UPDATE OF j`, jobID, userID).Scan(&offerID, &offerTitle, &clientID, &masterID, &previousStatus)
+	if err != nil {
+		return false, notFound(fmt.Errorf("failed to get job: %w", err), ErrJobNotFound)
+	}
+	if previousStatus == status {
+		return false, nil
+	}
+	if !models.CanTransitionJob(previousStatus, status) {
+		return false, ErrJobTransition
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE jobs
+		SET status = $1::request_status,
+			started_at = CASE WHEN $1::request_status = 'in_progress' THEN NOW() ELSE started_at END,
+			completed_at = CASE WHEN $1::request_status = 'completed' THEN NOW() ELSE completed_at END
+		WHERE id = $2`, status, jobID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	recipientID := clientID
+	if userID == clientID {
+		recipientID = masterID
+	}
+	if recipientID != "" {
+		err = notify(ctx, tx, recipientID, models.NotificationJobStatusChanged, jobStatusPayload(jobID, offerID, offerTitle, status))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = publishEvent(ctx, tx, models.WebhookEventJobStatusChanged, jobStatusEvent(jobID, offerID, clientID, masterID, &previousStatus, status))
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// CreateJobReview records the rating one participant of a completed job gives
+// the other, queues a recalculation of the average rating of the reviewed user
+// and notifies them. Each participant can review a job once.
+func (s *PostgresStore) CreateJobReview(ctx context.Context, jobID, authorID string, payload models.CreateReviewPayload) (string, error) {
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var offerID, offerTitle, clientID, masterID, status string
+	err = tx.QueryRow(ctx, `
+		SELECT j.offer_id, o.title, j.client_id, COALESCE(j.master_id::text, ''), j.status
+		FROM jobs j
+		JOIN offers o ON o.id = j.offer_id
+		WHERE j.id = $1 AND (j.client_id = $2 OR j.master_id = $2)`, jobID, authorID).Scan(&offerID, &offerTitle, &clientID, &masterID, &status)
+	if err != nil {
+		return "", notFound(fmt.Errorf("failed to get job: %w", err), ErrJobNotFound)
+	}
+	if status != models.JobStatusCompleted {
+		return "", ErrJobNotCompleted
+	}
+	subjectID := clientID
+	if authorID == clientID {
+		subjectID = masterID
+	}
+	if subjectID == "" {
+		return "", ErrUserNotFound
+	}
+
+	var reviewID string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO job_reviews (job_id, author_id, subject_id, rating, comment)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		jobID, authorID, subjectID, payload.Rating, payload.Comment).Scan(&reviewID)
+	if err != nil {
+		return "", uniqueViolation(fmt.Errorf("failed to create review: %w", err), ErrReviewExists)
+	}
+	_, err = enqueueTask(ctx, tx, models.NewTask{Kind: models.TaskKindRatingRecalculate, Payload: models.UserTask{UserID: subjectID}})
+	if err != nil {
+		return "", err
+	}
+
+	err = notify(ctx, tx, subjectID, models.NotificationReviewReceived, map[string]interface{}{
+		"reviewId":   reviewID,
+		"jobId":      jobID,
+		"offerId":    offerID,
+		"offerTitle": offerTitle,
+		"rating":     payload.Rating,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return reviewID, nil
+}
+
+// RecalculateUserRating refreshes the average rating of the user from the
+// reviews they received.
+func (s *PostgresStore) RecalculateUserRating(ctx context.Context, userID string) error {
+	return updateUserRating(ctx, s.dbpool, userID)
+}
+
+// updateUserRating sets the average rating of the user from the reviews they
+// received; a user without reviews gets 0.
+func updateUserRating(ctx context.Context, db querier, userID string) error {
+	_, err := db.Exec(ctx, `
+		UPDATE user_details
+		SET average_rating = COALESCE((SELECT ROUND(AVG(rating), 2) FROM job_reviews WHERE subject_id = $1), 0)
+		WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update user rating: %w", err)
+	}
+	return nil
+}
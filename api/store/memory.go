@@ -0,0 +1,1462 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"masterdom/api/audit"
+	"masterdom/api/db"
+	"masterdom/api/models"
+)
+
+// MemoryStore keeps all data in process memory. It follows the semantics of
+// PostgresStore (uniqueness, participant checks, cascades, audit entries and
+// queued side effects) so handlers can be tested without a database. It
+// starts empty: the default admin and seed data of the migrations are not
+// created.
+//
+// Every method holds a single lock for its whole duration and validates its
+// input before changing anything, so a failed call leaves no partial state,
+// like a rolled back transaction.
+type MemoryStore struct {
+	mu      sync.Mutex
+	lastNow time.Time
+
+	users         map[string]*memUser
+	avatars       map[string]*models.Avatar
+	sanctions     []*models.UserSanction
+	identities    []*memIdentity
+	loginStates   map[string]models.OIDCLoginState
+	offers        map[string]*memOffer
+	applications  map[string]*memApplication
+	jobs          map[string]*models.Job
+	reviews       []*models.Review
+	conversations map[string]*memConversation
+	messages      []*memMessage
+	categories    map[int]*memCategory
+	attributes    map[int]*memAttribute
+	notifications []*memNotification
+	preferences   map[string]map[string]bool
+	outbox        []*memEmail
+	tasks         map[int64]*models.Task
+	webhooks      map[int]*models.Webhook
+	deliveries    []*models.WebhookDelivery
+	apiKeys       map[string]*memAPIKey
+	auditLog      []*models.AuditEntry
+
+	// Counters for serial IDs
+	lastCategoryID  int
+	lastAttributeID int
+	lastWebhookID   int
+	lastTaskID      int64
+	lastDeliveryID  int64
+	lastEmailID     int64
+	lastAuditID     int64
+	lastIdentityID  int
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:         make(map[string]*memUser),
+		avatars:       make(map[string]*models.Avatar),
+		loginStates:   make(map[string]models.OIDCLoginState),
+		offers:        make(map[string]*memOffer),
+		applications:  make(map[string]*memApplication),
+		jobs:          make(map[string]*models.Job),
+		conversations: make(map[string]*memConversation),
+		categories:    make(map[int]*memCategory),
+		attributes:    make(map[int]*memAttribute),
+		preferences:   make(map[string]map[string]bool),
+		tasks:         make(map[int64]*models.Task),
+		webhooks:      make(map[int]*models.Webhook),
+		apiKeys:       make(map[string]*memAPIKey),
+	}
+}
+
+type memUser struct {
+	id             string
+	email          string
+	passwordHash   *string
+	role           string
+	status         string
+	suspendedUntil *time.Time
+	sanctionReason *string
+	sanctionedBy   *string
+	sanctionedAt   *time.Time
+	locale         string
+	createdAt      time.Time
+	updatedAt      time.Time
+	deletedAt      *time.Time
+
+	firstName         string
+	lastName          *string
+	phoneNumber       *string
+	bio               *string
+	yearsOfExperience *int
+	averageRating     float64
+}
+
+func (u *memUser) accountStatus() models.AccountStatus {
+	return models.AccountStatus{Status: u.status, SuspendedUntil: u.suspendedUntil, Reason: u.sanctionReason}
+}
+
+func (u *memUser) user() *models.User {
+	user := &models.User{ID: u.id, Email: u.email, Role: u.role, AccountStatus: u.accountStatus()}
+	if u.passwordHash != nil {
+		user.PasswordHash = *u.passwordHash
+	}
+	return user
+}
+
+func (u *memUser) detail() models.UserDetail {
+	firstName := u.firstName
+	rating := u.averageRating
+	return models.UserDetail{
+		ID: u.id, Email: u.email, Role: u.role, Status: u.status,
+		SuspendedUntil: u.suspendedUntil, SanctionReason: u.sanctionReason, Locale: u.locale,
+		CreatedAt: u.createdAt, UpdatedAt: u.updatedAt,
+		FirstName: &firstName, LastName: u.lastName, PhoneNumber: u.phoneNumber, Bio: u.bio,
+		YearsOfExperience: u.yearsOfExperience, AverageRating: &rating,
+	}
+}
+
+type memOffer struct {
+	id               string
+	authorID         string
+	offerType        string
+	title            string
+	description      string
+	categoryID       *int
+	isActive         bool
+	attributes       []byte
+	createdAt        time.Time
+	updatedAt        time.Time
+	deletedAt        *time.Time
+	expiresAt        *time.Time
+	expiryRemindedAt *time.Time
+}
+
+type memApplication struct {
+	id          string
+	offerID     string
+	applicantID string
+	message     string
+	status      string
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+type memConversation struct {
+	id           string
+	offerID      string
+	participants []string
+	createdAt    time.Time
+}
+
+func (c *memConversation) hasParticipant(userID string) bool {
+	for _, id := range c.participants {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+type memMessage struct {
+	id             string
+	conversationID string
+	senderID       string
+	content        string
+	createdAt      time.Time
+	isRead         bool
+}
+
+// now returns the current time, strictly increasing between calls so that
+// records created in a row keep a stable order.
+func (s *MemoryStore) now() time.Time {
+	t := time.Now()
+	if !t.After(s.lastNow) {
+		t = s.lastNow.Add(time.Microsecond)
+	}
+	s.lastNow = t
+	return t
+}
+
+// liveUser returns the user unless it is missing or soft-deleted.
+func (s *MemoryStore) liveUser(userID string) *memUser {
+	if u := s.users[userID]; u != nil && u.deletedAt == nil {
+		return u
+	}
+	return nil
+}
+
+// liveOffer returns the offer unless it is missing or soft-deleted.
+func (s *MemoryStore) liveOffer(offerID string) *memOffer {
+	if o := s.offers[offerID]; o != nil && o.deletedAt == nil {
+		return o
+	}
+	return nil
+}
+
+// openOffer returns the offer if GetOffers would list it at now: live, active,
+// not expired and published by a live author who is not blocked.
+func (s *MemoryStore) openOffer(offerID string, now time.Time) *memOffer {
+	o := s.liveOffer(offerID)
+	if o == nil || !o.isActive || o.expiresAt != nil && !o.expiresAt.After(now) {
+		return nil
+	}
+	if author := s.liveUser(o.authorID); author == nil || author.accountStatus().IsBlocked(now) {
+		return nil
+	}
+	return o
+}
+
+func (s *MemoryStore) userFirstName(userID string) string {
+	if u := s.users[userID]; u != nil {
+		return u.firstName
+	}
+	return ""
+}
+
+// jsonObject encodes value as JSON, defaulting to an empty object for nil.
+func jsonObject(value interface{}) ([]byte, error) {
+	if value == nil {
+		return []byte("{}"), nil
+	}
+	if m, ok := value.(map[string]interface{}); ok && m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(value)
+}
+
+func decodeObject(data []byte) map[string]interface{} {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil || m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+// normalizeJSON round-trips value through JSON so it compares the same way
+// as a snapshot decoded from jsonb.
+func normalizeJSON(value map[string]interface{}) map[string]interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return decodeObject(data)
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+func cloneInt(i *int) *int {
+	if i == nil {
+		return nil
+	}
+	c := *i
+	return &c
+}
+
+// page applies LIMIT/OFFSET to n rows and returns the bounds of the page.
+func page(n, limit, offset int) (int, int) {
+	start := min(max(offset, 0), n)
+	end := min(start+max(limit, 0), n)
+	return start, end
+}
+
+// --- Audit ---
+
+// withAudit mirrors PostgresStore.withAudit. The caller holds s.mu; fn must
+// return before changing anything when it fails.
+func (s *MemoryStore) withAudit(ctx context.Context, action, targetType string, targetID *string, fn func() error) error {
+	actor, audited := audit.ActorFromContext(ctx)
+
+	var before map[string]interface{}
+	if audited && *targetID != "" {
+		if before = s.auditSnapshot(targetType, *targetID); before == nil {
+			return auditNotFound[targetType]
+		}
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	if audited {
+		after := s.auditSnapshot(targetType, *targetID)
+		beforeDiff, afterDiff := auditDiff(before, after)
+		entry := &models.AuditEntry{
+			Action:     action,
+			TargetType: targetType,
+			TargetID:   *targetID,
+			Before:     auditJSON(beforeDiff),
+			After:      auditJSON(afterDiff),
+			IP:         &actor.IP,
+			UserAgent:  &actor.UserAgent,
+			CreatedAt:  s.now(),
+		}
+		if actor.UserID != "" {
+			entry.ActorID = &actor.UserID
+		}
+		s.lastAuditID++
+		entry.ID = s.lastAuditID
+		s.auditLog = append(s.auditLog, entry)
+	}
+	return nil
+}
+
+func auditJSON(snapshot map[string]interface{}) json.RawMessage {
+	if snapshot == nil {
+		return nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// auditSnapshot returns the same keys as the auditSnapshotQueries of PostgresStore.
+func (s *MemoryStore) auditSnapshot(targetType, targetID string) map[string]interface{} {
+	var snapshot map[string]interface{}
+	switch targetType {
+	case auditTargetUser:
+		u := s.users[targetID]
+		if u == nil {
+			return nil
+		}
+		snapshot = map[string]interface{}{
+			"id": u.id, "email": u.email, "role": u.role, "status": u.status,
+			"suspended_until": u.suspendedUntil, "sanction_reason": u.sanctionReason,
+			"sanctioned_by": u.sanctionedBy, "sanctioned_at": u.sanctionedAt,
+			"locale": u.locale, "created_at": u.createdAt, "updated_at": u.updatedAt, "deleted_at": u.deletedAt,
+			"first_name": u.firstName, "last_name": u.lastName, "phone_number": u.phoneNumber, "bio": u.bio,
+			"years_of_experience": u.yearsOfExperience, "average_rating": u.averageRating,
+		}
+	case auditTargetOffer:
+		o := s.offers[targetID]
+		if o == nil {
+			return nil
+		}
+		snapshot = map[string]interface{}{
+			"id": o.id, "author_id": o.authorID, "offer_type": o.offerType, "title": o.title,
+			"description": o.description, "category_id": o.categoryID, "is_active": o.isActive,
+			"status": "open", "attributes": json.RawMessage(o.attributes),
+			"created_at": o.createdAt, "updated_at": o.updatedAt, "deleted_at": o.deletedAt,
+			"expires_at": o.expiresAt, "expiry_reminded_at": o.expiryRemindedAt,
+		}
+	case auditTargetCategory:
+		id, _ := strconv.Atoi(targetID)
+		c := s.categories[id]
+		if c == nil {
+			return nil
+		}
+		var translations map[string]models.CategoryTranslation
+		if len(c.translations) > 0 {
+			translations = c.translations
+		}
+		snapshot = map[string]interface{}{
+			"id": c.id, "name": c.name, "description": c.description, "parent_id": c.parentID,
+			"slug": c.slug, "sort_order": c.sortOrder, "icon": c.icon, "deleted_at": c.deletedAt,
+			"translations": translations,
+		}
+	case auditTargetAttribute:
+		id, _ := strconv.Atoi(targetID)
+		a := s.attributes[id]
+		if a == nil {
+			return nil
+		}
+		snapshot = map[string]interface{}{
+			"id": a.id, "category_id": a.categoryID, "key": a.key, "label": a.label, "type": a.attrType,
+			"required": a.required, "options": a.options, "sort_order": a.sortOrder,
+			"created_at": a.createdAt, "updated_at": a.updatedAt,
+		}
+	case auditTargetTask:
+		id, _ := strconv.ParseInt(targetID, 10, 64)
+		t := s.tasks[id]
+		if t == nil {
+			return nil
+		}
+		snapshot = map[string]interface{}{
+			"id": t.ID, "kind": t.Kind, "status": t.Status, "attempts": t.Attempts, "max_attempts": t.MaxAttempts,
+			"run_at": t.RunAt, "locked_by": t.LockedBy, "locked_at": t.LockedAt, "last_error": t.LastError,
+			"created_at": t.CreatedAt, "updated_at": t.UpdatedAt, "finished_at": t.FinishedAt,
+		}
+	case auditTargetWebhook:
+		id, _ := strconv.Atoi(targetID)
+		w := s.webhooks[id]
+		if w == nil {
+			return nil
+		}
+		snapshot = map[string]interface{}{
+			"id": w.ID, "url": w.URL, "description": w.Description, "events": w.Events, "is_active": w.IsActive,
+			"created_by": w.CreatedBy, "created_at": w.CreatedAt, "updated_at": w.UpdatedAt,
+		}
+	case auditTargetAPIKey:
+		k := s.apiKeys[targetID]
+		if k == nil {
+			return nil
+		}
+		snapshot = map[string]interface{}{
+			"id": k.ID, "user_id": k.UserID, "name": k.Name, "prefix": k.Prefix, "scopes": k.Scopes,
+			"expires_at": k.ExpiresAt, "revoked_at": k.RevokedAt, "created_at": k.CreatedAt,
+		}
+	default:
+		return nil
+	}
+	return normalizeJSON(snapshot)
+}
+
+func (s *MemoryStore) GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]models.AuditEntry, 0)
+	for _, e := range s.auditLog {
+		if filter.ActorID != "" && (e.ActorID == nil || *e.ActorID != filter.ActorID) ||
+			filter.Action != "" && e.Action != filter.Action ||
+			filter.TargetType != "" && e.TargetType != filter.TargetType ||
+			filter.TargetID != "" && e.TargetID != filter.TargetID ||
+			filter.From != nil && e.CreatedAt.Before(*filter.From) ||
+			filter.To != nil && !e.CreatedAt.Before(*filter.To) {
+			continue
+		}
+		entry := *e
+		if e.ActorID != nil {
+			if u := s.users[*e.ActorID]; u != nil {
+				entry.ActorEmail = &u.email
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		}
+		return entries[i].ID > entries[j].ID
+	})
+	if filter.Limit > 0 {
+		start, end := page(len(entries), filter.Limit, filter.Offset)
+		entries = entries[start:end]
+	}
+	return entries, nil
+}
+
+// --- Health ---
+
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// GetSchemaVersion reports the version the code expects, as there is no
+// schema to migrate.
+func (s *MemoryStore) GetSchemaVersion(ctx context.Context) (int, bool, error) {
+	return db.SchemaVersion(), false, nil
+}
+
+// --- Users ---
+
+func (s *MemoryStore) CreateUser(ctx context.Context, payload models.RegisterPayload, hashedPassword string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insertUser(payload, &hashedPassword)
+}
+
+// insertUser mirrors the Postgres insertUser, including the registration event.
+func (s *MemoryStore) insertUser(payload models.RegisterPayload, hashedPassword *string) (string, error) {
+	for _, u := range s.users {
+		if u.deletedAt == nil && u.email == payload.Email {
+			return "", ErrEmailTaken
+		}
+	}
+	locale := payload.Locale
+	if locale == "" {
+		locale = "ru"
+	}
+
+	now := s.now()
+	u := &memUser{
+		id:           uuid.NewString(),
+		email:        payload.Email,
+		passwordHash: cloneString(hashedPassword),
+		role:         "user",
+		status:       models.UserStatusActive,
+		locale:       locale,
+		createdAt:    now,
+		updatedAt:    now,
+		firstName:    payload.FirstName,
+		lastName:     cloneString(payload.LastName),
+		phoneNumber:  cloneString(payload.PhoneNumber),
+	}
+	s.users[u.id] = u
+
+	err := s.publishEvent(models.WebhookEventUserRegistered, map[string]interface{}{
+		"userId":    u.id,
+		"email":     payload.Email,
+		"firstName": payload.FirstName,
+		"lastName":  payload.LastName,
+	})
+	if err != nil {
+		return "", err
+	}
+	return u.id, nil
+}
+
+func (s *MemoryStore) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.deletedAt == nil && u.email == email {
+			return u.user(), nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (s *MemoryStore) GetAllUsers(ctx context.Context) ([]models.UserDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make([]models.UserDetail, 0)
+	for _, u := range s.users {
+		if u.deletedAt == nil {
+			users = append(users, u.detail())
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].CreatedAt.After(users[j].CreatedAt) })
+	return users, nil
+}
+
+func (s *MemoryStore) GetUserDetailByID(ctx context.Context, userID string) (*models.UserDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.liveUser(userID)
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	detail := u.detail()
+	return &detail, nil
+}
+
+// UpdateUserDetail, like the Postgres version, updates soft-deleted users too
+// and silently ignores unknown IDs.
+func (s *MemoryStore) UpdateUserDetail(ctx context.Context, userID string, payload models.UpdateUserPayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withAudit(ctx, "user.update", auditTargetUser, &userID, func() error {
+		u := s.liveUser(userID)
+		if u == nil {
+			return ErrUserNotFound
+		}
+		if payload.IsAdmin != nil || payload.Locale != nil {
+			u.updatedAt = s.now()
+		}
+		if payload.IsAdmin != nil {
+			u.role = "user"
+			if *payload.IsAdmin {
+				u.role = "admin"
+			}
+		}
+		if payload.Locale != nil {
+			u.locale = *payload.Locale
+		}
+		if payload.FirstName != nil {
+			u.firstName = *payload.FirstName
+		}
+		if payload.LastName != nil {
+			u.lastName = cloneString(payload.LastName)
+		}
+		if payload.PhoneNumber != nil {
+			u.phoneNumber = cloneString(payload.PhoneNumber)
+		}
+		if payload.Bio != nil {
+			u.bio = cloneString(payload.Bio)
+		}
+		if payload.YearsOfExperience != nil {
+			u.yearsOfExperience = cloneInt(payload.YearsOfExperience)
+		}
+		return nil
+	})
+}
+
+func (s *MemoryStore) DeleteUser(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withAudit(ctx, "user.delete", auditTargetUser, &userID, func() error {
+		u := s.liveUser(userID)
+		if u == nil {
+			return ErrUserNotFound
+		}
+		now := s.now()
+		u.deletedAt = &now
+		return nil
+	})
+}
+
+func (s *MemoryStore) IsUserAdmin(ctx context.Context, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.liveUser(userID)
+	return u != nil && u.role == "admin", nil
+}
+
+func (s *MemoryStore) GetUserEmailByID(ctx context.Context, userID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.users[userID]
+	if u == nil {
+		return "", ErrUserNotFound
+	}
+	return u.email, nil
+}
+
+func (s *MemoryStore) SetUserPassword(ctx context.Context, userID, hashedPassword string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withAudit(ctx, "user.reset_password", auditTargetUser, &userID, func() error {
+		u := s.liveUser(userID)
+		if u == nil {
+			return ErrUserNotFound
+		}
+		u.passwordHash = &hashedPassword
+		u.updatedAt = s.now()
+		return nil
+	})
+}
+
+// --- Offers ---
+
+func (s *MemoryStore) CreateOffer(ctx context.Context, userID string, payload models.CreateOfferPayload) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.users[userID] == nil {
+		return "", fmt.Errorf("failed to create offer: author %s does not exist", userID)
+	}
+	if payload.CategoryID != nil && s.categories[*payload.CategoryID] == nil {
+		return "", fmt.Errorf("failed to create offer: category %d does not exist", *payload.CategoryID)
+	}
+	attributes, err := jsonObject(payload.Attributes)
+	if err != nil {
+		return "", fmt.Errorf("failed to create offer: %w", err)
+	}
+
+	now := s.now()
+	o := &memOffer{
+		id:          uuid.NewString(),
+		authorID:    userID,
+		offerType:   payload.OfferType,
+		title:       payload.Title,
+		description: payload.Description,
+		categoryID:  cloneInt(payload.CategoryID),
+		isActive:    true,
+		attributes:  attributes,
+		createdAt:   now,
+		updatedAt:   now,
+		expiresAt:   cloneTime(payload.ExpiresAt),
+	}
+	s.offers[o.id] = o
+
+	err = s.publishEvent(models.WebhookEventOfferCreated, map[string]interface{}{
+		"offerId":     o.id,
+		"authorId":    userID,
+		"offerType":   payload.OfferType,
+		"title":       payload.Title,
+		"description": payload.Description,
+		"categoryId":  payload.CategoryID,
+		"attributes":  payload.Attributes,
+		"expiresAt":   payload.ExpiresAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return o.id, nil
+}
+
+func (s *MemoryStore) GetOffers(ctx context.Context, filter models.OfferFilter) ([]models.OfferResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var categories map[int]bool
+	if filter.Category != "" {
+		categories = make(map[int]bool)
+		for _, id := range s.categoryTree(filter.Category) {
+			categories[id] = true
+		}
+	}
+	search := strings.ToLower(filter.Search)
+
+	now := time.Now()
+	offers := make([]models.OfferResponse, 0)
+	for _, o := range s.offers {
+		if s.openOffer(o.id, now) == nil {
+			continue
+		}
+		if filter.OfferType != "" && o.offerType != filter.OfferType {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(o.title), search) && !strings.Contains(strings.ToLower(o.description), search) {
+			continue
+		}
+		if categories != nil && (o.categoryID == nil || !categories[*o.categoryID]) {
+			continue
+		}
+		matched, err := matchAttributes(o.attributes, filter.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		offer := models.OfferResponse{
+			ID: o.id, Title: o.title, Description: o.description, OfferType: o.offerType, CreatedAt: o.createdAt,
+			AuthorID: o.authorID, AuthorFirstName: s.userFirstName(o.authorID), CategoryID: cloneInt(o.categoryID),
+			Attributes: decodeObject(o.attributes), ExpiresAt: cloneTime(o.expiresAt),
+		}
+		if filter.ViewerID != nil {
+			offer.HasResponded = s.findApplication(o.id, *filter.ViewerID) != nil
+		}
+		offers = append(offers, offer)
+	}
+	sort.Slice(offers, func(i, j int) bool { return offers[i].CreatedAt.After(offers[j].CreatedAt) })
+	return offers, nil
+}
+
+// matchAttributes applies the attribute filters the way the Postgres query
+// does: eq compares the text rendering of the value, min and max only match numbers.
+func matchAttributes(data []byte, filters []models.AttributeFilter) (bool, error) {
+	if len(filters) == 0 {
+		return true, nil
+	}
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return false, fmt.Errorf("failed to decode offer attributes: %w", err)
+	}
+	for _, f := range filters {
+		raw, ok := attributes[f.Key]
+		if !ok || string(raw) == "null" {
+			return false, nil
+		}
+		switch f.Op {
+		case models.AttributeFilterEq:
+			text := string(raw)
+			if raw[0] == '"' {
+				if err := json.Unmarshal(raw, &text); err != nil {
+					return false, fmt.Errorf("failed to decode offer attribute: %w", err)
+				}
+			}
+			if text != f.Value {
+				return false, nil
+			}
+		case models.AttributeFilterMin, models.AttributeFilterMax:
+			bound, err := strconv.ParseFloat(f.Value, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid numeric filter value %q: %w", f.Value, err)
+			}
+			var value float64
+			if raw[0] == '"' || raw[0] == '{' || raw[0] == '[' || raw[0] == 't' || raw[0] == 'f' {
+				return false, nil
+			}
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return false, nil
+			}
+			if f.Op == models.AttributeFilterMin && value < bound || f.Op == models.AttributeFilterMax && value > bound {
+				return false, nil
+			}
+		default:
+			return false, fmt.Errorf("unsupported attribute filter %q", f.Op)
+		}
+	}
+	return true, nil
+}
+
+func (s *MemoryStore) GetAllOffersForAdmin(ctx context.Context) ([]models.AdminOfferResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offers := make([]models.AdminOfferResponse, 0)
+	for _, o := range s.offers {
+		if o.deletedAt != nil {
+			continue
+		}
+		author := s.users[o.authorID]
+		offers = append(offers, models.AdminOfferResponse{
+			ID: o.id, Title: o.title, Description: o.description, OfferType: o.offerType, IsActive: o.isActive,
+			ExpiresAt: cloneTime(o.expiresAt), CreatedAt: o.createdAt, UpdatedAt: o.updatedAt,
+			AuthorID: o.authorID, AuthorFirstName: author.firstName, AuthorEmail: author.email,
+		})
+	}
+	sort.Slice(offers, func(i, j int) bool { return offers[i].CreatedAt.After(offers[j].CreatedAt) })
+	return offers, nil
+}
+
+func (s *MemoryStore) UpdateOfferStatus(ctx context.Context, offerID string, payload models.UpdateOfferPayload) error {
+	if payload.IsActive == nil {
+		return fmt.Errorf("is_active must be provided")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withAudit(ctx, "offer.update_status", auditTargetOffer, &offerID, func() error {
+		o := s.liveOffer(offerID)
+		if o == nil {
+			return ErrOfferNotFound
+		}
+		o.isActive = *payload.IsActive
+		o.updatedAt = s.now()
+		return nil
+	})
+}
+
+func (s *MemoryStore) DeleteOffer(ctx context.Context, offerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withAudit(ctx, "offer.delete", auditTargetOffer, &offerID, func() error {
+		o := s.liveOffer(offerID)
+		if o == nil {
+			return ErrOfferNotFound
+		}
+		now := s.now()
+		o.deletedAt = &now
+		o.updatedAt = now
+		return nil
+	})
+}
+
+func (s *MemoryStore) RenewOffer(ctx context.Context, offerID string, ttl models.OfferTTL) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expiresAt time.Time
+	err := s.withAudit(ctx, "offer.renew", auditTargetOffer, &offerID, func() error {
+		o := s.liveOffer(offerID)
+		if o == nil {
+			return ErrOfferNotFound
+		}
+		now := s.now()
+		expiresAt = now.Add(ttl.For(o.offerType))
+		o.isActive = o.isActive || (o.expiresAt != nil && !o.expiresAt.After(now))
+		o.expiresAt = &expiresAt
+		o.expiryRemindedAt = nil
+		o.updatedAt = now
+		return nil
+	})
+	return expiresAt, err
+}
+
+func (s *MemoryStore) ExpireOffers(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var expired int64
+	for _, o := range s.offers {
+		if o.isActive && o.deletedAt == nil && o.expiresAt != nil && !o.expiresAt.After(now) {
+			o.isActive = false
+			o.updatedAt = now
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+func (s *MemoryStore) MarkExpiringOffers(ctx context.Context, expiresBefore time.Time) ([]models.ExpiringOffer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	offers := make([]models.ExpiringOffer, 0)
+	for _, o := range s.offers {
+		if o.isActive && o.deletedAt == nil && o.expiryRemindedAt == nil && o.expiresAt != nil &&
+			o.expiresAt.After(now) && !o.expiresAt.After(expiresBefore) {
+			o.expiryRemindedAt = &now
+			o.updatedAt = now
+			offers = append(offers, models.ExpiringOffer{ID: o.id, AuthorID: o.authorID, Title: o.title, ExpiresAt: *o.expiresAt})
+		}
+	}
+	sort.Slice(offers, func(i, j int) bool { return offers[i].ExpiresAt.Before(offers[j].ExpiresAt) })
+	return offers, nil
+}
+
+// DeactivateOffers audits each offer on its own, like the Postgres version.
+func (s *MemoryStore) DeactivateOffers(ctx context.Context, filter models.OfferDeactivationFilter) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []*memOffer
+	for _, o := range s.offers {
+		if !o.isActive || o.deletedAt != nil ||
+			filter.AuthorID != nil && o.authorID != *filter.AuthorID ||
+			filter.CategoryID != nil && (o.categoryID == nil || *o.categoryID != *filter.CategoryID) ||
+			filter.CreatedBefore != nil && !o.createdAt.Before(*filter.CreatedBefore) {
+			continue
+		}
+		matched = append(matched, o)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].createdAt.Before(matched[j].createdAt) })
+
+	deactivated := make([]string, 0, len(matched))
+	for _, o := range matched {
+		err := s.withAudit(ctx, "offer.update_status", auditTargetOffer, &o.id, func() error {
+			o.isActive = false
+			o.updatedAt = s.now()
+			return nil
+		})
+		if err != nil {
+			return deactivated, fmt.Errorf("failed to deactivate offer %s: %w", o.id, err)
+		}
+		deactivated = append(deactivated, o.id)
+	}
+	return deactivated, nil
+}
+
+// --- Applications ---
+
+func (s *MemoryStore) findApplication(offerID, applicantID string) *memApplication {
+	for _, a := range s.applications {
+		if a.offerID == offerID && a.applicantID == applicantID {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) CreateOfferResponse(ctx context.Context, response *models.OfferApplication) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := s.openOffer(response.OfferID, time.Now())
+	if o == nil {
+		return "", ErrOfferNotFound
+	}
+	if s.findApplication(response.OfferID, response.ApplicantID) != nil {
+		return "", ErrApplicationExists
+	}
+	if s.users[response.ApplicantID] == nil {
+		return "", fmt.Errorf("failed to create offer response: applicant %s does not exist", response.ApplicantID)
+	}
+
+	now := s.now()
+	a := &memApplication{
+		id:          uuid.NewString(),
+		offerID:     response.OfferID,
+		applicantID: response.ApplicantID,
+		message:     response.Message,
+		status:      "pending",
+		createdAt:   now,
+		updatedAt:   now,
+	}
+	s.applications[a.id] = a
+
+	if o.authorID != response.ApplicantID {
+		err := s.notify(o.authorID, models.NotificationApplicationCreated, map[string]interface{}{
+			"offerId":            response.OfferID,
+			"offerTitle":         o.title,
+			"applicationId":      a.id,
+			"applicantId":        response.ApplicantID,
+			"applicantFirstName": s.userFirstName(response.ApplicantID),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err := s.publishEvent(models.WebhookEventApplicationCreated, map[string]interface{}{
+		"applicationId": a.id,
+		"offerId":       response.OfferID,
+		"applicantId":   response.ApplicantID,
+		"message":       response.Message,
+	})
+	if err != nil {
+		return "", err
+	}
+	return a.id, nil
+}
+
+func (s *MemoryStore) UpdateApplicationStatus(ctx context.Context, offerID, applicationID, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.applications[applicationID]
+	if a == nil || a.offerID != offerID {
+		return ErrApplicationNotFound
+	}
+	o := s.liveOffer(offerID)
+	if o == nil {
+		return ErrApplicationNotFound
+	}
+	if a.status == status {
+		return nil
+	}
+
+	a.status = status
+	a.updatedAt = s.now()
+
+	eventType := models.NotificationApplicationAccepted
+	if status == "rejected" {
+		eventType = models.NotificationApplicationRejected
+	}
+	err := s.notify(a.applicantID, eventType, map[string]interface{}{
+		"offerId":       offerID,
+		"offerTitle":    o.title,
+		"applicationId": applicationID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if status == "accepted" {
+		return s.createJob(o, a.applicantID)
+	}
+	return nil
+}
+
+// createJob mirrors the Postgres createJob.
+func (s *MemoryStore) createJob(o *memOffer, applicantID string) error {
+	clientID, masterID := jobParticipants(o.offerType, o.authorID, applicantID)
+	for _, j := range s.jobs {
+		if j.OfferID == o.id && j.ClientID == clientID && j.MasterID == masterID &&
+			(j.Status == models.JobStatusAssigned || j.Status == models.JobStatusInProgress) {
+			return nil
+		}
+	}
+
+	now := s.now()
+	j := &models.Job{
+		ID: uuid.NewString(), OfferID: o.id, ClientID: clientID, MasterID: masterID,
+		Status: models.JobStatusAssigned, CreatedAt: now, UpdatedAt: now,
+	}
+	s.jobs[j.ID] = j
+	if err := s.notify(applicantID, models.NotificationJobStatusChanged, jobStatusPayload(j.ID, o.id, o.title, j.Status)); err != nil {
+		return err
+	}
+	return s.publishEvent(models.WebhookEventJobStatusChanged, jobStatusEvent(j.ID, o.id, clientID, masterID, nil, j.Status))
+}
+
+func (s *MemoryStore) GetUserJobs(ctx context.Context, userID string) ([]models.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]models.Job, 0)
+	for _, j := range s.jobs {
+		if j.ClientID != userID && j.MasterID != userID {
+			continue
+		}
+		job := *j
+		job.ScheduledFor = cloneTime(j.ScheduledFor)
+		job.StartedAt = cloneTime(j.StartedAt)
+		job.CompletedAt = cloneTime(j.CompletedAt)
+		if o := s.offers[j.OfferID]; o != nil {
+			job.OfferTitle = o.title
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].CreatedAt.After(jobs[k].CreatedAt) })
+	return jobs, nil
+}
+
+// CreateJobReview mirrors the Postgres CreateJobReview.
+func (s *MemoryStore) CreateJobReview(ctx context.Context, jobID, authorID string, payload models.CreateReviewPayload) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := s.jobs[jobID]
+	if j == nil || j.ClientID != authorID && j.MasterID != authorID {
+		return "", ErrJobNotFound
+	}
+	if j.Status != models.JobStatusCompleted {
+		return "", ErrJobNotCompleted
+	}
+	subjectID := j.ClientID
+	if authorID == j.ClientID {
+		subjectID = j.MasterID
+	}
+	if s.users[subjectID] == nil {
+		return "", ErrUserNotFound
+	}
+	for _, r := range s.reviews {
+		if r.JobID == jobID && r.AuthorID == authorID {
+			return "", ErrReviewExists
+		}
+	}
+
+	r := &models.Review{
+		ID: uuid.NewString(), JobID: jobID, AuthorID: authorID, SubjectID: subjectID,
+		Rating: payload.Rating, Comment: cloneString(payload.Comment), CreatedAt: s.now(),
+	}
+	s.reviews = append(s.reviews, r)
+	_, err := s.enqueueTask(models.NewTask{Kind: models.TaskKindRatingRecalculate, Payload: models.UserTask{UserID: subjectID}})
+	if err != nil {
+		return "", err
+	}
+
+	var title string
+	if o := s.offers[j.OfferID]; o != nil {
+		title = o.title
+	}
+	err = s.notify(subjectID, models.NotificationReviewReceived, map[string]interface{}{
+		"reviewId":   r.ID,
+		"jobId":      jobID,
+		"offerId":    j.OfferID,
+		"offerTitle": title,
+		"rating":     payload.Rating,
+	})
+	if err != nil {
+		return "", err
+	}
+	return r.ID, nil
+}
+
+func (s *MemoryStore) RecalculateUserRating(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updateUserRating(userID)
+	return nil
+}
+
+// updateUserRating mirrors the Postgres updateUserRating and reports whether
+// the rating changed.
+func (s *MemoryStore) updateUserRating(userID string) bool {
+	u := s.users[userID]
+	if u == nil {
+		return false
+	}
+	var sum, count int
+	for _, r := range s.reviews {
+		if r.SubjectID == userID {
+			sum += r.Rating
+			count++
+		}
+	}
+	rating := 0.0
+	if count > 0 {
+		rating = math.Round(float64(sum)/float64(count)*100) / 100
+	}
+	changed := u.averageRating != rating
+	u.averageRating = rating
+	return changed
+}
+
+func (s *MemoryStore) RecomputeRatings(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := 0
+	for id := range s.users {
+		if s.updateUserRating(id) {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+func (s *MemoryStore) UpdateJobStatus(ctx context.Context, jobID, userID, status string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := s.jobs[jobID]
+	if j == nil || j.ClientID != userID && j.MasterID != userID {
+		return false, ErrJobNotFound
+	}
+	if j.Status == status {
+		return false, nil
+	}
+	if !models.CanTransitionJob(j.Status, status) {
+		return false, ErrJobTransition
+	}
+
+	previousStatus := j.Status
+	now := s.now()
+	j.Status = status
+	j.UpdatedAt = now
+	switch status {
+	case models.JobStatusInProgress:
+		j.StartedAt = &now
+	case models.JobStatusCompleted:
+		j.CompletedAt = &now
+	}
+
+	recipientID := j.ClientID
+	if userID == j.ClientID {
+		recipientID = j.MasterID
+	}
+	if recipientID != "" {
+		var title string
+		if o := s.offers[j.OfferID]; o != nil {
+			title = o.title
+		}
+		if err := s.notify(recipientID, models.NotificationJobStatusChanged, jobStatusPayload(j.ID, j.OfferID, title, status)); err != nil {
+			return false, err
+		}
+	}
+	err := s.publishEvent(models.WebhookEventJobStatusChanged, jobStatusEvent(j.ID, j.OfferID, j.ClientID, j.MasterID, &previousStatus, status))
+	return err == nil, err
+}
+
+func (s *MemoryStore) GetOfferApplications(ctx context.Context, offerID string) ([]models.OfferApplication, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applications := make([]models.OfferApplication, 0)
+	for _, a := range s.applications {
+		applicant := s.liveUser(a.applicantID)
+		if a.offerID != offerID || applicant == nil {
+			continue
+		}
+		applications = append(applications, models.OfferApplication{
+			ID: a.id, OfferID: a.offerID, ApplicantID: a.applicantID, Message: a.message, Status: a.status,
+			CreatedAt: a.createdAt, ApplicantFirstName: applicant.firstName, ApplicantRating: float32(applicant.averageRating),
+		})
+	}
+	sort.Slice(applications, func(i, j int) bool { return applications[i].CreatedAt.After(applications[j].CreatedAt) })
+	return applications, nil
+}
+
+func (s *MemoryStore) GetOfferAuthor(ctx context.Context, offerID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := s.liveOffer(offerID)
+	if o == nil {
+		return "", ErrOfferNotFound
+	}
+	return o.authorID, nil
+}
+
+// --- Chat ---
+
+func (s *MemoryStore) InitiateChat(ctx context.Context, offerID, initiatorID, recipientID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var existing []*memConversation
+	for _, c := range s.conversations {
+		if c.offerID == offerID && c.hasParticipant(initiatorID) && c.hasParticipant(recipientID) {
+			existing = append(existing, c)
+		}
+	}
+	if len(existing) > 0 {
+		sort.Slice(existing, func(i, j int) bool { return existing[i].createdAt.Before(existing[j].createdAt) })
+		return existing[0].id, nil
+	}
+
+	if s.offers[offerID] == nil {
+		return "", fmt.Errorf("failed to create conversation: offer %s does not exist", offerID)
+	}
+	if initiatorID == recipientID || s.users[initiatorID] == nil || s.users[recipientID] == nil {
+		return "", fmt.Errorf("failed to add participants to conversation")
+	}
+
+	c := &memConversation{
+		id:           uuid.NewString(),
+		offerID:      offerID,
+		participants: []string{initiatorID, recipientID},
+		createdAt:    s.now(),
+	}
+	s.conversations[c.id] = c
+	return c.id, nil
+}
+
+// participantConversation returns the conversation if userID takes part in it.
+func (s *MemoryStore) participantConversation(conversationID, userID string) (*memConversation, error) {
+	c := s.conversations[conversationID]
+	if c == nil || !c.hasParticipant(userID) {
+		return nil, ErrNotParticipant
+	}
+	return c, nil
+}
+
+func (s *MemoryStore) GetChatDetails(ctx context.Context, conversationID, userID string) (*models.ChatDetailsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.participantConversation(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := models.ChatDetailsResponse{
+		ConversationID: c.id,
+		OfferID:        c.offerID,
+		OfferTitle:     s.offers[c.offerID].title,
+		Participants:   make([]models.UserDetail, 0, len(c.participants)),
+	}
+	for _, id := range c.participants {
+		detail := s.users[id].detail()
+		// The Postgres query does not select these columns
+		detail.SuspendedUntil, detail.SanctionReason, detail.Locale = nil, nil, ""
+		details.Participants = append(details.Participants, detail)
+	}
+	return &details, nil
+}
+
+func (s *MemoryStore) PostMessage(ctx context.Context, conversationID, senderID, content string) (*models.MessageResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.participantConversation(conversationID, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &memMessage{
+		id:             uuid.NewString(),
+		conversationID: conversationID,
+		senderID:       senderID,
+		content:        content,
+		createdAt:      s.now(),
+	}
+	s.messages = append(s.messages, m)
+	msg := s.messageResponse(m)
+
+	for _, recipientID := range c.participants {
+		if recipientID == senderID {
+			continue
+		}
+		err := s.notify(recipientID, models.NotificationMessageCreated, map[string]interface{}{
+			"conversationId":  conversationID,
+			"messageId":       msg.ID,
+			"senderId":        senderID,
+			"senderFirstName": msg.SenderFirstName,
+			"preview":         messagePreview(content),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.publishEvent(models.WebhookEventMessageCreated, map[string]interface{}{
+		"messageId":      msg.ID,
+		"conversationId": conversationID,
+		"senderId":       senderID,
+		"content":        content,
+		"createdAt":      msg.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (s *MemoryStore) messageResponse(m *memMessage) models.MessageResponse {
+	return models.MessageResponse{
+		ID: m.id, ConversationID: m.conversationID, SenderID: m.senderID, SenderFirstName: s.userFirstName(m.senderID),
+		Content: m.content, CreatedAt: m.createdAt, IsRead: m.isRead,
+	}
+}
+
+func (s *MemoryStore) GetMessages(ctx context.Context, conversationID, userID string) ([]models.MessageResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.participantConversation(conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	messages := make([]models.MessageResponse, 0)
+	for _, m := range s.messages {
+		if m.conversationID == conversationID {
+			messages = append(messages, s.messageResponse(m))
+		}
+	}
+	return messages, nil
+}
+
+func (s *MemoryStore) GetConversations(ctx context.Context, userID string) ([]models.ConversationPreview, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Messages are appended in creation order, so the last one wins
+	lastMessages := make(map[string]*memMessage)
+	for _, m := range s.messages {
+		lastMessages[m.conversationID] = m
+	}
+
+	conversations := make([]models.ConversationPreview, 0)
+	for _, c := range s.conversations {
+		if !c.hasParticipant(userID) {
+			continue
+		}
+		for _, otherID := range c.participants {
+			if otherID == userID {
+				continue
+			}
+			preview := models.ConversationPreview{
+				ConversationID:       c.id,
+				OtherParticipantID:   otherID,
+				OtherParticipantName: s.userFirstName(otherID),
+				LastMessageAt:        c.createdAt,
+				OfferTitle:           s.offers[c.offerID].title,
+			}
+			if m := lastMessages[c.id]; m != nil {
+				preview.LastMessageContent = m.content
+				preview.LastMessageAt = m.createdAt
+			}
+			conversations = append(conversations, preview)
+		}
+	}
+	sort.Slice(conversations, func(i, j int) bool { return conversations[i].LastMessageAt.After(conversations[j].LastMessageAt) })
+	return conversations, nil
+}
+
+// --- Stats ---
+
+func (s *MemoryStore) GetAdminStats(ctx context.Context) (*models.AdminStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stats models.AdminStats
+	for _, u := range s.users {
+		if u.deletedAt == nil {
+			stats.TotalUsers++
+		}
+	}
+	for _, o := range s.offers {
+		if o.deletedAt != nil {
+			continue
+		}
+		stats.TotalOffers++
+		switch o.offerType {
+		case "request_for_service":
+			stats.TotalServiceRequests++
+		case "service_offer":
+			stats.TotalServiceOffers++
+		}
+	}
+	stats.TotalJobs = len(s.jobs)
+	return &stats, nil
+}
@@ -0,0 +1,368 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"masterdom/api/models"
+)
+
+type memIdentity struct {
+	id          int
+	userID      string
+	provider    string
+	subject     string
+	email       *string
+	createdAt   time.Time
+	lastLoginAt time.Time
+}
+
+type memAPIKey struct {
+	models.APIKey
+	keyHash string
+}
+
+// --- User Sanctions ---
+
+func (s *MemoryStore) GetAccountStatus(ctx context.Context, userID string) (*models.AccountStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.liveUser(userID)
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	status := u.accountStatus()
+	status.SuspendedUntil = cloneTime(status.SuspendedUntil)
+	status.Reason = cloneString(status.Reason)
+	return &status, nil
+}
+
+func (s *MemoryStore) SuspendUser(ctx context.Context, userID, actorID string, payload models.SuspendUserPayload) error {
+	return s.applySanction(ctx, userID, actorID, "suspend", models.UserStatusSuspended, &payload.Reason, &payload.Until)
+}
+
+func (s *MemoryStore) BanUser(ctx context.Context, userID, actorID string, payload models.BanUserPayload) error {
+	return s.applySanction(ctx, userID, actorID, "ban", models.UserStatusBanned, &payload.Reason, nil)
+}
+
+func (s *MemoryStore) LiftUserSanction(ctx context.Context, userID, actorID string, payload models.LiftSanctionPayload) error {
+	var reason *string
+	if payload.Reason != "" {
+		reason = &payload.Reason
+	}
+	return s.applySanction(ctx, userID, actorID, "lift", models.UserStatusActive, reason, nil)
+}
+
+func (s *MemoryStore) applySanction(ctx context.Context, userID, actorID, action, status string, reason *string, until *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	auditAction := "user." + action
+	if action == "lift" {
+		auditAction = "user.lift_sanction"
+	}
+	return s.withAudit(ctx, auditAction, auditTargetUser, &userID, func() error {
+		u := s.liveUser(userID)
+		if u == nil {
+			return ErrUserNotFound
+		}
+
+		now := s.now()
+		u.status = status
+		if status == models.UserStatusActive {
+			u.suspendedUntil, u.sanctionReason, u.sanctionedBy, u.sanctionedAt = nil, nil, nil, nil
+		} else {
+			u.suspendedUntil = cloneTime(until)
+			u.sanctionReason = cloneString(reason)
+			u.sanctionedBy = &actorID
+			u.sanctionedAt = &now
+		}
+		u.updatedAt = now
+
+		s.sanctions = append(s.sanctions, &models.UserSanction{
+			ID:        uuid.NewString(),
+			UserID:    userID,
+			Action:    action,
+			Reason:    cloneString(reason),
+			ActorID:   &actorID,
+			ExpiresAt: cloneTime(until),
+			CreatedAt: now,
+		})
+		return nil
+	})
+}
+
+func (s *MemoryStore) GetUserSanctions(ctx context.Context, userID string) ([]models.UserSanction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sanctions := make([]models.UserSanction, 0)
+	for _, sanction := range s.sanctions {
+		if sanction.UserID == userID {
+			c := *sanction
+			c.Reason = cloneString(sanction.Reason)
+			c.ActorID = cloneString(sanction.ActorID)
+			c.ExpiresAt = cloneTime(sanction.ExpiresAt)
+			sanctions = append(sanctions, c)
+		}
+	}
+	sort.SliceStable(sanctions, func(i, j int) bool { return sanctions[i].CreatedAt.After(sanctions[j].CreatedAt) })
+	return sanctions, nil
+}
+
+// --- OpenID Connect ---
+
+func (s *MemoryStore) SaveOIDCLoginState(ctx context.Context, state models.OIDCLoginState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, st := range s.loginStates {
+		if st.ExpiresAt.Before(now) {
+			delete(s.loginStates, key)
+		}
+	}
+	if _, ok := s.loginStates[state.State]; ok {
+		return fmt.Errorf("failed to save login state: state %s already exists", state.State)
+	}
+	s.loginStates[state.State] = state
+	return nil
+}
+
+func (s *MemoryStore) ConsumeOIDCLoginState(ctx context.Context, state string) (*models.OIDCLoginState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.loginStates[state]
+	if !ok || !st.ExpiresAt.After(s.now()) {
+		return nil, ErrLoginStateNotFound
+	}
+	delete(s.loginStates, state)
+	return &st, nil
+}
+
+func (s *MemoryStore) ResolveOIDCUser(ctx context.Context, identity models.OIDCIdentity) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, i := range s.identities {
+		if i.provider != identity.Provider || i.subject != identity.Subject {
+			continue
+		}
+		u := s.liveUser(i.userID)
+		if u == nil {
+			break
+		}
+		i.lastLoginAt = s.now()
+		if identity.Email != "" {
+			email := identity.Email
+			i.email = &email
+		}
+		return u.user(), nil
+	}
+
+	if identity.Email == "" {
+		return nil, ErrIdentityEmailRequired
+	}
+	if !identity.EmailVerified {
+		return nil, ErrIdentityEmailUnverified
+	}
+	var user *models.User
+	for _, u := range s.users {
+		if u.deletedAt == nil && strings.EqualFold(u.email, identity.Email) {
+			user = u.user()
+			break
+		}
+	}
+	for _, i := range s.identities {
+		if i.provider == identity.Provider && i.subject == identity.Subject {
+			return nil, fmt.Errorf("failed to link identity: %s/%s is linked to another user", identity.Provider, identity.Subject)
+		}
+	}
+	if user == nil {
+		userID, err := s.insertUser(models.RegisterPayload{
+			Email:       identity.Email,
+			FirstName:   identity.FirstName,
+			LastName:    identity.LastName,
+			PhoneNumber: identity.PhoneNumber,
+			Locale:      identity.Locale,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		user = s.users[userID].user()
+		user.Created = true
+	}
+
+	now := s.now()
+	email := identity.Email
+	s.lastIdentityID++
+	s.identities = append(s.identities, &memIdentity{
+		id:          s.lastIdentityID,
+		userID:      user.ID,
+		provider:    identity.Provider,
+		subject:     identity.Subject,
+		email:       &email,
+		createdAt:   now,
+		lastLoginAt: now,
+	})
+	return user, nil
+}
+
+// --- API Keys ---
+
+func cloneAPIKey(k *memAPIKey) models.APIKey {
+	key := k.APIKey
+	key.Scopes = cloneStrings(k.Scopes)
+	key.ExpiresAt = cloneTime(k.ExpiresAt)
+	key.LastUsedAt = cloneTime(k.LastUsedAt)
+	key.LastUsedIP = cloneString(k.LastUsedIP)
+	key.RevokedAt = cloneTime(k.RevokedAt)
+	return key
+}
+
+func (s *MemoryStore) CreateAPIKey(ctx context.Context, userID string, payload models.CreateAPIKeyPayload, prefix, keyHash string) (*models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.users[userID] == nil {
+		return nil, fmt.Errorf("failed to create api key: user %s does not exist", userID)
+	}
+	for _, k := range s.apiKeys {
+		if k.Prefix == prefix {
+			return nil, ErrAPIKeyPrefixTaken
+		}
+	}
+
+	k := &memAPIKey{
+		APIKey: models.APIKey{
+			ID:        uuid.NewString(),
+			UserID:    userID,
+			Name:      payload.Name,
+			Prefix:    prefix,
+			Scopes:    cloneStrings(payload.Scopes),
+			ExpiresAt: cloneTime(payload.ExpiresAt),
+			CreatedAt: s.now(),
+		},
+		keyHash: keyHash,
+	}
+	s.apiKeys[k.ID] = k
+	key := cloneAPIKey(k)
+	return &key, nil
+}
+
+func (s *MemoryStore) GetAPIKeys(ctx context.Context, userID *string) ([]models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]models.APIKey, 0)
+	for _, k := range s.apiKeys {
+		if userID == nil || k.UserID == *userID {
+			keys = append(keys, cloneAPIKey(k))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (s *MemoryStore) RevokeAPIKey(ctx context.Context, keyID string, ownerID *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withAudit(ctx, "api_key.revoke", auditTargetAPIKey, &keyID, func() error {
+		k := s.apiKeys[keyID]
+		if k == nil || ownerID != nil && k.UserID != *ownerID {
+			return ErrAPIKeyNotFound
+		}
+		if k.RevokedAt == nil {
+			now := s.now()
+			k.RevokedAt = &now
+		}
+		return nil
+	})
+}
+
+func (s *MemoryStore) GetAPIKeyCredentials(ctx context.Context, prefix string) (*models.APIKeyCredentials, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, k := range s.apiKeys {
+		if k.Prefix != prefix {
+			continue
+		}
+		u := s.liveUser(k.UserID)
+		if u == nil {
+			break
+		}
+		return &models.APIKeyCredentials{
+			ID:        k.ID,
+			UserID:    k.UserID,
+			KeyHash:   k.keyHash,
+			Scopes:    cloneStrings(k.Scopes),
+			ExpiresAt: cloneTime(k.ExpiresAt),
+			RevokedAt: cloneTime(k.RevokedAt),
+			IsAdmin:   u.role == "admin",
+		}, nil
+	}
+	return nil, fmt.Errorf("failed to get api key: no key with prefix %s", prefix)
+}
+
+func (s *MemoryStore) TouchAPIKey(ctx context.Context, keyID, ip string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := s.apiKeys[keyID]
+	if k == nil {
+		return nil
+	}
+	now := s.now()
+	if k.LastUsedAt == nil || k.LastUsedAt.Before(now.Add(-time.Minute)) || k.LastUsedIP == nil || *k.LastUsedIP != ip {
+		k.LastUsedAt = &now
+		k.LastUsedIP = &ip
+	}
+	return nil
+}
+
+// --- Avatars ---
+
+func (s *MemoryStore) SetUserAvatar(ctx context.Context, userID, contentType string, image []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.liveUser(userID) == nil {
+		return ErrUserNotFound
+	}
+	s.avatars[userID] = &models.Avatar{ContentType: contentType, Image: bytes.Clone(image), UpdatedAt: s.now()}
+	_, err := s.enqueueTask(models.NewTask{Kind: models.TaskKindAvatarThumbnail, Payload: models.UserTask{UserID: userID}})
+	return err
+}
+
+func (s *MemoryStore) GetUserAvatar(ctx context.Context, userID string) (*models.Avatar, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.avatars[userID]
+	if a == nil || s.liveUser(userID) == nil {
+		return nil, ErrAvatarNotFound
+	}
+	return &models.Avatar{
+		ContentType: a.ContentType, Image: bytes.Clone(a.Image), Thumbnail: bytes.Clone(a.Thumbnail), UpdatedAt: a.UpdatedAt,
+	}, nil
+}
+
+func (s *MemoryStore) SetAvatarThumbnail(ctx context.Context, userID string, updatedAt time.Time, thumbnail []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a := s.avatars[userID]; a != nil && a.UpdatedAt.Equal(updatedAt) {
+		a.Thumbnail = bytes.Clone(thumbnail)
+	}
+	return nil
+}
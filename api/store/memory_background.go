@@ -0,0 +1,664 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"masterdom/api/models"
+)
+
+type memNotification struct {
+	id        string
+	userID    string
+	eventType string
+	payload   []byte
+	readAt    *time.Time
+	emailedAt *time.Time
+	createdAt time.Time
+}
+
+type memEmail struct {
+	id        int64
+	userID    string
+	template  string
+	data      []byte
+	attempts  int
+	lastError *string
+	sentAt    *time.Time
+	createdAt time.Time
+}
+
+// --- Notifications ---
+
+// notify mirrors the Postgres notify, including the email queued for types
+// with a template.
+func (s *MemoryStore) notify(userID, eventType string, payload map[string]interface{}) error {
+	if s.users[userID] == nil {
+		return fmt.Errorf("failed to create %s notification: user %s does not exist", eventType, userID)
+	}
+	if enabled, ok := s.preferences[userID][eventType]; ok && !enabled {
+		return nil
+	}
+	data, err := jsonObject(payload)
+	if err != nil {
+		return fmt.Errorf("failed to create %s notification: %w", eventType, err)
+	}
+
+	now := s.now()
+	n := &memNotification{id: uuid.NewString(), userID: userID, eventType: eventType, payload: data, createdAt: now}
+	if template := models.NotificationEmailTemplates[eventType]; template != "" {
+		n.emailedAt = &now
+		if err := s.queueEmail(userID, template, data); err != nil {
+			return err
+		}
+	}
+	s.notifications = append(s.notifications, n)
+	return nil
+}
+
+// queueEmail stores an email in the outbox with a task to send it.
+func (s *MemoryStore) queueEmail(userID, template string, data []byte) error {
+	s.lastEmailID++
+	s.outbox = append(s.outbox, &memEmail{
+		id:        s.lastEmailID,
+		userID:    userID,
+		template:  template,
+		data:      data,
+		createdAt: s.now(),
+	})
+	_, err := s.enqueueTask(models.NewTask{
+		Kind:        models.TaskKindEmailSend,
+		Payload:     models.EmailTask{EmailID: s.lastEmailID},
+		MaxAttempts: emailSendAttempts,
+	})
+	return err
+}
+
+func (s *MemoryStore) CreateNotification(ctx context.Context, userID, eventType string, payload map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notify(userID, eventType, payload)
+}
+
+func (s *MemoryStore) GetNotifications(ctx context.Context, userID string, filter models.NotificationFilter) (*models.NotificationList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []*memNotification
+	unread := 0
+	for _, n := range s.notifications {
+		if n.userID != userID {
+			continue
+		}
+		if n.readAt == nil {
+			unread++
+		}
+		if !filter.UnreadOnly || n.readAt == nil {
+			matched = append(matched, n)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if !matched[i].createdAt.Equal(matched[j].createdAt) {
+			return matched[i].createdAt.After(matched[j].createdAt)
+		}
+		return matched[i].id < matched[j].id
+	})
+
+	list := models.NotificationList{Items: make([]models.Notification, 0), UnreadCount: unread}
+	start, end := page(len(matched), filter.Limit, filter.Offset)
+	for _, n := range matched[start:end] {
+		list.Items = append(list.Items, models.Notification{
+			ID: n.id, Type: n.eventType, Payload: json.RawMessage(n.payload), ReadAt: cloneTime(n.readAt), CreatedAt: n.createdAt,
+		})
+	}
+	return &list, nil
+}
+
+func (s *MemoryStore) MarkNotificationRead(ctx context.Context, userID, notificationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, n := range s.notifications {
+		if n.id == notificationID && n.userID == userID {
+			if n.readAt == nil {
+				now := s.now()
+				n.readAt = &now
+			}
+			return nil
+		}
+	}
+	return ErrNotificationNotFound
+}
+
+func (s *MemoryStore) MarkAllNotificationsRead(ctx context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var updated int64
+	for _, n := range s.notifications {
+		if n.userID == userID && n.readAt == nil {
+			n.readAt = &now
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+func (s *MemoryStore) GetNotificationPreferences(ctx context.Context, userID string) (map[string]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	preferences := make(map[string]bool, len(models.NotificationTypes))
+	for _, t := range models.NotificationTypes {
+		preferences[t] = true
+	}
+	for eventType, enabled := range s.preferences[userID] {
+		preferences[eventType] = enabled
+	}
+	return preferences, nil
+}
+
+func (s *MemoryStore) UpdateNotificationPreferences(ctx context.Context, userID string, preferences map[string]bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(preferences) == 0 {
+		return nil
+	}
+	if s.users[userID] == nil {
+		return fmt.Errorf("failed to save notification preferences: user %s does not exist", userID)
+	}
+	if s.preferences[userID] == nil {
+		s.preferences[userID] = make(map[string]bool)
+	}
+	for eventType, enabled := range preferences {
+		s.preferences[userID][eventType] = enabled
+	}
+	return nil
+}
+
+// --- Email Outbox ---
+
+func (s *MemoryStore) GetOutboxEmail(ctx context.Context, emailID int64) (*models.OutboxEmail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.findEmail(emailID)
+	if e == nil || e.sentAt != nil || s.liveUser(e.userID) == nil {
+		return nil, ErrEmailNotFound
+	}
+	u := s.users[e.userID]
+	return &models.OutboxEmail{
+		ID: e.id, To: u.email, Locale: u.locale, Template: e.template,
+		Data: decodeObject(e.data), CreatedAt: e.createdAt,
+	}, nil
+}
+
+func (s *MemoryStore) findEmail(emailID int64) *memEmail {
+	for _, e := range s.outbox {
+		if e.id == emailID {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) MarkEmailSent(ctx context.Context, emailID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e := s.findEmail(emailID); e != nil {
+		now := s.now()
+		e.sentAt = &now
+		e.attempts++
+		e.lastError = nil
+	}
+	return nil
+}
+
+func (s *MemoryStore) MarkEmailFailed(ctx context.Context, emailID int64, sendErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e := s.findEmail(emailID); e != nil {
+		e.attempts++
+		e.lastError = &sendErr
+	}
+	return nil
+}
+
+func (s *MemoryStore) EnqueueMessageDigests(ctx context.Context, createdBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var users []string
+	pending := make(map[string][]json.RawMessage)
+	// Notifications are appended in creation order, as jsonb_agg orders them
+	for _, n := range s.notifications {
+		if n.eventType != models.NotificationMessageCreated || n.readAt != nil || n.emailedAt != nil || n.createdAt.After(createdBefore) {
+			continue
+		}
+		n.emailedAt = &now
+		if pending[n.userID] == nil {
+			users = append(users, n.userID)
+		}
+		pending[n.userID] = append(pending[n.userID], json.RawMessage(n.payload))
+	}
+
+	for _, userID := range users {
+		data, err := json.Marshal(map[string]interface{}{"count": len(pending[userID]), "messages": pending[userID]})
+		if err != nil {
+			return 0, fmt.Errorf("failed to enqueue message digests: %w", err)
+		}
+		if err := s.queueEmail(userID, models.EmailTemplateMessageDigest, data); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(users)), nil
+}
+
+// --- Task Queue ---
+
+func cloneTask(t *models.Task) *models.Task {
+	c := *t
+	c.LockedBy = cloneString(t.LockedBy)
+	c.LockedAt = cloneTime(t.LockedAt)
+	c.LastError = cloneString(t.LastError)
+	c.FinishedAt = cloneTime(t.FinishedAt)
+	return &c
+}
+
+// enqueueTask mirrors the Postgres enqueueTask defaults.
+func (s *MemoryStore) enqueueTask(task models.NewTask) (int64, error) {
+	payload, err := jsonObject(task.Payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode %s task payload: %w", task.Kind, err)
+	}
+
+	now := s.now()
+	t := &models.Task{
+		Kind:        task.Kind,
+		Payload:     payload,
+		Status:      models.TaskStatusPending,
+		MaxAttempts: task.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if task.RunAt != nil {
+		t.RunAt = *task.RunAt
+	}
+	if t.MaxAttempts == 0 {
+		t.MaxAttempts = 10
+	}
+	s.lastTaskID++
+	t.ID = s.lastTaskID
+	s.tasks[t.ID] = t
+	return t.ID, nil
+}
+
+func (s *MemoryStore) EnqueueTask(ctx context.Context, task models.NewTask) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enqueueTask(task)
+}
+
+func (s *MemoryStore) ClaimTask(ctx context.Context, kinds []string, workerID string, lease time.Duration) (*models.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var next *models.Task
+	for _, t := range s.tasks {
+		if !slices.Contains(kinds, t.Kind) {
+			continue
+		}
+		due := t.Status == models.TaskStatusPending && !t.RunAt.After(now)
+		abandoned := t.Status == models.TaskStatusRunning && t.LockedAt != nil && t.LockedAt.Before(now.Add(-lease))
+		if (due || abandoned) && (next == nil || t.RunAt.Before(next.RunAt) || t.RunAt.Equal(next.RunAt) && t.ID < next.ID) {
+			next = t
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	next.Status = models.TaskStatusRunning
+	next.Attempts++
+	next.LockedBy = &workerID
+	next.LockedAt = &now
+	next.UpdatedAt = now
+	return cloneTask(next), nil
+}
+
+func (s *MemoryStore) CompleteTask(ctx context.Context, taskID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t := s.tasks[taskID]; t != nil {
+		now := s.now()
+		t.Status = models.TaskStatusSucceeded
+		t.FinishedAt = &now
+		t.LockedBy, t.LockedAt, t.LastError = nil, nil, nil
+		t.UpdatedAt = now
+	}
+	return nil
+}
+
+func (s *MemoryStore) FailTask(ctx context.Context, taskID int64, taskErr string, retryAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t := s.tasks[taskID]; t != nil {
+		now := s.now()
+		if retryAt == nil {
+			t.Status = models.TaskStatusDead
+			t.FinishedAt = &now
+		} else {
+			t.Status = models.TaskStatusPending
+			t.RunAt = *retryAt
+			t.FinishedAt = nil
+		}
+		t.LastError = &taskErr
+		t.LockedBy, t.LockedAt = nil, nil
+		t.UpdatedAt = now
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetTasks(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []*models.Task
+	for _, t := range s.tasks {
+		if (filter.Status == "" || t.Status == filter.Status) && (filter.Kind == "" || t.Kind == filter.Kind) {
+			matched = append(matched, t)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.After(matched[j].CreatedAt)
+		}
+		return matched[i].ID > matched[j].ID
+	})
+
+	start, end := page(len(matched), filter.Limit, filter.Offset)
+	tasks := make([]models.Task, 0, end-start)
+	for _, t := range matched[start:end] {
+		tasks = append(tasks, *cloneTask(t))
+	}
+	return tasks, nil
+}
+
+func (s *MemoryStore) GetTask(ctx context.Context, taskID int64) (*models.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tasks[taskID]
+	if t == nil {
+		return nil, ErrTaskNotFound
+	}
+	return cloneTask(t), nil
+}
+
+func (s *MemoryStore) RetryTask(ctx context.Context, taskID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targetID := strconv.FormatInt(taskID, 10)
+	return s.withAudit(ctx, "task.retry", auditTargetTask, &targetID, func() error {
+		t := s.tasks[taskID]
+		if t == nil || t.Status != models.TaskStatusDead {
+			return ErrDeadTaskNotFound
+		}
+		now := s.now()
+		t.Status = models.TaskStatusPending
+		t.Attempts = 0
+		t.RunAt = now
+		t.FinishedAt = nil
+		t.UpdatedAt = now
+		return nil
+	})
+}
+
+func (s *MemoryStore) PurgeFinishedTasks(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, t := range s.tasks {
+		if t.Status == models.TaskStatusSucceeded && t.FinishedAt != nil && t.FinishedAt.Before(finishedBefore) {
+			delete(s.tasks, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// --- Webhooks ---
+
+// publishEvent mirrors the Postgres publishEvent: one delivery task per
+// active subscribed webhook, all sharing the event ID.
+func (s *MemoryStore) publishEvent(eventType string, data map[string]interface{}) error {
+	event := models.WebhookEvent{ID: uuid.NewString(), Type: eventType, CreatedAt: s.now(), Data: data}
+	for _, w := range s.sortedWebhooks() {
+		if !w.IsActive || !slices.Contains(w.Events, eventType) {
+			continue
+		}
+		_, err := s.enqueueTask(models.NewTask{
+			Kind:        models.TaskKindWebhookDelivery,
+			Payload:     models.WebhookDeliveryTask{WebhookID: w.ID, Event: event},
+			MaxAttempts: webhookDeliveryAttempts,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish %s event: %w", eventType, err)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) sortedWebhooks() []*models.Webhook {
+	webhooks := make([]*models.Webhook, 0, len(s.webhooks))
+	for _, w := range s.webhooks {
+		webhooks = append(webhooks, w)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks
+}
+
+func cloneWebhook(w *models.Webhook) *models.Webhook {
+	c := *w
+	c.Description = cloneString(w.Description)
+	c.Events = cloneStrings(w.Events)
+	c.CreatedBy = cloneString(w.CreatedBy)
+	return &c
+}
+
+func (s *MemoryStore) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhooks := make([]models.Webhook, 0, len(s.webhooks))
+	for _, w := range s.sortedWebhooks() {
+		c := cloneWebhook(w)
+		c.Secret = ""
+		webhooks = append(webhooks, *c)
+	}
+	return webhooks, nil
+}
+
+func (s *MemoryStore) GetWebhook(ctx context.Context, webhookID int) (*models.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := s.webhooks[webhookID]
+	if w == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return cloneWebhook(w), nil
+}
+
+func (s *MemoryStore) CreateWebhook(ctx context.Context, payload models.WebhookPayload, secret, createdBy string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var webhookID int
+	var targetID string
+	err := s.withAudit(ctx, "webhook.create", auditTargetWebhook, &targetID, func() error {
+		if s.users[createdBy] == nil {
+			return fmt.Errorf("failed to create webhook: user %s does not exist", createdBy)
+		}
+		now := s.now()
+		w := &models.Webhook{
+			URL:         payload.URL,
+			Description: cloneString(payload.Description),
+			Secret:      secret,
+			Events:      cloneStrings(payload.Events),
+			IsActive:    true,
+			CreatedBy:   &createdBy,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if payload.IsActive != nil {
+			w.IsActive = *payload.IsActive
+		}
+		s.lastWebhookID++
+		w.ID = s.lastWebhookID
+		s.webhooks[w.ID] = w
+
+		webhookID = w.ID
+		targetID = strconv.Itoa(webhookID)
+		return nil
+	})
+	return webhookID, err
+}
+
+func (s *MemoryStore) UpdateWebhook(ctx context.Context, webhookID int, payload models.WebhookPayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targetID := strconv.Itoa(webhookID)
+	return s.withAudit(ctx, "webhook.update", auditTargetWebhook, &targetID, func() error {
+		w := s.webhooks[webhookID]
+		if w == nil {
+			return ErrWebhookNotFound
+		}
+		w.URL = payload.URL
+		w.Description = cloneString(payload.Description)
+		w.Events = cloneStrings(payload.Events)
+		if payload.IsActive != nil {
+			w.IsActive = *payload.IsActive
+		}
+		w.UpdatedAt = s.now()
+		return nil
+	})
+}
+
+func (s *MemoryStore) RotateWebhookSecret(ctx context.Context, webhookID int, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targetID := strconv.Itoa(webhookID)
+	return s.withAudit(ctx, "webhook.rotate_secret", auditTargetWebhook, &targetID, func() error {
+		w := s.webhooks[webhookID]
+		if w == nil {
+			return ErrWebhookNotFound
+		}
+		w.Secret = secret
+		w.UpdatedAt = s.now()
+		return nil
+	})
+}
+
+func (s *MemoryStore) DeleteWebhook(ctx context.Context, webhookID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targetID := strconv.Itoa(webhookID)
+	return s.withAudit(ctx, "webhook.delete", auditTargetWebhook, &targetID, func() error {
+		if s.webhooks[webhookID] == nil {
+			return ErrWebhookNotFound
+		}
+		delete(s.webhooks, webhookID)
+		s.deliveries = filterSlice(s.deliveries, func(d *models.WebhookDelivery) bool { return d.WebhookID != webhookID })
+		return nil
+	})
+}
+
+func (s *MemoryStore) EnqueueWebhookTest(ctx context.Context, webhookID int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := s.webhooks[webhookID]
+	if w == nil {
+		return "", ErrWebhookNotFound
+	}
+	event := models.WebhookEvent{
+		ID:        uuid.NewString(),
+		Type:      models.WebhookEventTest,
+		CreatedAt: s.now(),
+		Data:      map[string]interface{}{"webhookId": w.ID},
+	}
+	_, err := s.enqueueTask(models.NewTask{
+		Kind:        models.TaskKindWebhookDelivery,
+		Payload:     models.WebhookDeliveryTask{WebhookID: w.ID, Event: event},
+		MaxAttempts: 1,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to queue webhook test: %w", err)
+	}
+	return event.ID, nil
+}
+
+func (s *MemoryStore) RecordWebhookDelivery(ctx context.Context, d models.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The webhook may have been deleted while the request was in flight
+	if s.webhooks[d.WebhookID] == nil {
+		return nil
+	}
+	s.lastDeliveryID++
+	d.ID = s.lastDeliveryID
+	d.CreatedAt = s.now()
+	d.StatusCode = cloneInt(d.StatusCode)
+	d.ResponseBody = cloneString(d.ResponseBody)
+	d.Error = cloneString(d.Error)
+	s.deliveries = append(s.deliveries, &d)
+	return nil
+}
+
+func (s *MemoryStore) GetWebhookDeliveries(ctx context.Context, webhookID, limit, offset int) ([]models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []*models.WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.WebhookID == webhookID {
+			matched = append(matched, d)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.After(matched[j].CreatedAt)
+		}
+		return matched[i].ID > matched[j].ID
+	})
+
+	start, end := page(len(matched), limit, offset)
+	deliveries := make([]models.WebhookDelivery, 0, end-start)
+	for _, d := range matched[start:end] {
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,734 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"masterdom/api/models"
+	"masterdom/api/utils"
+)
+
+type memCategory struct {
+	id           int
+	name         string
+	description  *string
+	parentID     *int
+	slug         string
+	sortOrder    int
+	icon         *string
+	deletedAt    *time.Time
+	translations map[string]models.CategoryTranslation
+}
+
+type memAttribute struct {
+	id         int
+	categoryID int
+	key        string
+	label      string
+	attrType   string
+	required   bool
+	options    []string
+	sortOrder  int
+	createdAt  time.Time
+	updatedAt  time.Time
+}
+
+func (a *memAttribute) attribute() models.CategoryAttribute {
+	return models.CategoryAttribute{
+		ID: a.id, CategoryID: a.categoryID, Key: a.key, Label: a.label, Type: a.attrType,
+		Required: a.required, Options: cloneStrings(a.options), SortOrder: a.sortOrder,
+	}
+}
+
+func cloneStrings(list []string) []string {
+	if list == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(list)), list...)
+}
+
+func (s *MemoryStore) liveCategory(categoryID int) *memCategory {
+	if c := s.categories[categoryID]; c != nil && c.deletedAt == nil {
+		return c
+	}
+	return nil
+}
+
+// categoryTree mirrors categoryDescendantsQuery: the live category matched by
+// ID or slug together with all its live descendants.
+func (s *MemoryStore) categoryTree(ref string) []int {
+	var tree []int
+	for _, c := range s.categories {
+		if c.deletedAt == nil && (strconv.Itoa(c.id) == ref || c.slug == ref) {
+			tree = append(tree, c.id)
+		}
+	}
+	for i := 0; i < len(tree); i++ {
+		for _, c := range s.categories {
+			if c.deletedAt == nil && c.parentID != nil && *c.parentID == tree[i] {
+				tree = append(tree, c.id)
+			}
+		}
+	}
+	return tree
+}
+
+// checkCategoryParent mirrors the Postgres checkCategoryParent.
+func (s *MemoryStore) checkCategoryParent(categoryID int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == categoryID {
+		return ErrCategoryCycle
+	}
+	if s.liveCategory(*parentID) == nil {
+		return ErrRelatedCategoryNotFound
+	}
+	for _, id := range s.categoryTree(strconv.Itoa(categoryID)) {
+		if id == *parentID {
+			return ErrCategoryCycle
+		}
+	}
+	return nil
+}
+
+// categorySlug mirrors the Postgres categorySlug.
+func (s *MemoryStore) categorySlug(categoryID int, name string, requested *string) (string, error) {
+	explicit := requested != nil && *requested != ""
+	base := utils.Slugify(name)
+	if explicit {
+		base = *requested
+	}
+	if base == "" {
+		base = "category"
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		taken := false
+		for _, c := range s.categories {
+			if c.deletedAt == nil && c.id != categoryID && c.slug == slug {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			return slug, nil
+		}
+		if explicit {
+			return "", ErrSlugTaken
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// checkCategoryName enforces the unique name among live siblings.
+func (s *MemoryStore) checkCategoryName(categoryID int, parentID *int, name string) error {
+	for _, c := range s.categories {
+		if c.deletedAt == nil && c.id != categoryID && c.name == name && sameParent(c.parentID, parentID) {
+			return ErrCategoryNameTaken
+		}
+	}
+	return nil
+}
+
+// saveTranslations mirrors saveCategoryTranslations: a nil map refreshes the
+// default-locale translation from the base name and description.
+func (c *memCategory) saveTranslations(translations map[string]models.CategoryTranslation) {
+	if translations != nil {
+		c.translations = cloneTranslations(translations)
+		return
+	}
+	if c.translations == nil {
+		c.translations = make(map[string]models.CategoryTranslation)
+	}
+	c.translations[utils.DefaultLocale] = models.CategoryTranslation{Name: c.name, Description: cloneString(c.description)}
+}
+
+func sameParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func cloneTranslations(translations map[string]models.CategoryTranslation) map[string]models.CategoryTranslation {
+	if translations == nil {
+		return nil
+	}
+	c := make(map[string]models.CategoryTranslation, len(translations))
+	for locale, t := range translations {
+		c[locale] = models.CategoryTranslation{Name: t.Name, Description: cloneString(t.Description)}
+	}
+	return c
+}
+
+// --- Categories ---
+
+func (s *MemoryStore) GetAllCategories(ctx context.Context, locale string) ([]models.ServiceCategory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categories := make([]models.ServiceCategory, 0)
+	for _, c := range s.categories {
+		if c.deletedAt != nil {
+			continue
+		}
+		cat := models.ServiceCategory{
+			ID: c.id, ParentID: cloneInt(c.parentID), Slug: c.slug, Name: c.name,
+			SortOrder: c.sortOrder, Icon: cloneString(c.icon),
+		}
+		// COALESCE(t.x, d.x, c.x) for each column: the requested locale wins over the default one
+		description := c.description
+		for _, l := range []string{utils.DefaultLocale, locale} {
+			if t, ok := c.translations[l]; ok {
+				cat.Name = t.Name
+				if t.Description != nil {
+					description = t.Description
+				}
+			}
+		}
+		if description != nil {
+			cat.Description = *description
+		}
+		categories = append(categories, cat)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].SortOrder != categories[j].SortOrder {
+			return categories[i].SortOrder < categories[j].SortOrder
+		}
+		return categories[i].Name < categories[j].Name
+	})
+	return categories, nil
+}
+
+func (s *MemoryStore) GetCategoryTranslations(ctx context.Context) (map[int]map[string]models.CategoryTranslation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	translations := make(map[int]map[string]models.CategoryTranslation)
+	for _, c := range s.categories {
+		if c.deletedAt == nil && len(c.translations) > 0 {
+			translations[c.id] = cloneTranslations(c.translations)
+		}
+	}
+	return translations, nil
+}
+
+func (s *MemoryStore) CreateCategory(ctx context.Context, payload models.CategoryPayload) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var categoryID int
+	var targetID string
+	err := s.withAudit(ctx, "category.create", auditTargetCategory, &targetID, func() error {
+		if err := s.checkCategoryParent(0, payload.ParentID); err != nil {
+			return err
+		}
+		slug, err := s.categorySlug(0, payload.Name, payload.Slug)
+		if err != nil {
+			return err
+		}
+		if err := s.checkCategoryName(0, payload.ParentID, payload.Name); err != nil {
+			return err
+		}
+
+		c := &memCategory{
+			name:        payload.Name,
+			description: cloneString(payload.Description),
+			parentID:    cloneInt(payload.ParentID),
+			slug:        slug,
+			icon:        cloneString(payload.Icon),
+		}
+		c.saveTranslations(payload.Translations)
+		if payload.SortOrder != nil {
+			c.sortOrder = *payload.SortOrder
+		}
+		s.lastCategoryID++
+		c.id = s.lastCategoryID
+		s.categories[c.id] = c
+
+		categoryID = c.id
+		targetID = strconv.Itoa(categoryID)
+		return nil
+	})
+	return categoryID, err
+}
+
+func (s *MemoryStore) UpdateCategory(ctx context.Context, categoryID int, payload models.UpdateCategoryPayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targetID := strconv.Itoa(categoryID)
+	return s.withAudit(ctx, "category.update", auditTargetCategory, &targetID, func() error {
+		if err := s.checkCategoryParent(categoryID, payload.ParentID); err != nil {
+			return err
+		}
+		c := s.liveCategory(categoryID)
+		if c == nil {
+			return ErrCategoryNotFound
+		}
+		name := c.name
+		if payload.Name != nil {
+			name = *payload.Name
+		}
+		var slug *string
+		if payload.Slug != nil {
+			generated, err := s.categorySlug(categoryID, name, payload.Slug)
+			if err != nil {
+				return err
+			}
+			slug = &generated
+		}
+		parentID := c.parentID
+		switch {
+		case payload.Clears("parentId"):
+			parentID = nil
+		case payload.ParentID != nil:
+			parentID = cloneInt(payload.ParentID)
+		}
+		if err := s.checkCategoryName(categoryID, parentID, name); err != nil {
+			return err
+		}
+
+		c.name = name
+		c.parentID = parentID
+		switch {
+		case payload.Clears("description"):
+			c.description = nil
+		case payload.Description != nil:
+			c.description = cloneString(payload.Description)
+		}
+		if slug != nil {
+			c.slug = *slug
+		}
+		if payload.SortOrder != nil {
+			c.sortOrder = *payload.SortOrder
+		}
+		switch {
+		case payload.Clears("icon"):
+			c.icon = nil
+		case payload.Icon != nil:
+			c.icon = cloneString(payload.Icon)
+		}
+		c.saveTranslations(payload.Translations)
+		return nil
+	})
+}
+
+func (s *MemoryStore) DeleteCategory(ctx context.Context, categoryID int, reassignTo *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targetID := strconv.Itoa(categoryID)
+	return s.withAudit(ctx, "category.delete", auditTargetCategory, &targetID, func() error {
+		used := false
+		for _, c := range s.categories {
+			if c.deletedAt == nil && c.parentID != nil && *c.parentID == categoryID {
+				used = true
+			}
+		}
+		for _, o := range s.offers {
+			if o.deletedAt == nil && o.categoryID != nil && *o.categoryID == categoryID {
+				used = true
+			}
+		}
+
+		if used {
+			if reassignTo == nil {
+				return ErrCategoryNotEmpty
+			}
+			if err := s.checkCategoryParent(categoryID, reassignTo); err != nil {
+				return err
+			}
+		}
+		c := s.liveCategory(categoryID)
+		if c == nil {
+			return ErrCategoryNotFound
+		}
+
+		if used {
+			// Deleted subcategories and offers move too, as in the Postgres version
+			for _, child := range s.categories {
+				if child.parentID != nil && *child.parentID == categoryID {
+					child.parentID = cloneInt(reassignTo)
+				}
+			}
+			for _, o := range s.offers {
+				if o.categoryID != nil && *o.categoryID == categoryID {
+					o.categoryID = cloneInt(reassignTo)
+					o.updatedAt = s.now()
+				}
+			}
+		}
+		now := s.now()
+		c.deletedAt = &now
+		return nil
+	})
+}
+
+// --- Category Attributes ---
+
+func (s *MemoryStore) GetCategoryAttributes(ctx context.Context, categoryID int) ([]models.CategoryAttribute, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.liveCategory(categoryID) == nil {
+		return nil, ErrCategoryNotFound
+	}
+
+	// Walk up through live ancestors; the nearest definition of a key wins
+	byKey := make(map[string]*memAttribute)
+	for c := s.liveCategory(categoryID); c != nil; {
+		for _, a := range s.attributes {
+			if a.categoryID == c.id && byKey[a.key] == nil {
+				byKey[a.key] = a
+			}
+		}
+		if c.parentID == nil {
+			break
+		}
+		c = s.liveCategory(*c.parentID)
+	}
+
+	attributes := make([]models.CategoryAttribute, 0, len(byKey))
+	for _, a := range byKey {
+		attributes = append(attributes, a.attribute())
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		if attributes[i].SortOrder != attributes[j].SortOrder {
+			return attributes[i].SortOrder < attributes[j].SortOrder
+		}
+		return attributes[i].Key < attributes[j].Key
+	})
+	return attributes, nil
+}
+
+func (s *MemoryStore) attributeKeyTaken(categoryID, attributeID int, key string) bool {
+	for _, a := range s.attributes {
+		if a.categoryID == categoryID && a.id != attributeID && a.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) CreateCategoryAttribute(ctx context.Context, categoryID int, payload models.CategoryAttributePayload) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var attributeID int
+	var targetID string
+	err := s.withAudit(ctx, "category_attribute.create", auditTargetAttribute, &targetID, func() error {
+		if s.liveCategory(categoryID) == nil {
+			return ErrCategoryNotFound
+		}
+		if s.attributeKeyTaken(categoryID, 0, payload.Key) {
+			return ErrAttributeKeyTaken
+		}
+
+		now := s.now()
+		s.lastAttributeID++
+		a := &memAttribute{
+			id:         s.lastAttributeID,
+			categoryID: categoryID,
+			key:        payload.Key,
+			label:      payload.Label,
+			attrType:   payload.Type,
+			required:   payload.Required,
+			options:    cloneStrings(payload.Options),
+			sortOrder:  payload.SortOrder,
+			createdAt:  now,
+			updatedAt:  now,
+		}
+		s.attributes[a.id] = a
+
+		attributeID = a.id
+		targetID = strconv.Itoa(attributeID)
+		return nil
+	})
+	return attributeID, err
+}
+
+func (s *MemoryStore) UpdateCategoryAttribute(ctx context.Context, categoryID, attributeID int, payload models.CategoryAttributePayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targetID := strconv.Itoa(attributeID)
+	return s.withAudit(ctx, "category_attribute.update", auditTargetAttribute, &targetID, func() error {
+		a := s.attributes[attributeID]
+		if a == nil || a.categoryID != categoryID {
+			return ErrAttributeNotFound
+		}
+		if s.attributeKeyTaken(categoryID, attributeID, payload.Key) {
+			return ErrAttributeKeyTaken
+		}
+
+		a.key = payload.Key
+		a.label = payload.Label
+		a.attrType = payload.Type
+		a.required = payload.Required
+		a.options = cloneStrings(payload.Options)
+		a.sortOrder = payload.SortOrder
+		a.updatedAt = s.now()
+		return nil
+	})
+}
+
+func (s *MemoryStore) DeleteCategoryAttribute(ctx context.Context, categoryID, attributeID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targetID := strconv.Itoa(attributeID)
+	return s.withAudit(ctx, "category_attribute.delete", auditTargetAttribute, &targetID, func() error {
+		a := s.attributes[attributeID]
+		if a == nil || a.categoryID != categoryID {
+			return ErrAttributeNotFound
+		}
+		delete(s.attributes, attributeID)
+		return nil
+	})
+}
+
+// --- Soft Delete ---
+
+func (s *MemoryStore) RestoreUser(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withAudit(ctx, auditTargetUser+".restore", auditTargetUser, &userID, func() error {
+		u := s.users[userID]
+		if u == nil || u.deletedAt == nil {
+			return ErrDeletedRecordNotFound
+		}
+		for _, other := range s.users {
+			if other.deletedAt == nil && other.email == u.email {
+				return ErrRestoreConflict
+			}
+		}
+		u.deletedAt = nil
+		return nil
+	})
+}
+
+func (s *MemoryStore) RestoreOffer(ctx context.Context, offerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withAudit(ctx, auditTargetOffer+".restore", auditTargetOffer, &offerID, func() error {
+		o := s.offers[offerID]
+		if o == nil || o.deletedAt == nil {
+			return ErrDeletedRecordNotFound
+		}
+		o.deletedAt = nil
+		o.updatedAt = s.now()
+		return nil
+	})
+}
+
+func (s *MemoryStore) RestoreCategory(ctx context.Context, categoryID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targetID := strconv.Itoa(categoryID)
+	return s.withAudit(ctx, auditTargetCategory+".restore", auditTargetCategory, &targetID, func() error {
+		c := s.categories[categoryID]
+		if c == nil || c.deletedAt == nil {
+			return ErrDeletedRecordNotFound
+		}
+		// A category whose parent is still deleted comes back as a root category
+		parentID := c.parentID
+		if parentID != nil && s.liveCategory(*parentID) == nil {
+			parentID = nil
+		}
+		for _, other := range s.categories {
+			if other.deletedAt == nil && (other.slug == c.slug || other.name == c.name && sameParent(other.parentID, parentID)) {
+				return ErrRestoreConflict
+			}
+		}
+		c.parentID = parentID
+		c.deletedAt = nil
+		return nil
+	})
+}
+
+func (s *MemoryStore) GetTrash(ctx context.Context) (*models.TrashResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trash := models.TrashResponse{
+		Users:      make([]models.DeletedEntity, 0),
+		Offers:     make([]models.DeletedEntity, 0),
+		Categories: make([]models.DeletedEntity, 0),
+	}
+	for _, u := range s.users {
+		if u.deletedAt != nil {
+			trash.Users = append(trash.Users, models.DeletedEntity{ID: u.id, Label: u.email, DeletedAt: *u.deletedAt})
+		}
+	}
+	for _, o := range s.offers {
+		if o.deletedAt != nil {
+			trash.Offers = append(trash.Offers, models.DeletedEntity{ID: o.id, Label: o.title, DeletedAt: *o.deletedAt})
+		}
+	}
+	for _, c := range s.categories {
+		if c.deletedAt != nil {
+			trash.Categories = append(trash.Categories, models.DeletedEntity{ID: strconv.Itoa(c.id), Label: c.name, DeletedAt: *c.deletedAt})
+		}
+	}
+	for _, list := range [][]models.DeletedEntity{trash.Users, trash.Offers, trash.Categories} {
+		sort.Slice(list, func(i, j int) bool { return list[i].DeletedAt.After(list[j].DeletedAt) })
+	}
+	return &trash, nil
+}
+
+// PurgeDeleted removes soft-deleted rows with the same cascades as the
+// foreign keys of the Postgres schema.
+func (s *MemoryStore) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (*models.PurgeResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result models.PurgeResult
+	for id, o := range s.offers {
+		if o.deletedAt != nil && o.deletedAt.Before(deletedBefore) {
+			s.removeOffer(id)
+			result.Offers++
+		}
+	}
+	for id, c := range s.categories {
+		if c.deletedAt != nil && c.deletedAt.Before(deletedBefore) {
+			s.removeCategory(id)
+			result.Categories++
+		}
+	}
+	for id, u := range s.users {
+		if u.deletedAt != nil && u.deletedAt.Before(deletedBefore) {
+			s.removeUser(id)
+			result.Users++
+		}
+	}
+	return &result, nil
+}
+
+// removeOffer deletes the offer with its applications, jobs and conversations.
+func (s *MemoryStore) removeOffer(offerID string) {
+	delete(s.offers, offerID)
+	for id, a := range s.applications {
+		if a.offerID == offerID {
+			delete(s.applications, id)
+		}
+	}
+	for id, j := range s.jobs {
+		if j.OfferID == offerID {
+			s.removeJob(id)
+		}
+	}
+	for id, c := range s.conversations {
+		if c.offerID == offerID {
+			s.removeConversation(id)
+		}
+	}
+}
+
+// removeJob deletes the job with its reviews.
+func (s *MemoryStore) removeJob(jobID string) {
+	delete(s.jobs, jobID)
+	s.reviews = filterSlice(s.reviews, func(r *models.Review) bool { return r.JobID != jobID })
+}
+
+func (s *MemoryStore) removeConversation(conversationID string) {
+	delete(s.conversations, conversationID)
+	s.messages = filterSlice(s.messages, func(m *memMessage) bool { return m.conversationID != conversationID })
+}
+
+// removeCategory deletes the category with its attributes. Subcategories
+// become roots and offers lose their category.
+func (s *MemoryStore) removeCategory(categoryID int) {
+	delete(s.categories, categoryID)
+	for _, c := range s.categories {
+		if c.parentID != nil && *c.parentID == categoryID {
+			c.parentID = nil
+		}
+	}
+	for _, o := range s.offers {
+		if o.categoryID != nil && *o.categoryID == categoryID {
+			o.categoryID = nil
+		}
+	}
+	for id, a := range s.attributes {
+		if a.categoryID == categoryID {
+			delete(s.attributes, id)
+		}
+	}
+}
+
+// removeUser deletes the user with everything that belongs to them.
+// References from other users' records are cleared.
+func (s *MemoryStore) removeUser(userID string) {
+	delete(s.users, userID)
+	delete(s.avatars, userID)
+	delete(s.preferences, userID)
+	for id, o := range s.offers {
+		if o.authorID == userID {
+			s.removeOffer(id)
+		}
+	}
+	for id, a := range s.applications {
+		if a.applicantID == userID {
+			delete(s.applications, id)
+		}
+	}
+	for id, j := range s.jobs {
+		switch {
+		case j.ClientID == userID:
+			s.removeJob(id)
+		case j.MasterID == userID:
+			j.MasterID = ""
+		}
+	}
+	s.reviews = filterSlice(s.reviews, func(r *models.Review) bool { return r.AuthorID != userID && r.SubjectID != userID })
+	for _, c := range s.conversations {
+		c.participants = filterSlice(c.participants, func(id string) bool { return id != userID })
+	}
+	s.messages = filterSlice(s.messages, func(m *memMessage) bool { return m.senderID != userID })
+	s.notifications = filterSlice(s.notifications, func(n *memNotification) bool { return n.userID != userID })
+	s.outbox = filterSlice(s.outbox, func(e *memEmail) bool { return e.userID != userID })
+	s.identities = filterSlice(s.identities, func(i *memIdentity) bool { return i.userID != userID })
+	s.sanctions = filterSlice(s.sanctions, func(sanction *models.UserSanction) bool { return sanction.UserID != userID })
+	for id, k := range s.apiKeys {
+		if k.UserID == userID {
+			delete(s.apiKeys, id)
+		}
+	}
+
+	for _, sanction := range s.sanctions {
+		if sanction.ActorID != nil && *sanction.ActorID == userID {
+			sanction.ActorID = nil
+		}
+	}
+	for _, u := range s.users {
+		if u.sanctionedBy != nil && *u.sanctionedBy == userID {
+			u.sanctionedBy = nil
+		}
+	}
+	for _, w := range s.webhooks {
+		if w.CreatedBy != nil && *w.CreatedBy == userID {
+			w.CreatedBy = nil
+		}
+	}
+}
+
+// filterSlice keeps the elements for which keep returns true.
+func filterSlice[T any](list []T, keep func(T) bool) []T {
+	kept := list[:0]
+	for _, item := range list {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	clear(list[len(kept):])
+	return kept
+}
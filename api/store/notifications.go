@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"masterdom/api/models"
+)
+
+// notify stores a notification for the user unless the user has disabled
+// notifications of this type. Types with an email template also get an email
+// stored in the outbox and a task to send it.
+func notify(ctx context.Context, db querier, userID, eventType string, payload map[string]interface{}) error {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	template := models.NotificationEmailTemplates[eventType]
+	_, err := db.Exec(ctx, `
+		WITH notification AS (
+			INSERT INTO notifications (user_id, type, payload, emailed_at)
+			SELECT $1, $2, $3, CASE WHEN $4 <> '' THEN NOW() END
+			WHERE NOT EXISTS (
+				SELECT 1 FROM notification_preferences
+				WHERE user_id = $1 AND type = $2 AND enabled = FALSE
+			)
+			RETURNING user_id, payload
+		), email AS (
+			INSERT INTO email_outbox (user_id, template, data)
+			SELECT user_id, $4, payload FROM notification WHERE $4 <> ''
+			RETURNING id
+		)
+		INSERT INTO tasks (kind, payload, max_attempts)
+		SELECT $5, jsonb_build_object('emailId', id), $6 FROM email`,
+		userID, eventType, payload, template, models.TaskKindEmailSend, emailSendAttempts)
+	if err != nil {
+		return fmt.Errorf("failed to create %s notification: %w", eventType, err)
+	}
+	return nil
+}
+
+// --- Notification Implementations ---
+
+func (s *PostgresStore) CreateNotification(ctx context.Context, userID, eventType string, payload map[string]interface{}) error {
+	return notify(ctx, s.dbpool, userID, eventType, payload)
+}
+
+func (s *PostgresStore) GetNotifications(ctx context.Context, userID string, filter models.NotificationFilter) (*models.NotificationList, error) {
+	list := models.NotificationList{Items: make([]models.Notification, 0)}
+
+	err := s.dbpool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL",
+		userID).Scan(&list.UnreadCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	rows, err := s.dbpool.Query(ctx, `
+		SELECT id, type, payload, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4`,
+		userID, filter.UnreadOnly, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notifications: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.ID, &n.Type, &n.Payload, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		list.Items = append(list.Items, n)
+	}
+	return &list, rows.Err()
+}
+
+func (s *PostgresStore) MarkNotificationRead(ctx context.Context, userID, notificationID string) error {
+	tag, err := s.dbpool.Exec(ctx,
+		"UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2",
+		notificationID, userID)
+	if err != nil {
+		return notFound(fmt.Errorf("failed to mark notification as read: %w", err), ErrNotificationNotFound)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (s *PostgresStore) MarkAllNotificationsRead(ctx context.Context, userID string) (int64, error) {
+	tag, err := s.dbpool.Exec(ctx,
+		"UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL",
+		userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetNotificationPreferences returns whether each notification type is
+// enabled for the user. Types without a stored preference are enabled.
+func (s *PostgresStore) GetNotificationPreferences(ctx context.Context, userID string) (map[string]bool, error) {
+	preferences := make(map[string]bool, len(models.NotificationTypes))
+	for _, t := range models.NotificationTypes {
+		preferences[t] = true
+	}
+
+	rows, err := s.dbpool.Query(ctx, "SELECT type, enabled FROM notification_preferences WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var eventType string
+		var enabled bool
+		if err := rows.Scan(&eventType, &enabled); err != nil {
+			return nil, fmt.Errorf("failed to scan notification preference: %w", err)
+		}
+		preferences[eventType] = enabled
+	}
+	return preferences, rows.Err()
+}
+
+func (s *PostgresStore) UpdateNotificationPreferences(ctx context.Context, userID string, preferences map[string]bool) error {
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for eventType, enabled := range preferences {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO notification_preferences (user_id, type, enabled) VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = NOW()`,
+			userID, eventType, enabled)
+		if err != nil {
+			return fmt.Errorf("failed to save %s notification preference: %w", eventType, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/models"
+)
+
+// --- Operator Implementations ---
+
+// SetUserPassword replaces the password of a user, e.g. when an operator
+// resets it. Users that signed up through OIDC get a password this way too.
+func (s *PostgresStore) SetUserPassword(ctx context.Context, userID, hashedPassword string) error {
+	return s.withAudit(ctx, "user.reset_password", auditTargetUser, &userID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			"UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2 AND deleted_at IS NULL",
+			hashedPassword, userID)
+		if err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+// DeactivateOffers unpublishes every active offer matching the filter and
+// returns the IDs of the offers it changed. Each offer is audited on its own,
+// so a failure half-way leaves the already processed offers deactivated.
+func (s *PostgresStore) DeactivateOffers(ctx context.Context, filter models.OfferDeactivationFilter) ([]string, error) {
+	rows, err := s.dbpool.Query(ctx, `
+		SELECT id FROM offers
+		WHERE is_active AND deleted_at IS NULL
+		  AND ($1::uuid IS NULL OR author_id = $1)
+		  AND ($2::int IS NULL OR category_id = $2)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+		ORDER BY created_at`,
+		filter.AuthorID, filter.CategoryID, filter.CreatedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find offers to deactivate: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan offer id: %w", err)
+	}
+
+	deactivated := make([]string, 0, len(ids))
+	for _, id := range ids {
+		err := s.withAudit(ctx, "offer.update_status", auditTargetOffer, &id, func(tx pgx.Tx) error {
+			_, err := tx.Exec(ctx, "UPDATE offers SET is_active = FALSE, updated_at = NOW() WHERE id = $1 AND is_active", id)
+			return err
+		})
+		if err != nil {
+			return deactivated, fmt.Errorf("failed to deactivate offer %s: %w", id, err)
+		}
+		deactivated = append(deactivated, id)
+	}
+	return deactivated, nil
+}
+
+// RecomputeRatings rebuilds the average rating of every user from the job
+// reviews they received and returns how many ratings changed. It repairs
+// ratings left stale, e.g. after reviews were purged with their jobs.
+func (s *PostgresStore) RecomputeRatings(ctx context.Context) (int, error) {
+	tag, err := s.dbpool.Exec(ctx, `
+		WITH ratings AS (
+			SELECT subject_id, ROUND(AVG(rating), 2) AS rating
+			FROM job_reviews
+			GROUP BY subject_id
+		)
+		UPDATE user_details ud
+		SET average_rating = COALESCE(r.rating, 0)
+		FROM user_details d
+		LEFT JOIN ratings r ON r.subject_id = d.user_id
+		WHERE ud.user_id = d.user_id AND ud.average_rating IS DISTINCT FROM COALESCE(r.rating, 0)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recompute ratings: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
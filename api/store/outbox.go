@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"masterdom/api/models"
+)
+
+// emailSendAttempts is how many times an email is sent before the send task is dead.
+const emailSendAttempts = 8
+
+// --- Email Outbox Implementations ---
+
+// GetOutboxEmail returns an unsent email with the address and locale of its
+// recipient. Sent emails and emails to deleted users are reported as
+// ErrEmailNotFound.
+func (s *PostgresStore) GetOutboxEmail(ctx context.Context, emailID int64) (*models.OutboxEmail, error) {
+	var e models.OutboxEmail
+	err := s.dbpool.QueryRow(ctx, `
+		SELECT e.id, u.email, u.locale, e.template, e.data, e.created_at
+		FROM email_outbox e
+		JOIN users u ON u.id = e.user_id AND u.deleted_at IS NULL
+		WHERE e.id = $1 AND e.sent_at IS NULL`,
+		emailID).Scan(&e.ID, &e.To, &e.Locale, &e.Template, &e.Data, &e.CreatedAt)
+	if err != nil {
+		return nil, notFound(fmt.Errorf("failed to get outbox email: %w", err), ErrEmailNotFound)
+	}
+	return &e, nil
+}
+
+func (s *PostgresStore) MarkEmailSent(ctx context.Context, emailID int64) error {
+	_, err := s.dbpool.Exec(ctx,
+		"UPDATE email_outbox SET sent_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = $1",
+		emailID)
+	if err != nil {
+		return fmt.Errorf("failed to mark email as sent: %w", err)
+	}
+	return nil
+}
+
+// MarkEmailFailed records a failed send; the send task decides whether to retry.
+func (s *PostgresStore) MarkEmailFailed(ctx context.Context, emailID int64, sendErr string) error {
+	_, err := s.dbpool.Exec(ctx,
+		"UPDATE email_outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1",
+		emailID, sendErr)
+	if err != nil {
+		return fmt.Errorf("failed to mark email as failed: %w", err)
+	}
+	return nil
+}
+
+// EnqueueMessageDigests queues one digest email per user for unread message
+// notifications created before createdBefore that were not emailed yet.
+func (s *PostgresStore) EnqueueMessageDigests(ctx context.Context, createdBefore time.Time) (int64, error) {
+	tag, err := s.dbpool.Exec(ctx, `
+		WITH pending AS (
+			UPDATE notifications SET emailed_at = NOW()
+			WHERE type = $1 AND read_at IS NULL AND emailed_at IS NULL AND created_at <= $2
+			RETURNING user_id, payload, created_at
+		), email AS (
+			INSERT INTO email_outbox (user_id, template, data)
+			SELECT user_id, $3, jsonb_build_object(
+				'count', COUNT(*),
+				'messages', jsonb_agg(payload ORDER BY created_at)
+			)
+			FROM pending
+			GROUP BY user_id
+			RETURNING id
+		)
+		INSERT INTO tasks (kind, payload, max_attempts)
+		SELECT $4, jsonb_build_object('emailId', id), $5 FROM email`,
+		models.NotificationMessageCreated, createdBefore, models.EmailTemplateMessageDigest,
+		models.TaskKindEmailSend, emailSendAttempts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue message digests: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
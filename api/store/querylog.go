@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"masterdom/api/logging"
+)
+
+// storePackage is the import path prefix of functions in this package, used
+// to find the store method that issued a query.
+var storePackage = reflect.TypeOf(PostgresStore{}).PkgPath() + "."
+
+// QueryLogger is a pgx tracer that logs failed queries together with the
+// name of the store method that ran them. Callers receive the error as usual;
+// the log line keeps the SQL state and request context that a wrapped error
+// message loses by the time it reaches the handler.
+type QueryLogger struct{}
+
+func (QueryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return ctx
+}
+
+func (QueryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	err := data.Err
+	if err == nil || errors.Is(err, pgx.ErrNoRows) || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	attrs := []slog.Attr{slog.String("query", queryName()), logging.Err(err)}
+	level := slog.LevelError
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		attrs = append(attrs, slog.String("sql_state", pgErr.Code))
+		// Constraint violations are reported to clients as conflicts or bad input
+		if strings.HasPrefix(pgErr.Code, "23") {
+			level = slog.LevelWarn
+		}
+	}
+	slog.LogAttrs(ctx, level, "Database query failed", attrs...)
+}
+
+// queryName returns the name of the exported store method on the call stack,
+// e.g. "GetOffers". Helpers such as publishEvent are reported under the method
+// that called them.
+func queryName() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	fallback := "unknown"
+	for {
+		frame, more := frames.Next()
+		if function, ok := strings.CutPrefix(frame.Function, storePackage); ok && !isQueryHook(function) {
+			name := methodName(function)
+			if ast.IsExported(name) {
+				return name
+			}
+			if fallback == "unknown" {
+				fallback = name
+			}
+		}
+		if !more {
+			return fallback
+		}
+	}
+}
+
+// isQueryHook reports whether function belongs to one of the pgx tracers of
+// this package, which are on the stack but never issue queries themselves.
+func isQueryHook(function string) bool {
+	return strings.HasPrefix(function, "QueryLogger.") || strings.HasPrefix(function, "QueryTracer.")
+}
+
+// methodName turns "(*PostgresStore).UpdateUserDetail.func1" into "UpdateUserDetail".
+func methodName(function string) string {
+	if i := strings.Index(function, ")."); i >= 0 {
+		function = function[i+2:]
+	}
+	if i := strings.Index(function, "."); i >= 0 {
+		function = function[:i]
+	}
+	return function
+}
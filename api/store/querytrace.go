@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer(storePackage[:len(storePackage)-1])
+
+// QueryTracer is a pgx tracer that records every query as an OpenTelemetry
+// span named after the store method that issued it, e.g. "store.GetConversations".
+// Query arguments are not recorded as they may contain personal data.
+type QueryTracer struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		// Queries outside of a request, e.g. from background tasks, are not traced
+		return ctx
+	}
+	ctx, span := tracer.Start(ctx, "store.query", trace.WithSpanKind(trace.SpanKindClient))
+	if !span.IsRecording() {
+		// Walking the stack for the method name is only worth it for sampled spans
+		return ctx
+	}
+	span.SetName("store." + queryName())
+	span.SetAttributes(
+		attribute.String("db.system.name", "postgresql"),
+		attribute.String("db.operation.name", operationName(data.SQL)),
+		attribute.String("db.query.text", data.SQL),
+	)
+	return ctx
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	} else {
+		span.SetAttributes(attribute.Int64("db.response.returned_rows", data.CommandTag.RowsAffected()))
+	}
+	span.End()
+}
+
+// operationName returns the SQL keyword the query starts with, e.g. "SELECT".
+func operationName(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
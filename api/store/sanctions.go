@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/apperr"
+	"masterdom/api/models"
+)
+
+// --- User Sanction Implementations ---
+
+// AccountBlocked returns the error for a banned or suspended account with the
+// sanction reason and, for a suspension, its end as parameters.
+func AccountBlocked(status models.AccountStatus) *apperr.Error {
+	err := ErrAccountSuspended
+	if status.Status == models.UserStatusBanned {
+		err = ErrAccountBanned
+	} else if status.SuspendedUntil != nil {
+		err = err.With("suspendedUntil", status.SuspendedUntil.UTC().Format(time.RFC3339))
+	}
+	if status.Reason != nil {
+		err = err.With("reason", *status.Reason)
+	}
+	return err
+}
+
+func (s *PostgresStore) GetAccountStatus(ctx context.Context, userID string) (*models.AccountStatus, error) {
+	var status models.AccountStatus
+	err := s.dbpool.QueryRow(ctx,
+		"SELECT status, suspended_until, sanction_reason FROM users WHERE id = $1 AND deleted_at IS NULL",
+		userID).Scan(&status.Status, &status.SuspendedUntil, &status.Reason)
+	if err != nil {
+		return nil, notFound(fmt.Errorf("failed to get account status: %w", err), ErrUserNotFound)
+	}
+	return &status, nil
+}
+
+func (s *PostgresStore) SuspendUser(ctx context.Context, userID, actorID string, payload models.SuspendUserPayload) error {
+	return s.applySanction(ctx, userID, actorID, "suspend", models.UserStatusSuspended, &payload.Reason, &payload.Until)
+}
+
+func (s *PostgresStore) BanUser(ctx context.Context, userID, actorID string, payload models.BanUserPayload) error {
+	return s.applySanction(ctx, userID, actorID, "ban", models.UserStatusBanned, &payload.Reason, nil)
+}
+
+func (s *PostgresStore) LiftUserSanction(ctx context.Context, userID, actorID string, payload models.LiftSanctionPayload) error {
+	var reason *string
+	if payload.Reason != "" {
+		reason = &payload.Reason
+	}
+	return s.applySanction(ctx, userID, actorID, "lift", models.UserStatusActive, reason, nil)
+}
+
+// applySanction updates the user's current status and appends the action to
+// the sanction history in a single audited transaction.
+func (s *PostgresStore) applySanction(ctx context.Context, userID, actorID, action, status string, reason *string, until *time.Time) error {
+	auditAction := "user." + action
+	if action == "lift" {
+		auditAction = "user.lift_sanction"
+	}
+	return s.withAudit(ctx, auditAction, auditTargetUser, &userID, func(tx pgx.Tx) error {
+		var query string
+		var args []interface{}
+		if status == models.UserStatusActive {
+			query = `UPDATE users SET status = 'active', suspended_until = NULL, sanction_reason = NULL,
+					 sanctioned_by = NULL, sanctioned_at = NULL WHERE id = $1 AND deleted_at IS NULL`
+			args = []interface{}{userID}
+		} else {
+			query = `UPDATE users SET status = $2, suspended_until = $3, sanction_reason = $4,
+					 sanctioned_by = $5, sanctioned_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+			args = []interface{}{userID, status, until, reason, actorID}
+		}
+		tag, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to update user status: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO user_sanctions (user_id, action, reason, actor_id, expires_at) VALUES ($1, $2, $3, $4, $5)",
+			userID, action, reason, actorID, until)
+		if err != nil {
+			return fmt.Errorf("failed to record sanction: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *PostgresStore) GetUserSanctions(ctx context.Context, userID string) ([]models.UserSanction, error) {
+	rows, err := s.dbpool.Query(ctx, `
+		SELECT id, user_id, action, reason, actor_id, expires_at, created_at
+		FROM user_sanctions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user sanctions: %w", err)
+	}
+	defer rows.Close()
+
+	sanctions := make([]models.UserSanction, 0)
+	for rows.Next() {
+		var sanction models.UserSanction
+		if err := rows.Scan(&sanction.ID, &sanction.UserID, &sanction.Action, &sanction.Reason, &sanction.ActorID, &sanction.ExpiresAt, &sanction.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user sanction: %w", err)
+		}
+		sanctions = append(sanctions, sanction)
+	}
+	return sanctions, nil
+}
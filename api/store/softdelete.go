@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/models"
+)
+
+// --- Soft Delete Implementations ---
+
+func (s *PostgresStore) RestoreUser(ctx context.Context, userID string) error {
+	return s.restore(ctx, auditTargetUser, userID, "UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL")
+}
+
+func (s *PostgresStore) RestoreOffer(ctx context.Context, offerID string) error {
+	return s.restore(ctx, auditTargetOffer, offerID, "UPDATE offers SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL")
+}
+
+func (s *PostgresStore) RestoreCategory(ctx context.Context, categoryID int) error {
+	return s.restore(ctx, auditTargetCategory, strconv.Itoa(categoryID), `
+		UPDATE service_categories c
+		SET deleted_at = NULL,
+			-- A category whose parent is still deleted comes back as a root category
+			parent_id = CASE WHEN EXISTS (
+				SELECT 1 FROM service_categories p WHERE p.id = c.parent_id AND p.deleted_at IS NULL
+			) THEN c.parent_id END
+		WHERE c.id = $1::int AND c.deleted_at IS NOT NULL`)
+}
+
+// restore clears deleted_at for a single row and reports ErrDeletedRecordNotFound
+// when there was no deleted row with the given ID, or ErrRestoreConflict when a
+// live row with the same unique email or name was created after deletion.
+func (s *PostgresStore) restore(ctx context.Context, targetType, id, query string) error {
+	return s.withAudit(ctx, targetType+".restore", targetType, &id, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, query, id)
+		if err != nil {
+			err = notFound(fmt.Errorf("failed to restore: %w", err), ErrDeletedRecordNotFound)
+			return uniqueViolation(err, ErrRestoreConflict)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrDeletedRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (s *PostgresStore) GetTrash(ctx context.Context) (*models.TrashResponse, error) {
+	var trash models.TrashResponse
+	var err error
+
+	trash.Users, err = s.queryDeleted(ctx, "SELECT id::text, email, deleted_at FROM users WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	trash.Offers, err = s.queryDeleted(ctx, "SELECT id::text, title, deleted_at FROM offers WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	trash.Categories, err = s.queryDeleted(ctx, "SELECT id::text, name, deleted_at FROM service_categories WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	return &trash, nil
+}
+
+func (s *PostgresStore) queryDeleted(ctx context.Context, query string) ([]models.DeletedEntity, error) {
+	rows, err := s.dbpool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deleted records: %w", err)
+	}
+	defer rows.Close()
+
+	entities := make([]models.DeletedEntity, 0)
+	for rows.Next() {
+		var e models.DeletedEntity
+		if err := rows.Scan(&e.ID, &e.Label, &e.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan deleted record: %w", err)
+		}
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+// PurgeDeleted permanently removes rows soft-deleted before deletedBefore.
+// Offers go first so that purging a user does not count them twice.
+func (s *PostgresStore) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (*models.PurgeResult, error) {
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var result models.PurgeResult
+	tag, err := tx.Exec(ctx, "DELETE FROM offers WHERE deleted_at < $1", deletedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge offers: %w", err)
+	}
+	result.Offers = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, "DELETE FROM service_categories WHERE deleted_at < $1", deletedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge categories: %w", err)
+	}
+	result.Categories = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, "DELETE FROM users WHERE deleted_at < $1", deletedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge users: %w", err)
+	}
+	result.Users = tag.RowsAffected()
+
+	return &result, tx.Commit(ctx)
+}
@@ -2,37 +2,124 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"masterdom/api/models"
 )
 
 type Store interface {
+	Ping(ctx context.Context) error
+	GetSchemaVersion(ctx context.Context) (int, bool, error)
 	CreateUser(ctx context.Context, payload models.RegisterPayload, hashedPassword string) (string, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	SaveOIDCLoginState(ctx context.Context, state models.OIDCLoginState) error
+	ConsumeOIDCLoginState(ctx context.Context, state string) (*models.OIDCLoginState, error)
+	ResolveOIDCUser(ctx context.Context, identity models.OIDCIdentity) (*models.User, error)
 	CreateOffer(ctx context.Context, userID string, payload models.CreateOfferPayload) (string, error)
-	GetOffers(ctx context.Context, offerTypeFilter string, search string, categoryID string, userID *string) ([]models.OfferResponse, error)
+	GetOffers(ctx context.Context, filter models.OfferFilter) ([]models.OfferResponse, error)
 	GetAllUsers(ctx context.Context) ([]models.UserDetail, error)
 	GetUserDetailByID(ctx context.Context, userID string) (*models.UserDetail, error)
 	UpdateUserDetail(ctx context.Context, userID string, payload models.UpdateUserPayload) error
+	SetUserAvatar(ctx context.Context, userID, contentType string, image []byte) error
+	GetUserAvatar(ctx context.Context, userID string) (*models.Avatar, error)
+	SetAvatarThumbnail(ctx context.Context, userID string, updatedAt time.Time, thumbnail []byte) error
 	DeleteUser(ctx context.Context, userID string) error
 	IsUserAdmin(ctx context.Context, userID string) (bool, error)
 	GetUserEmailByID(ctx context.Context, userID string) (string, error)
+	GetAccountStatus(ctx context.Context, userID string) (*models.AccountStatus, error)
+	SuspendUser(ctx context.Context, userID, actorID string, payload models.SuspendUserPayload) error
+	BanUser(ctx context.Context, userID, actorID string, payload models.BanUserPayload) error
+	LiftUserSanction(ctx context.Context, userID, actorID string, payload models.LiftSanctionPayload) error
+	GetUserSanctions(ctx context.Context, userID string) ([]models.UserSanction, error)
 	GetAllOffersForAdmin(ctx context.Context) ([]models.AdminOfferResponse, error)
 	UpdateOfferStatus(ctx context.Context, offerID string, payload models.UpdateOfferPayload) error
 	DeleteOffer(ctx context.Context, offerID string) error
-	GetAllCategories(ctx context.Context) ([]models.ServiceCategory, error)
+	RenewOffer(ctx context.Context, offerID string, ttl models.OfferTTL) (time.Time, error)
+	ExpireOffers(ctx context.Context) (int64, error)
+	MarkExpiringOffers(ctx context.Context, expiresBefore time.Time) ([]models.ExpiringOffer, error)
+	GetAllCategories(ctx context.Context, locale string) ([]models.ServiceCategory, error)
+	GetCategoryTranslations(ctx context.Context) (map[int]map[string]models.CategoryTranslation, error)
+	GetCategoryAttributes(ctx context.Context, categoryID int) ([]models.CategoryAttribute, error)
+	CreateCategoryAttribute(ctx context.Context, categoryID int, payload models.CategoryAttributePayload) (int, error)
+	UpdateCategoryAttribute(ctx context.Context, categoryID, attributeID int, payload models.CategoryAttributePayload) error
+	DeleteCategoryAttribute(ctx context.Context, categoryID, attributeID int) error
 	CreateCategory(ctx context.Context, payload models.CategoryPayload) (int, error)
-	UpdateCategory(ctx context.Context, categoryID int, payload models.CategoryPayload) error
-	DeleteCategory(ctx context.Context, categoryID int) error
+	UpdateCategory(ctx context.Context, categoryID int, payload models.UpdateCategoryPayload) error
+	DeleteCategory(ctx context.Context, categoryID int, reassignTo *int) error
 	GetAdminStats(ctx context.Context) (*models.AdminStats, error)
+
+	// Operator methods
+	SetUserPassword(ctx context.Context, userID, hashedPassword string) error
+	DeactivateOffers(ctx context.Context, filter models.OfferDeactivationFilter) ([]string, error)
+	RecomputeRatings(ctx context.Context) (int, error)
+
+	// Soft delete methods
+	RestoreUser(ctx context.Context, userID string) error
+	RestoreOffer(ctx context.Context, offerID string) error
+	RestoreCategory(ctx context.Context, categoryID int) error
+	GetTrash(ctx context.Context) (*models.TrashResponse, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (*models.PurgeResult, error)
+
+	// Audit methods
+	GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+
 	CreateOfferResponse(ctx context.Context, response *models.OfferApplication) (string, error)
 	GetOfferApplications(ctx context.Context, offerID string) ([]models.OfferApplication, error)
 	GetOfferAuthor(ctx context.Context, offerID string) (string, error)
+	UpdateApplicationStatus(ctx context.Context, offerID, applicationID, status string) error
+	GetUserJobs(ctx context.Context, userID string) ([]models.Job, error)
+	UpdateJobStatus(ctx context.Context, jobID, userID, status string) (bool, error)
+	CreateJobReview(ctx context.Context, jobID, authorID string, payload models.CreateReviewPayload) (string, error)
+	RecalculateUserRating(ctx context.Context, userID string) error
+
+	// Notification methods
+	CreateNotification(ctx context.Context, userID, eventType string, payload map[string]interface{}) error
+	GetNotifications(ctx context.Context, userID string, filter models.NotificationFilter) (*models.NotificationList, error)
+	MarkNotificationRead(ctx context.Context, userID, notificationID string) error
+	MarkAllNotificationsRead(ctx context.Context, userID string) (int64, error)
+	GetNotificationPreferences(ctx context.Context, userID string) (map[string]bool, error)
+	UpdateNotificationPreferences(ctx context.Context, userID string, preferences map[string]bool) error
+
+	// Task queue methods
+	EnqueueTask(ctx context.Context, task models.NewTask) (int64, error)
+	ClaimTask(ctx context.Context, kinds []string, workerID string, lease time.Duration) (*models.Task, error)
+	CompleteTask(ctx context.Context, taskID int64) error
+	FailTask(ctx context.Context, taskID int64, taskErr string, retryAt *time.Time) error
+	GetTasks(ctx context.Context, filter models.TaskFilter) ([]models.Task, error)
+	GetTask(ctx context.Context, taskID int64) (*models.Task, error)
+	RetryTask(ctx context.Context, taskID int64) error
+	PurgeFinishedTasks(ctx context.Context, finishedBefore time.Time) (int64, error)
+
+	// API key methods
+	CreateAPIKey(ctx context.Context, userID string, payload models.CreateAPIKeyPayload, prefix, keyHash string) (*models.APIKey, error)
+	GetAPIKeys(ctx context.Context, userID *string) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID string, ownerID *string) error
+	GetAPIKeyCredentials(ctx context.Context, prefix string) (*models.APIKeyCredentials, error)
+	TouchAPIKey(ctx context.Context, keyID, ip string) error
+
+	// Webhook methods
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, webhookID int) (*models.Webhook, error)
+	CreateWebhook(ctx context.Context, payload models.WebhookPayload, secret, createdBy string) (int, error)
+	UpdateWebhook(ctx context.Context, webhookID int, payload models.WebhookPayload) error
+	RotateWebhookSecret(ctx context.Context, webhookID int, secret string) error
+	DeleteWebhook(ctx context.Context, webhookID int) error
+	EnqueueWebhookTest(ctx context.Context, webhookID int) (string, error)
+	RecordWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+	GetWebhookDeliveries(ctx context.Context, webhookID, limit, offset int) ([]models.WebhookDelivery, error)
+
+	// Email outbox methods
+	GetOutboxEmail(ctx context.Context, emailID int64) (*models.OutboxEmail, error)
+	MarkEmailSent(ctx context.Context, emailID int64) error
+	MarkEmailFailed(ctx context.Context, emailID int64, sendErr string) error
+	EnqueueMessageDigests(ctx context.Context, createdBefore time.Time) (int64, error)
 
 	// Chat methods
 	InitiateChat(ctx context.Context, offerID, initiatorID, recipientID string) (string, error)
@@ -42,6 +129,20 @@ type Store interface {
 	GetConversations(ctx context.Context, userID string) ([]models.ConversationPreview, error)
 }
 
+// activeUserCondition returns a SQL predicate that is true when the user
+// aliased as alias is not currently suspended or banned.
+func activeUserCondition(alias string) string {
+	return fmt.Sprintf("(%[1]s.status = 'active' OR (%[1]s.status = 'suspended' AND %[1]s.suspended_until <= NOW()))", alias)
+}
+
+// querier is satisfied by both the pool and a transaction, so side effects
+// such as notifications and queued tasks can be written inside the
+// transaction of the event that triggered them.
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type PostgresStore struct {
 	dbpool *pgxpool.Pool
 }
@@ -57,12 +158,22 @@ func (s *PostgresStore) CreateUser(ctx context.Context, payload models.RegisterP
 	}
 	defer tx.Rollback(ctx)
 
+	userID, err := insertUser(ctx, tx, payload, &hashedPassword)
+	if err != nil {
+		return "", err
+	}
+	return userID, tx.Commit(ctx)
+}
+
+// insertUser creates the user with its details and publishes the registration
+// event. A nil hashedPassword creates an account that can only sign in via OIDC.
+func insertUser(ctx context.Context, tx pgx.Tx, payload models.RegisterPayload, hashedPassword *string) (string, error) {
 	var userID string
-	err = tx.QueryRow(ctx,
-		"INSERT INTO users (email, password_hash, role) VALUES ($1, $2, 'user') RETURNING id",
-		payload.Email, hashedPassword).Scan(&userID)
+	err := tx.QueryRow(ctx,
+		"INSERT INTO users (email, password_hash, role, locale) VALUES ($1, $2, 'user', COALESCE(NULLIF($3, ''), 'ru')) RETURNING id",
+		payload.Email, hashedPassword, payload.Locale).Scan(&userID)
 	if err != nil {
-		return "", fmt.Errorf("failed to create user: %w", err)
+		return "", uniqueViolation(fmt.Errorf("failed to create user: %w", err), ErrEmailTaken)
 	}
 
 	_, err = tx.Exec(ctx,
@@ -72,56 +183,89 @@ func (s *PostgresStore) CreateUser(ctx context.Context, payload models.RegisterP
 		return "", fmt.Errorf("failed to create user_details: %w", err)
 	}
 
-	return userID, tx.Commit(ctx)
+	err = publishEvent(ctx, tx, models.WebhookEventUserRegistered, map[string]interface{}{
+		"userId":    userID,
+		"email":     payload.Email,
+		"firstName": payload.FirstName,
+		"lastName":  payload.LastName,
+	})
+	if err != nil {
+		return "", err
+	}
+	return userID, nil
 }
 
 func (s *PostgresStore) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
 	err := s.dbpool.QueryRow(ctx,
-		"SELECT id, email, password_hash, role FROM users WHERE email = $1",
-		email).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role)
+		"SELECT id, email, COALESCE(password_hash, ''), role, status, suspended_until, sanction_reason FROM users WHERE email = $1 AND deleted_at IS NULL",
+		email).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role, &user.Status, &user.SuspendedUntil, &user.Reason)
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, notFound(fmt.Errorf("failed to get user by email: %w", err), ErrUserNotFound)
 	}
 	return &user, nil
 }
 
-func (s *PostgresStore) GetOffers(ctx context.Context, offerTypeFilter string, search string, categoryID string, userID *string) ([]models.OfferResponse, error) {
+func (s *PostgresStore) GetOffers(ctx context.Context, filter models.OfferFilter) ([]models.OfferResponse, error) {
 	baseQuery := `
 		SELECT o.id, o.title, o.description, o.offer_type, o.created_at,
 			   u.id as author_id,
 			   up.first_name as author_first_name,
 			   CASE WHEN $1::UUID IS NOT NULL THEN EXISTS (
 				   SELECT 1 FROM offer_responses orr WHERE orr.offer_id = o.id AND orr.applicant_id = $1::UUID
-			   ) ELSE FALSE END as has_responded
+			   ) ELSE FALSE END as has_responded,
+			   o.category_id, o.attributes, o.expires_at
 		FROM offers o
 		JOIN users u ON o.author_id = u.id
 		LEFT JOIN user_details up ON u.id = up.user_id
-		WHERE o.is_active = true`
+		WHERE o.is_active = true AND o.deleted_at IS NULL AND u.deleted_at IS NULL
+		  AND (o.expires_at IS NULL OR o.expires_at > NOW())
+		  AND ` + activeUserCondition("u")
 
-	args := []interface{}{userID}
+	args := []interface{}{filter.ViewerID}
 	whereClauses := []string{}
 	argCount := 2 // Start at 2 because $1 is for userID
 
-	if offerTypeFilter != "" {
+	if filter.OfferType != "" {
 		whereClauses = append(whereClauses, fmt.Sprintf("o.offer_type = $%d", argCount))
-		args = append(args, offerTypeFilter)
+		args = append(args, filter.OfferType)
 		argCount++
 	}
 
-	if search != "" {
+	if filter.Search != "" {
 		// Use separate placeholders for title and description search
 		whereClauses = append(whereClauses, fmt.Sprintf("(LOWER(o.title) LIKE $%d OR LOWER(o.description) LIKE $%d)", argCount, argCount+1))
-		args = append(args, "%"+strings.ToLower(search)+"%", "%"+strings.ToLower(search)+"%")
+		args = append(args, "%"+strings.ToLower(filter.Search)+"%", "%"+strings.ToLower(filter.Search)+"%")
 		argCount += 2
 	}
 
-	if categoryID != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("o.category_id = $%d", argCount))
-		args = append(args, categoryID)
+	if filter.Category != "" {
+		// Match the category (by ID or slug) together with all its subcategories
+		whereClauses = append(whereClauses, fmt.Sprintf("o.category_id IN ("+categoryDescendantsQuery+")", argCount))
+		args = append(args, filter.Category)
 		argCount++
 	}
 
+	for _, attr := range filter.Attributes {
+		switch attr.Op {
+		case models.AttributeFilterEq:
+			// ->> renders numbers and booleans as text, so one comparison covers every type
+			whereClauses = append(whereClauses, fmt.Sprintf("o.attributes ->> $%d = $%d", argCount, argCount+1))
+		case models.AttributeFilterMin, models.AttributeFilterMax:
+			op := ">="
+			if attr.Op == models.AttributeFilterMax {
+				op = "<="
+			}
+			whereClauses = append(whereClauses, fmt.Sprintf(
+				"(jsonb_typeof(o.attributes -> $%[1]d) = 'number' AND (o.attributes ->> $%[1]d)::numeric %[3]s $%[2]d::numeric)",
+				argCount, argCount+1, op))
+		default:
+			return nil, fmt.Errorf("unsupported attribute filter %q", attr.Op)
+		}
+		args = append(args, attr.Key, attr.Value)
+		argCount += 2
+	}
+
 	if len(whereClauses) > 0 {
 		baseQuery += " AND " + strings.Join(whereClauses, " AND ")
 	}
@@ -137,18 +281,17 @@ func (s *PostgresStore) GetOffers(ctx context.Context, offerTypeFilter string, s
 	offers := make([]models.OfferResponse, 0)
 	for rows.Next() {
 		var offer models.OfferResponse
-		if err := rows.Scan(&offer.ID, &offer.Title, &offer.Description, &offer.OfferType, &offer.CreatedAt, &offer.AuthorID, &offer.AuthorFirstName, &offer.HasResponded); err != nil {
-			log.Printf("Error scanning offer row: %v", err)
-			continue
+		if err := rows.Scan(&offer.ID, &offer.Title, &offer.Description, &offer.OfferType, &offer.CreatedAt, &offer.AuthorID, &offer.AuthorFirstName, &offer.HasResponded, &offer.CategoryID, &offer.Attributes, &offer.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan offer: %w", err)
 		}
 		offers = append(offers, offer)
 	}
-	return offers, nil
+	return offers, rows.Err()
 }
 
 func (s *PostgresStore) IsUserAdmin(ctx context.Context, userID string) (bool, error) {
 	var role string
-	err := s.dbpool.QueryRow(ctx, "SELECT role FROM users WHERE id = $1", userID).Scan(&role)
+	err := s.dbpool.QueryRow(ctx, "SELECT role FROM users WHERE id = $1 AND deleted_at IS NULL", userID).Scan(&role)
 	if err != nil {
 		return false, nil
 	}
@@ -158,24 +301,49 @@ func (s *PostgresStore) IsUserAdmin(ctx context.Context, userID string) (bool, e
 // ... Implementations for all other interface methods ...
 
 func (s *PostgresStore) CreateOffer(ctx context.Context, userID string, payload models.CreateOfferPayload) (string, error) {
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	var offerID string
-	err := s.dbpool.QueryRow(ctx,
-		`INSERT INTO offers (author_id, offer_type, title, description, category_id)
-		 VALUES ($1, $2, $3, $4, $5) RETURNING id`,
-		userID, payload.OfferType, payload.Title, payload.Description, payload.CategoryID).Scan(&offerID)
+	err = tx.QueryRow(ctx,
+		`INSERT INTO offers (author_id, offer_type, title, description, category_id, attributes, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, COALESCE($6, '{}'::jsonb), $7) RETURNING id`,
+		userID, payload.OfferType, payload.Title, payload.Description, payload.CategoryID, payload.Attributes, payload.ExpiresAt).Scan(&offerID)
 
 	if err != nil {
 		return "", fmt.Errorf("failed to create offer: %w", err)
 	}
+
+	err = publishEvent(ctx, tx, models.WebhookEventOfferCreated, map[string]interface{}{
+		"offerId":     offerID,
+		"authorId":    userID,
+		"offerType":   payload.OfferType,
+		"title":       payload.Title,
+		"description": payload.Description,
+		"categoryId":  payload.CategoryID,
+		"attributes":  payload.Attributes,
+		"expiresAt":   payload.ExpiresAt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return offerID, nil
 }
 
 func (s *PostgresStore) GetAllUsers(ctx context.Context) ([]models.UserDetail, error) {
 	rows, err := s.dbpool.Query(ctx,
-		`SELECT u.id, u.email, u.role, u.created_at, u.updated_at, 
+		`SELECT u.id, u.email, u.role, u.status, u.suspended_until, u.sanction_reason, u.locale, u.created_at, u.updated_at,
 				up.first_name, up.last_name, up.phone_number, up.bio, up.years_of_experience, up.average_rating
 		 FROM users u
 		 LEFT JOIN user_details up ON u.id = up.user_id
+		 WHERE u.deleted_at IS NULL
 		 ORDER BY u.created_at DESC`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch all users: %w", err)
@@ -186,9 +354,8 @@ func (s *PostgresStore) GetAllUsers(ctx context.Context) ([]models.UserDetail, e
 	for rows.Next() {
 		var user models.UserDetail
 		if err := rows.Scan(
-			&user.ID, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt,
-			&user.FirstName, &user.LastName, &user.PhoneNumber, &user.Bio, &user.YearsOfExperience, &user.AverageRating);
-			err != nil {
+			&user.ID, &user.Email, &user.Role, &user.Status, &user.SuspendedUntil, &user.SanctionReason, &user.Locale, &user.CreatedAt, &user.UpdatedAt,
+			&user.FirstName, &user.LastName, &user.PhoneNumber, &user.Bio, &user.YearsOfExperience, &user.AverageRating); err != nil {
 			return nil, fmt.Errorf("failed to scan user detail: %w", err)
 		}
 		users = append(users, user)
@@ -199,103 +366,127 @@ func (s *PostgresStore) GetAllUsers(ctx context.Context) ([]models.UserDetail, e
 func (s *PostgresStore) GetUserDetailByID(ctx context.Context, userID string) (*models.UserDetail, error) {
 	var user models.UserDetail
 	err := s.dbpool.QueryRow(ctx,
-		`SELECT u.id, u.email, u.role, u.created_at, u.updated_at, 
+		`SELECT u.id, u.email, u.role, u.status, u.suspended_until, u.sanction_reason, u.locale, u.created_at, u.updated_at,
 				up.first_name, up.last_name, up.phone_number, up.bio, up.years_of_experience, up.average_rating
 		 FROM users u
 		 LEFT JOIN user_details up ON u.id = up.user_id
-		 WHERE u.id = $1`,
+		 WHERE u.id = $1 AND u.deleted_at IS NULL`,
 		userID).Scan(
-		&user.ID, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+		&user.ID, &user.Email, &user.Role, &user.Status, &user.SuspendedUntil, &user.SanctionReason, &user.Locale, &user.CreatedAt, &user.UpdatedAt,
 		&user.FirstName, &user.LastName, &user.PhoneNumber, &user.Bio, &user.YearsOfExperience, &user.AverageRating)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by ID: %w", err)
+		return nil, notFound(fmt.Errorf("failed to get user by ID: %w", err), ErrUserNotFound)
 	}
 	return &user, nil
 }
 
 func (s *PostgresStore) UpdateUserDetail(ctx context.Context, userID string, payload models.UpdateUserPayload) error {
-	tx, err := s.dbpool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
-	// Handle role update in the 'users' table
-	if payload.IsAdmin != nil {
-		newRole := "user" // Default non-admin role
-		if *payload.IsAdmin {
-			newRole = "admin"
-		}
-		_, err := tx.Exec(ctx, "UPDATE users SET role = $1 WHERE id = $2", newRole, userID)
+	return s.withAudit(ctx, "user.update", auditTargetUser, &userID, func(tx pgx.Tx) error {
+		var exists bool
+		err := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND deleted_at IS NULL)", userID).Scan(&exists)
 		if err != nil {
-			return fmt.Errorf("failed to update user role: %w", err)
+			return notFound(fmt.Errorf("failed to check user: %w", err), ErrUserNotFound)
+		}
+		if !exists {
+			return ErrUserNotFound
 		}
-	}
 
-	// Handle profile updates in the 'user_details' table
-	query := "UPDATE user_details SET updated_at = NOW()"
-	args := []interface{}{}
-	argCounter := 1
+		// Handle role update in the 'users' table
+		if payload.IsAdmin != nil {
+			newRole := "user" // Default non-admin role
+			if *payload.IsAdmin {
+				newRole = "admin"
+			}
+			_, err := tx.Exec(ctx, "UPDATE users SET role = $1 WHERE id = $2", newRole, userID)
+			if err != nil {
+				return fmt.Errorf("failed to update user role: %w", err)
+			}
+		}
 
-	if payload.FirstName != nil {
-		query += fmt.Sprintf(", first_name = $%d", argCounter)
-		args = append(args, *payload.FirstName)
-		argCounter++
-	}
-	if payload.LastName != nil {
-		query += fmt.Sprintf(", last_name = $%d", argCounter)
-		args = append(args, *payload.LastName)
-		argCounter++
-	}
-	if payload.PhoneNumber != nil {
-		query += fmt.Sprintf(", phone_number = $%d", argCounter)
-		args = append(args, *payload.PhoneNumber)
-		argCounter++
-	}
-	if payload.Bio != nil {
-		query += fmt.Sprintf(", bio = $%d", argCounter)
-		args = append(args, *payload.Bio)
-		argCounter++
-	}
-	if payload.YearsOfExperience != nil {
-		query += fmt.Sprintf(", years_of_experience = $%d", argCounter)
-		args = append(args, *payload.YearsOfExperience)
-		argCounter++
-	}
+		if payload.Locale != nil {
+			_, err := tx.Exec(ctx, "UPDATE users SET locale = $1 WHERE id = $2", *payload.Locale, userID)
+			if err != nil {
+				return fmt.Errorf("failed to update user locale: %w", err)
+			}
+		}
 
-	// Only run the update if there are fields to update
-	if argCounter > 1 {
-		query += fmt.Sprintf(" WHERE user_id = $%d", argCounter)
-		args = append(args, userID)
+		// Handle profile updates in the 'user_details' table
+		query := "UPDATE user_details SET updated_at = NOW()"
+		args := []interface{}{}
+		argCounter := 1
 
-		_, err := tx.Exec(ctx, query, args...)
-		if err != nil {
-			return fmt.Errorf("failed to update user_details: %w", err)
+		if payload.FirstName != nil {
+			query += fmt.Sprintf(", first_name = $%d", argCounter)
+			args = append(args, *payload.FirstName)
+			argCounter++
+		}
+		if payload.LastName != nil {
+			query += fmt.Sprintf(", last_name = $%d", argCounter)
+			args = append(args, *payload.LastName)
+			argCounter++
+		}
+		if payload.PhoneNumber != nil {
+			query += fmt.Sprintf(", phone_number = $%d", argCounter)
+			args = append(args, *payload.PhoneNumber)
+			argCounter++
+		}
+		if payload.Bio != nil {
+			query += fmt.Sprintf(", bio = $%d", argCounter)
+			args = append(args, *payload.Bio)
+			argCounter++
+		}
+		if payload.YearsOfExperience != nil {
+			query += fmt.Sprintf(", years_of_experience = $%d", argCounter)
+			args = append(args, *payload.YearsOfExperience)
+			argCounter++
 		}
-	}
 
-	return tx.Commit(ctx)
+		// Only run the update if there are fields to update
+		if argCounter > 1 {
+			query += fmt.Sprintf(" WHERE user_id = $%d", argCounter)
+			args = append(args, userID)
+
+			_, err := tx.Exec(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("failed to update user_details: %w", err)
+			}
+		}
+		return nil
+	})
 }
+
 func (s *PostgresStore) DeleteUser(ctx context.Context, userID string) error {
-	_, err := s.dbpool.Exec(ctx, "DELETE FROM users WHERE id = $1", userID)
-	return err
+	return s.withAudit(ctx, "user.delete", auditTargetUser, &userID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", userID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
 }
 
 func (s *PostgresStore) GetUserEmailByID(ctx context.Context, userID string) (string, error) {
 	var email string
 	err := s.dbpool.QueryRow(ctx, "SELECT email FROM users WHERE id = $1", userID).Scan(&email)
-	return email, err
+	if err != nil {
+		return "", notFound(fmt.Errorf("failed to get user email: %w", err), ErrUserNotFound)
+	}
+	return email, nil
 }
 
 func (s *PostgresStore) GetAllOffersForAdmin(ctx context.Context) ([]models.AdminOfferResponse, error) {
 	rows, err := s.dbpool.Query(ctx,
-		`SELECT o.id, o.title, o.description, o.offer_type, o.is_active, o.created_at, o.updated_at,
+		`SELECT o.id, o.title, o.description, o.offer_type, o.is_active, o.expires_at, o.created_at, o.updated_at,
 		        u.id as author_id, u.email as author_email,
 		        up.first_name as author_first_name
 		 FROM offers o
 		 JOIN users u ON o.author_id = u.id
 		 LEFT JOIN user_details up ON u.id = up.user_id
+		 WHERE o.deleted_at IS NULL
 		 ORDER BY o.created_at DESC`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch all offers for admin: %w", err)
@@ -306,9 +497,8 @@ func (s *PostgresStore) GetAllOffersForAdmin(ctx context.Context) ([]models.Admi
 	for rows.Next() {
 		var offer models.AdminOfferResponse
 		if err := rows.Scan(
-			&offer.ID, &offer.Title, &offer.Description, &offer.OfferType, &offer.IsActive,
-			&offer.CreatedAt, &offer.UpdatedAt, &offer.AuthorID, &offer.AuthorEmail, &offer.AuthorFirstName);
-			err != nil {
+			&offer.ID, &offer.Title, &offer.Description, &offer.OfferType, &offer.IsActive, &offer.ExpiresAt,
+			&offer.CreatedAt, &offer.UpdatedAt, &offer.AuthorID, &offer.AuthorEmail, &offer.AuthorFirstName); err != nil {
 			return nil, fmt.Errorf("failed to scan admin offer: %w", err)
 		}
 		offers = append(offers, offer)
@@ -321,58 +511,40 @@ func (s *PostgresStore) UpdateOfferStatus(ctx context.Context, offerID string, p
 	if payload.IsActive == nil {
 		return fmt.Errorf("is_active must be provided")
 	}
-	_, err := s.dbpool.Exec(ctx, "UPDATE offers SET is_active = $1, updated_at = NOW() WHERE id = $2", *payload.IsActive, offerID)
-	return err
+	return s.withAudit(ctx, "offer.update_status", auditTargetOffer, &offerID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "UPDATE offers SET is_active = $1, updated_at = NOW() WHERE id = $2 AND deleted_at IS NULL", *payload.IsActive, offerID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrOfferNotFound
+		}
+		return nil
+	})
 }
 
 func (s *PostgresStore) DeleteOffer(ctx context.Context, offerID string) error {
-	_, err := s.dbpool.Exec(ctx, "DELETE FROM offers WHERE id = $1", offerID)
-	return err
-}
-
-func (s *PostgresStore) GetAllCategories(ctx context.Context) ([]models.ServiceCategory, error) {
-	rows, err := s.dbpool.Query(ctx, "SELECT id, name, description FROM service_categories ORDER BY name")
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch categories: %w", err)
-	}
-	defer rows.Close()
-
-	categories := make([]models.ServiceCategory, 0)
-	for rows.Next() {
-		var cat models.ServiceCategory
-		if err := rows.Scan(&cat.ID, &cat.Name, &cat.Description); err != nil {
-			return nil, fmt.Errorf("failed to scan category: %w", err)
+	return s.withAudit(ctx, "offer.delete", auditTargetOffer, &offerID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "UPDATE offers SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", offerID)
+		if err != nil {
+			return err
 		}
-		categories = append(categories, cat)
-	}
-	return categories, nil
-}
-
-func (s *PostgresStore) CreateCategory(ctx context.Context, payload models.CategoryPayload) (int, error) {
-	var categoryID int
-	err := s.dbpool.QueryRow(ctx, "INSERT INTO service_categories (name, description) VALUES ($1, $2) RETURNING id", payload.Name, payload.Description).Scan(&categoryID)
-	return categoryID, err
-}
-
-func (s *PostgresStore) UpdateCategory(ctx context.Context, categoryID int, payload models.CategoryPayload) error {
-	_, err := s.dbpool.Exec(ctx, "UPDATE service_categories SET name = $1, description = $2 WHERE id = $3", payload.Name, payload.Description, categoryID)
-	return err
-}
-
-func (s *PostgresStore) DeleteCategory(ctx context.Context, categoryID int) error {
-	_, err := s.dbpool.Exec(ctx, "DELETE FROM service_categories WHERE id = $1", categoryID)
-	return err
+		if tag.RowsAffected() == 0 {
+			return ErrOfferNotFound
+		}
+		return nil
+	})
 }
 
 func (s *PostgresStore) GetAdminStats(ctx context.Context) (*models.AdminStats, error) {
 	var stats models.AdminStats
 	err := s.dbpool.QueryRow(ctx, `
 		SELECT
-			(SELECT COUNT(*) FROM users),
-			(SELECT COUNT(*) FROM offers),
+			(SELECT COUNT(*) FROM users WHERE deleted_at IS NULL),
+			(SELECT COUNT(*) FROM offers WHERE deleted_at IS NULL),
 			(SELECT COUNT(*) FROM jobs),
-			(SELECT COUNT(*) FROM offers WHERE offer_type = 'request_for_service'),
-			(SELECT COUNT(*) FROM offers WHERE offer_type = 'service_offer')
+			(SELECT COUNT(*) FROM offers WHERE offer_type = 'request_for_service' AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM offers WHERE offer_type = 'service_offer' AND deleted_at IS NULL)
 	`).Scan(
 		&stats.TotalUsers,
 		&stats.TotalOffers,
@@ -380,43 +552,143 @@ func (s *PostgresStore) GetAdminStats(ctx context.Context) (*models.AdminStats,
 		&stats.TotalServiceRequests,
 		&stats.TotalServiceOffers,
 	)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get admin stats: %w", err)
-		}
-		return &stats, nil
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin stats: %w", err)
 	}
-	
+	return &stats, nil
+}
+
 func (s *PostgresStore) CreateOfferResponse(ctx context.Context, response *models.OfferApplication) (string, error) {
-	// First, check if a response from this applicant for this offer already exists.
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Only an offer listed by GetOffers accepts applications: a missing,
+	// hidden or expired offer, an offer of a blocked author, or a malformed ID
+	// is reported as ErrOfferNotFound.
+	var authorID, offerTitle, applicantName string
+	err = tx.QueryRow(ctx, `
+		SELECT o.author_id, o.title, COALESCE(ud.first_name, '')
+		FROM offers o
+		JOIN users u ON u.id = o.author_id
+		LEFT JOIN user_details ud ON ud.user_id = $2
+		WHERE o.id = $1 AND o.deleted_at IS NULL AND o.is_active
+		  AND (o.expires_at IS NULL OR o.expires_at > NOW())
+		  AND u.deleted_at IS NULL AND `+activeUserCondition("u")+`
+		FOR SHARE OF o`, response.OfferID, response.ApplicantID).Scan(&authorID, &offerTitle, &applicantName)
+	if err != nil {
+		return "", notFound(err, ErrOfferNotFound)
+	}
+
+	// Check if a response from this applicant for this offer already exists.
 	var exists bool
-	err := s.dbpool.QueryRow(ctx,
+	err = tx.QueryRow(ctx,
 		"SELECT EXISTS(SELECT 1 FROM offer_responses WHERE offer_id = $1 AND applicant_id = $2)",
 		response.OfferID, response.ApplicantID).Scan(&exists)
-
 	if err != nil {
 		return "", fmt.Errorf("failed to check for existing response: %w", err)
 	}
 	if exists {
-		return "", fmt.Errorf("response already exists") // Return a specific error
+		return "", ErrApplicationExists
 	}
 
 	// If no response exists, create a new one.
 	var id string
 	query := `INSERT INTO offer_responses (offer_id, applicant_id, message)
 			  VALUES ($1, $2, $3) RETURNING id`
-	err = s.dbpool.QueryRow(ctx, query, response.OfferID, response.ApplicantID, response.Message).Scan(&id)
+	err = tx.QueryRow(ctx, query, response.OfferID, response.ApplicantID, response.Message).Scan(&id)
 	if err != nil {
 		return "", fmt.Errorf("failed to create offer response: %w", err)
 	}
+
+	// Notify the offer author about the new application
+	if authorID != response.ApplicantID {
+		err = notify(ctx, tx, authorID, models.NotificationApplicationCreated, map[string]interface{}{
+			"offerId":            response.OfferID,
+			"offerTitle":         offerTitle,
+			"applicationId":      id,
+			"applicantId":        response.ApplicantID,
+			"applicantFirstName": applicantName,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = publishEvent(ctx, tx, models.WebhookEventApplicationCreated, map[string]interface{}{
+		"applicationId": id,
+		"offerId":       response.OfferID,
+		"applicantId":   response.ApplicantID,
+		"message":       response.Message,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return id, nil
 }
 
+// UpdateApplicationStatus accepts or rejects an application to the offer and
+// notifies the applicant when the status changes.
+func (s *PostgresStore) UpdateApplicationStatus(ctx context.Context, offerID, applicationID, status string) error {
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var applicantID, previousStatus, offerTitle, offerType, authorID string
+	err = tx.QueryRow(ctx, `
+		SELECT r.applicant_id, r.status, o.title, o.offer_type, o.author_id
+		FROM offer_responses r
+		JOIN offers o ON o.id = r.offer_id AND o.deleted_at IS NULL
+		WHERE r.id = $1 AND r.offer_id = $2
+		FOR UPDATE OF r`, applicationID, offerID).Scan(&applicantID, &previousStatus, &offerTitle, &offerType, &authorID)
+	if err != nil {
+		return notFound(fmt.Errorf("failed to get application: %w", err), ErrApplicationNotFound)
+	}
+	if previousStatus == status {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE offer_responses SET status = $1 WHERE id = $2", status, applicationID); err != nil {
+		return fmt.Errorf("failed to update application status: %w", err)
+	}
+
+	eventType := models.NotificationApplicationAccepted
+	if status == "rejected" {
+		eventType = models.NotificationApplicationRejected
+	}
+	err = notify(ctx, tx, applicantID, eventType, map[string]interface{}{
+		"offerId":       offerID,
+		"offerTitle":    offerTitle,
+		"applicationId": applicationID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if status == "accepted" {
+		if err := createJob(ctx, tx, offerID, offerType, offerTitle, authorID, applicantID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (s *PostgresStore) GetOfferApplications(ctx context.Context, offerID string) ([]models.OfferApplication, error) {
 	query := `
 		SELECT
 			r.id, r.offer_id, r.applicant_id, r.message, r.status, r.created_at,
 			ud.first_name, ud.average_rating
 		FROM offer_responses r
+		JOIN users u ON r.applicant_id = u.id AND u.deleted_at IS NULL
 		JOIN user_details ud ON r.applicant_id = ud.user_id
 		WHERE r.offer_id = $1
 		ORDER BY r.created_at DESC
@@ -444,9 +716,9 @@ func (s *PostgresStore) GetOfferApplications(ctx context.Context, offerID string
 
 func (s *PostgresStore) GetOfferAuthor(ctx context.Context, offerID string) (string, error) {
 	var authorID string
-	err := s.dbpool.QueryRow(ctx, "SELECT author_id FROM offers WHERE id = $1", offerID).Scan(&authorID)
+	err := s.dbpool.QueryRow(ctx, "SELECT author_id FROM offers WHERE id = $1 AND deleted_at IS NULL", offerID).Scan(&authorID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get offer author: %w", err)
+		return "", notFound(fmt.Errorf("failed to get offer author: %w", err), ErrOfferNotFound)
 	}
 	return authorID, nil
 }
@@ -477,7 +749,7 @@ func (s *PostgresStore) InitiateChat(ctx context.Context, offerID, initiatorID,
 		return conversationID, nil
 	}
 	// If no rows are found, that's expected, so we continue. Any other error is a problem.
-	if err != nil && err.Error() != "no rows in result set" {
+	if !errors.Is(err, pgx.ErrNoRows) {
 		return "", fmt.Errorf("failed to check for existing conversation: %w", err)
 	}
 
@@ -510,7 +782,7 @@ func (s *PostgresStore) GetChatDetails(ctx context.Context, conversationID, user
 		return nil, fmt.Errorf("failed to verify participant: %w", err)
 	}
 	if !isParticipant {
-		return nil, fmt.Errorf("user is not a participant in this conversation")
+		return nil, ErrNotParticipant
 	}
 
 	// Get conversation and offer details
@@ -527,7 +799,7 @@ func (s *PostgresStore) GetChatDetails(ctx context.Context, conversationID, user
 
 	// Get participant details
 	rows, err := s.dbpool.Query(ctx, `
-		SELECT u.id, u.email, u.role, u.created_at, u.updated_at, 
+		SELECT u.id, u.email, u.role, u.status, u.created_at, u.updated_at,
 			   ud.first_name, ud.last_name, ud.phone_number, ud.bio, ud.years_of_experience, ud.average_rating
 		FROM users u
 		JOIN user_details ud ON u.id = ud.user_id
@@ -542,7 +814,7 @@ func (s *PostgresStore) GetChatDetails(ctx context.Context, conversationID, user
 	participants := make([]models.UserDetail, 0)
 	for rows.Next() {
 		var p models.UserDetail
-		if err := rows.Scan(&p.ID, &p.Email, &p.Role, &p.CreatedAt, &p.UpdatedAt, &p.FirstName, &p.LastName, &p.PhoneNumber, &p.Bio, &p.YearsOfExperience, &p.AverageRating); err != nil {
+		if err := rows.Scan(&p.ID, &p.Email, &p.Role, &p.Status, &p.CreatedAt, &p.UpdatedAt, &p.FirstName, &p.LastName, &p.PhoneNumber, &p.Bio, &p.YearsOfExperience, &p.AverageRating); err != nil {
 			return nil, fmt.Errorf("failed to scan participant detail: %w", err)
 		}
 		participants = append(participants, p)
@@ -553,8 +825,26 @@ func (s *PostgresStore) GetChatDetails(ctx context.Context, conversationID, user
 }
 
 func (s *PostgresStore) PostMessage(ctx context.Context, conversationID, senderID, content string) (*models.MessageResponse, error) {
+	tx, err := s.dbpool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Only participants may post to the conversation
+	var isParticipant bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM conversation_participants WHERE conversation_id = $1 AND user_id = $2)",
+		conversationID, senderID).Scan(&isParticipant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify participant: %w", err)
+	}
+	if !isParticipant {
+		return nil, ErrNotParticipant
+	}
+
 	var msg models.MessageResponse
-	err := s.dbpool.QueryRow(ctx, `
+	err = tx.QueryRow(ctx, `
 		WITH inserted_message AS (
 			INSERT INTO messages (conversation_id, sender_id, content)
 			VALUES ($1, $2, $3)
@@ -569,9 +859,58 @@ func (s *PostgresStore) PostMessage(ctx context.Context, conversationID, senderI
 	if err != nil {
 		return nil, fmt.Errorf("failed to post message: %w", err)
 	}
+
+	// Notify the other participants of the conversation
+	rows, err := tx.Query(ctx,
+		"SELECT user_id FROM conversation_participants WHERE conversation_id = $1 AND user_id <> $2",
+		conversationID, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation participants: %w", err)
+	}
+	recipients, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan conversation participants: %w", err)
+	}
+	for _, recipientID := range recipients {
+		err := notify(ctx, tx, recipientID, models.NotificationMessageCreated, map[string]interface{}{
+			"conversationId":  conversationID,
+			"messageId":       msg.ID,
+			"senderId":        senderID,
+			"senderFirstName": msg.SenderFirstName,
+			"preview":         messagePreview(content),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = publishEvent(ctx, tx, models.WebhookEventMessageCreated, map[string]interface{}{
+		"messageId":      msg.ID,
+		"conversationId": conversationID,
+		"senderId":       senderID,
+		"content":        content,
+		"createdAt":      msg.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return &msg, nil
 }
 
+// messagePreview shortens a message for display in a notification.
+func messagePreview(content string) string {
+	const maxPreview = 100
+	runes := []rune(content)
+	if len(runes) <= maxPreview {
+		return content
+	}
+	return string(runes[:maxPreview]) + "…"
+}
+
 func (s *PostgresStore) GetMessages(ctx context.Context, conversationID, userID string) ([]models.MessageResponse, error) {
 	// Verify the user is part of the conversation
 	var isParticipant bool
@@ -582,7 +921,7 @@ func (s *PostgresStore) GetMessages(ctx context.Context, conversationID, userID
 		return nil, fmt.Errorf("failed to verify participant: %w", err)
 	}
 	if !isParticipant {
-		return nil, fmt.Errorf("user is not a participant in this conversation")
+		return nil, ErrNotParticipant
 	}
 
 	rows, err := s.dbpool.Query(ctx, `
@@ -668,5 +1007,3 @@ func (s *PostgresStore) GetConversations(ctx context.Context, userID string) ([]
 
 	return conversations, nil
 }
-
-	
\ No newline at end of file
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"masterdom/api/models"
+)
+
+const taskColumns = `id, kind, payload, status, attempts, max_attempts, run_at,
+	locked_by, locked_at, last_error, created_at, updated_at, finished_at`
+
+func scanTask(row pgx.Row) (*models.Task, error) {
+	var t models.Task
+	err := row.Scan(&t.ID, &t.Kind, &t.Payload, &t.Status, &t.Attempts, &t.MaxAttempts, &t.RunAt,
+		&t.LockedBy, &t.LockedAt, &t.LastError, &t.CreatedAt, &t.UpdatedAt, &t.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// enqueueTask inserts a task using db, which may be a transaction so the task
+// is only queued if the surrounding change commits.
+func enqueueTask(ctx context.Context, db querier, task models.NewTask) (int64, error) {
+	payload := []byte("{}")
+	if task.Payload != nil {
+		var err error
+		if payload, err = json.Marshal(task.Payload); err != nil {
+			return 0, fmt.Errorf("failed to encode %s task payload: %w", task.Kind, err)
+		}
+	}
+
+	var taskID int64
+	err := db.QueryRow(ctx, `
+		INSERT INTO tasks (kind, payload, run_at, max_attempts)
+		VALUES ($1, $2, COALESCE($3, NOW()), COALESCE(NULLIF($4, 0), 10))
+		RETURNING id`,
+		task.Kind, payload, task.RunAt, task.MaxAttempts).Scan(&taskID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue %s task: %w", task.Kind, err)
+	}
+	return taskID, nil
+}
+
+// --- Task Queue Implementations ---
+
+func (s *PostgresStore) EnqueueTask(ctx context.Context, task models.NewTask) (int64, error) {
+	return enqueueTask(ctx, s.dbpool, task)
+}
+
+// ClaimTask locks the next due task of one of the given kinds for workerID.
+// Running tasks whose lock is older than lease are treated as abandoned by a
+// crashed worker and claimed again. It returns nil when nothing is due.
+func (s *PostgresStore) ClaimTask(ctx context.Context, kinds []string, workerID string, lease time.Duration) (*models.Task, error) {
+	task, err := scanTask(s.dbpool.QueryRow(ctx, `
+		UPDATE tasks
+		SET status = 'running', attempts = attempts + 1, locked_by = $3, locked_at = NOW()
+		WHERE id = (
+			SELECT id FROM tasks
+			WHERE kind = ANY($1)
+			  AND ((status = 'pending' AND run_at <= NOW())
+			    OR (status = 'running' AND locked_at < NOW() - $2 * INTERVAL '1 second'))
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+taskColumns,
+		kinds, lease.Seconds(), workerID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim task: %w", err)
+	}
+	return task, nil
+}
+
+func (s *PostgresStore) CompleteTask(ctx context.Context, taskID int64) error {
+	_, err := s.dbpool.Exec(ctx, `
+		UPDATE tasks
+		SET status = 'succeeded', finished_at = NOW(), locked_by = NULL, locked_at = NULL, last_error = NULL
+		WHERE id = $1`, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to complete task: %w", err)
+	}
+	return nil
+}
+
+// FailTask records a failed attempt. The task is retried at retryAt, or moved
+// to the dead status when retryAt is nil.
+func (s *PostgresStore) FailTask(ctx context.Context, taskID int64, taskErr string, retryAt *time.Time) error {
+	_, err := s.dbpool.Exec(ctx, `
+		UPDATE tasks
+		SET status = CASE WHEN $3::timestamptz IS NULL THEN 'dead' ELSE 'pending' END,
+			run_at = COALESCE($3, run_at),
+			finished_at = CASE WHEN $3::timestamptz IS NULL THEN NOW() END,
+			last_error = $2, locked_by = NULL, locked_at = NULL
+		WHERE id = $1`, taskID, taskErr, retryAt)
+	if err != nil {
+		return fmt.Errorf("failed to record task failure: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetTasks(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	var clauses []string
+	var args []interface{}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		clauses = append(clauses, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Kind != "" {
+		args = append(args, filter.Kind)
+		clauses = append(clauses, fmt.Sprintf("kind = $%d", len(args)))
+	}
+
+	query := "SELECT " + taskColumns + " FROM tasks"
+	if len(clauses) > 0 {
+		query += " WHERE " + strings.Join(clauses, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := s.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]models.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, rows.Err()
+}
+
+func (s *PostgresStore) GetTask(ctx context.Context, taskID int64) (*models.Task, error) {
+	task, err := scanTask(s.dbpool.QueryRow(ctx, "SELECT "+taskColumns+" FROM tasks WHERE id = $1", taskID))
+	if err != nil {
+		return nil, notFound(fmt.Errorf("failed to get task: %w", err), ErrTaskNotFound)
+	}
+	return task, nil
+}
+
+// RetryTask puts a dead task back into the queue with a fresh set of attempts.
+func (s *PostgresStore) RetryTask(ctx context.Context, taskID int64) error {
+	targetID := strconv.FormatInt(taskID, 10)
+	return s.withAudit(ctx, "task.retry", auditTargetTask, &targetID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE tasks
+			SET status = 'pending', attempts = 0, run_at = NOW(), finished_at = NULL
+			WHERE id = $1 AND status = 'dead'`, taskID)
+		if err != nil {
+			return fmt.Errorf("failed to retry task: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrDeadTaskNotFound
+		}
+		return nil
+	})
+}
+
+// PurgeFinishedTasks deletes succeeded tasks finished before finishedBefore.
+// Dead tasks are kept for inspection until retried.
+func (s *PostgresStore) PurgeFinishedTasks(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	tag, err := s.dbpool.Exec(ctx, "DELETE FROM tasks WHERE status = 'succeeded' AND finished_at < $1", finishedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge finished tasks: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,200 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"masterdom/api/models"
+)
+
+// webhookDeliveryAttempts is how many times an event is sent to a webhook before the delivery task is dead.
+const webhookDeliveryAttempts = 8
+
+const webhookColumns = "id, url, description, events, is_active, created_by, created_at, updated_at"
+
+// publishEvent queues a delivery task for every active webhook subscribed to
+// the event. All deliveries of one event share the event ID.
+func publishEvent(ctx context.Context, db querier, eventType string, data map[string]interface{}) error {
+	_, err := db.Exec(ctx, `
+		INSERT INTO tasks (kind, payload, max_attempts)
+		SELECT $1, jsonb_build_object(
+			'webhookId', w.id,
+			'event', jsonb_build_object('id', e.id, 'type', $2::text, 'createdAt', NOW(), 'data', $3::jsonb)
+		), $4
+		FROM webhooks w, (SELECT uuid_generate_v4() AS id) e
+		WHERE w.is_active AND $2 = ANY(w.events)`,
+		models.TaskKindWebhookDelivery, eventType, data, webhookDeliveryAttempts)
+	if err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// --- Webhook Implementations ---
+
+func scanWebhook(row pgx.Row) (*models.Webhook, error) {
+	var w models.Webhook
+	if err := row.Scan(&w.ID, &w.URL, &w.Description, &w.Events, &w.IsActive, &w.CreatedBy, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (s *PostgresStore) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	rows, err := s.dbpool.Query(ctx, "SELECT "+webhookColumns+" FROM webhooks ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]models.Webhook, 0)
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, *w)
+	}
+	return webhooks, rows.Err()
+}
+
+// GetWebhook returns the webhook including its signing secret.
+func (s *PostgresStore) GetWebhook(ctx context.Context, webhookID int) (*models.Webhook, error) {
+	var w models.Webhook
+	err := s.dbpool.QueryRow(ctx, "SELECT "+webhookColumns+", secret FROM webhooks WHERE id = $1", webhookID).Scan(
+		&w.ID, &w.URL, &w.Description, &w.Events, &w.IsActive, &w.CreatedBy, &w.CreatedAt, &w.UpdatedAt, &w.Secret)
+	if err != nil {
+		return nil, notFound(fmt.Errorf("failed to get webhook: %w", err), ErrWebhookNotFound)
+	}
+	return &w, nil
+}
+
+func (s *PostgresStore) CreateWebhook(ctx context.Context, payload models.WebhookPayload, secret, createdBy string) (int, error) {
+	var webhookID int
+	var targetID string
+	err := s.withAudit(ctx, "webhook.create", auditTargetWebhook, &targetID, func(tx pgx.Tx) error {
+		isActive := true
+		if payload.IsActive != nil {
+			isActive = *payload.IsActive
+		}
+		err := tx.QueryRow(ctx, `
+			INSERT INTO webhooks (url, description, secret, events, is_active, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			payload.URL, payload.Description, secret, payload.Events, isActive, createdBy).Scan(&webhookID)
+		targetID = strconv.Itoa(webhookID)
+		return err
+	})
+	return webhookID, err
+}
+
+func (s *PostgresStore) UpdateWebhook(ctx context.Context, webhookID int, payload models.WebhookPayload) error {
+	targetID := strconv.Itoa(webhookID)
+	return s.withAudit(ctx, "webhook.update", auditTargetWebhook, &targetID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE webhooks SET url = $1, description = $2, events = $3, is_active = COALESCE($4, is_active)
+			WHERE id = $5`,
+			payload.URL, payload.Description, payload.Events, payload.IsActive, webhookID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrWebhookNotFound
+		}
+		return nil
+	})
+}
+
+func (s *PostgresStore) RotateWebhookSecret(ctx context.Context, webhookID int, secret string) error {
+	targetID := strconv.Itoa(webhookID)
+	return s.withAudit(ctx, "webhook.rotate_secret", auditTargetWebhook, &targetID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "UPDATE webhooks SET secret = $1 WHERE id = $2", secret, webhookID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrWebhookNotFound
+		}
+		return nil
+	})
+}
+
+// DeleteWebhook removes the webhook with its delivery log. Pending deliveries
+// fail permanently once their task runs.
+func (s *PostgresStore) DeleteWebhook(ctx context.Context, webhookID int) error {
+	targetID := strconv.Itoa(webhookID)
+	return s.withAudit(ctx, "webhook.delete", auditTargetWebhook, &targetID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "DELETE FROM webhooks WHERE id = $1", webhookID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrWebhookNotFound
+		}
+		return nil
+	})
+}
+
+// EnqueueWebhookTest queues a test event for the webhook, whether or not it is
+// active or subscribed, and returns the event ID.
+func (s *PostgresStore) EnqueueWebhookTest(ctx context.Context, webhookID int) (string, error) {
+	var eventID string
+	err := s.dbpool.QueryRow(ctx, `
+		INSERT INTO tasks (kind, payload, max_attempts)
+		SELECT $1, jsonb_build_object(
+			'webhookId', w.id,
+			'event', jsonb_build_object('id', e.id, 'type', $2::text, 'createdAt', NOW(), 'data', jsonb_build_object('webhookId', w.id))
+		), 1
+		FROM webhooks w, (SELECT uuid_generate_v4() AS id) e
+		WHERE w.id = $3
+		RETURNING payload -> 'event' ->> 'id'`,
+		models.TaskKindWebhookDelivery, models.WebhookEventTest, webhookID).Scan(&eventID)
+	if err != nil {
+		return "", notFound(fmt.Errorf("failed to queue webhook test: %w", err), ErrWebhookNotFound)
+	}
+	return eventID, nil
+}
+
+func (s *PostgresStore) RecordWebhookDelivery(ctx context.Context, d models.WebhookDelivery) error {
+	_, err := s.dbpool.Exec(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, attempt, success, status_code, response_body, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		d.WebhookID, d.EventID, d.EventType, d.Attempt, d.Success, d.StatusCode, d.ResponseBody, d.Error, d.DurationMs)
+	var pgErr *pgconn.PgError
+	// The webhook may have been deleted while the request was in flight
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetWebhookDeliveries(ctx context.Context, webhookID, limit, offset int) ([]models.WebhookDelivery, error) {
+	rows, err := s.dbpool.Query(ctx, `
+		SELECT id, webhook_id, event_id, event_type, attempt, success, status_code, response_body, error, duration_ms, created_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, webhookID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Attempt, &d.Success,
+			&d.StatusCode, &d.ResponseBody, &d.Error, &d.DurationMs, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
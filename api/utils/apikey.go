@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// apiKeyPrefix отличает ключи API от других секретов, например при поиске утечек в коде
+const apiKeyPrefix = "mdk_"
+
+// GenerateAPIKey создает ключ вида mdk_<префикс>_<секрет> и возвращает его вместе с префиксом.
+// Префикс из 64 бит практически не повторяется, но уникальность все равно проверяет база.
+func GenerateAPIKey() (key, prefix string, err error) {
+	b := make([]byte, 40)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	prefix = hex.EncodeToString(b[:8])
+	return apiKeyPrefix + prefix + "_" + hex.EncodeToString(b[8:]), prefix, nil
+}
+
+// ParseAPIKeyPrefix извлекает префикс из ключа.
+func ParseAPIKeyPrefix(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, apiKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	// Ключи, выданные до удлинения префикса, имеют префикс из 8 символов
+	if !ok || len(prefix) != 16 && len(prefix) != 8 || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// HashAPIKey возвращает SHA-256 ключа в hex. Ключ содержит 256 бит случайных
+// данных, поэтому медленное хеширование, как для паролей, не требуется.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale - язык, на котором заведены исходные данные
+const DefaultLocale = "ru"
+
+// SupportedLocales - языки, поддерживаемые веб-клиентом
+var SupportedLocales = []string{"ru", "en"}
+
+// IsSupportedLocale сообщает, поддерживается ли язык.
+func IsSupportedLocale(locale string) bool {
+	for _, l := range SupportedLocales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAcceptLanguage выбирает наиболее предпочтительный поддерживаемый язык
+// из заголовка Accept-Language (например, "en-US,en;q=0.9,ru;q=0.8").
+// Если ни один язык не подходит, возвращается DefaultLocale.
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		// Региональный вариант сводим к базовому языку: en-US -> en
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if IsSupportedLocale(base) && q > 0 {
+			candidates = append(candidates, candidate{base, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return DefaultLocale
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].locale
+}
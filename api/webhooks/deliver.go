@@ -0,0 +1,130 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"masterdom/api/models"
+	"masterdom/api/queue"
+	"masterdom/api/store"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+	// maxLoggedResponse - сколько байт ответа получателя сохраняется в журнале доставки
+	maxLoggedResponse = 1024
+)
+
+// Заголовки запроса с событием
+const (
+	HeaderEvent     = "X-MasterDom-Event"
+	HeaderDelivery  = "X-MasterDom-Delivery"
+	HeaderTimestamp = "X-MasterDom-Timestamp"
+	HeaderSignature = "X-MasterDom-Signature"
+)
+
+// Sign возвращает подпись тела запроса: "sha256=" и HMAC-SHA256 от
+// "<timestamp>.<body>" в hex. Получатель проверяет подпись тем же ключом
+// и отклоняет запросы со старым timestamp, защищаясь от повторов.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Deliverer отправляет события получателям. Используется как обработчик
+// фоновых задач вида models.TaskKindWebhookDelivery; повторы с задержкой
+// выполняет очередь.
+type Deliverer struct {
+	store  store.Store
+	client *http.Client
+}
+
+func NewDeliverer(s store.Store) *Deliverer {
+	return &Deliverer{store: s, client: &http.Client{Timeout: requestTimeout}}
+}
+
+func (d *Deliverer) Handle(ctx context.Context, task models.Task) error {
+	var delivery models.WebhookDeliveryTask
+	if err := json.Unmarshal(task.Payload, &delivery); err != nil {
+		return queue.Permanent(fmt.Errorf("invalid webhook task payload: %w", err))
+	}
+
+	webhook, err := d.store.GetWebhook(ctx, delivery.WebhookID)
+	if errors.Is(err, store.ErrWebhookNotFound) {
+		return queue.Permanent(fmt.Errorf("webhook %d no longer exists", delivery.WebhookID))
+	}
+	if err != nil {
+		return err
+	}
+	if !webhook.IsActive && delivery.Event.Type != models.WebhookEventTest {
+		return queue.Permanent(fmt.Errorf("webhook %d is disabled", webhook.ID))
+	}
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return queue.Permanent(fmt.Errorf("failed to encode event: %w", err))
+	}
+
+	record := models.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   delivery.Event.ID,
+		EventType: delivery.Event.Type,
+		Attempt:   task.Attempts,
+	}
+	start := time.Now()
+	sendErr := d.send(ctx, webhook, delivery.Event, body, &record)
+	record.DurationMs = int(time.Since(start).Milliseconds())
+	record.Success = sendErr == nil
+	if sendErr != nil {
+		msg := sendErr.Error()
+		record.Error = &msg
+	}
+	if err := d.store.RecordWebhookDelivery(ctx, record); err != nil {
+		return errors.Join(sendErr, err)
+	}
+	return sendErr
+}
+
+func (d *Deliverer) send(ctx context.Context, webhook *models.Webhook, event models.WebhookEvent, body []byte, record *models.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return queue.Permanent(fmt.Errorf("invalid webhook request: %w", err))
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MasterDom-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, event.Type)
+	req.Header.Set(HeaderDelivery, event.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	record.StatusCode = &resp.StatusCode
+	if excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxLoggedResponse)); len(excerpt) > 0 {
+		text := strings.ToValidUTF8(string(excerpt), "")
+		record.ResponseBody = &text
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -72,7 +72,7 @@ export function OfferApplicantsModal({ open, onClose, offerId }: OfferApplicants
       });
       if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(errorData.details || errorData.error || 'Не удалось начать чат');
+        throw new Error(errorData.message || errorData.error || 'Не удалось начать чат');
       }
       const { conversationId } = await response.json();
       navigate(`/chats/${conversationId}`);
@@ -35,7 +35,7 @@ export function OfferResponseModal({ open, onClose, offerId, onResponseSent }: O
       });
       if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(errorData.details || errorData.error || t('offersPage.sendResponseError'));
+        throw new Error(errorData.message || errorData.error || t('offersPage.sendResponseError'));
       }
       onResponseSent();
       onClose();
@@ -33,7 +33,7 @@ export function LoginForm() {
         let errorMessage = t('loginPage.errors.default');
         try {
           const errorData = JSON.parse(errorText);
-          errorMessage = errorData.error || errorData.details || errorMessage;
+          errorMessage = errorData.message || errorData.error || errorMessage;
         } catch (jsonError) {
           errorMessage = errorText || errorMessage; // Use raw text if not JSON
         }
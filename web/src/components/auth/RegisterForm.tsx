@@ -42,7 +42,7 @@ export function RegisterForm({ onSuccess }: RegisterFormProps) {
         let errorMessage = t('registerPage.errors.default');
         try {
           const errorData = JSON.parse(errorText);
-          errorMessage = errorData.details || errorData.error || errorMessage;
+          errorMessage = errorData.message || errorData.error || errorMessage;
         } catch (jsonError) {
           errorMessage = errorText || errorMessage; // Use raw text if not JSON
         }
@@ -76,7 +76,7 @@ export function AdminPage() {
   const handleApiResponse = async (response: Response, successMessage: string) => {
     if (!response.ok) {
       const errorData = await response.json().catch(() => ({ error: 'Operation failed' }));
-      throw new Error(errorData.details || errorData.error);
+      throw new Error(errorData.message || errorData.error);
     }
     setNotification({ message: successMessage, severity: 'success' });
     fetchData();
@@ -63,7 +63,7 @@ function CreateOfferForm({ open, onClose, onOfferCreated }: { open: boolean, onC
       if (!response.ok) {
         const text = await response.text();
         const errorData = JSON.parse(text);
-        throw new Error(errorData.details || errorData.error || 'Failed to create offer');
+        throw new Error(errorData.message || errorData.error || 'Failed to create offer');
       }
       onOfferCreated(selectedOfferType);
       onClose();
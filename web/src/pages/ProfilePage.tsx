@@ -61,7 +61,7 @@ export function ProfilePage() {
         }),
       });
       const data = await response.json();
-      if (!response.ok) throw new Error(data.details || data.error || 'Failed to update profile.');
+      if (!response.ok) throw new Error(data.message || data.error || 'Failed to update profile.');
       setMessage(t('profilePage.successMessage'));
     } catch (err) {
       setError(err instanceof Error ? err.message : 'An unknown error occurred');
@@ -28,7 +28,7 @@ export function RegisterClientPage() {
       const data = await response.json();
 
       if (!response.ok) {
-        throw new Error(data.details || data.error || 'Failed to register');
+        throw new Error(data.message || data.error || 'Failed to register');
       }
 
       setMessage(`Client registered successfully! User ID: ${data.userId}`);
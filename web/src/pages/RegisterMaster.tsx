@@ -33,7 +33,7 @@ export function RegisterMasterPage() {
 
       const data = await response.json();
       if (!response.ok) {
-        throw new Error(data.details || data.error || 'Failed to register');
+        throw new Error(data.message || data.error || 'Failed to register');
       }
 
       setMessage(`Master registered successfully! User ID: ${data.userId}`);